@@ -0,0 +1,68 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+// Builder creates an error with many fields in one readable expression:
+//
+//	return ergo.Build("billing", ENotFound).
+//		Info("id", id).
+//		Cause(err).
+//		Severity(ergo.SeverityWarn).
+//		Err()
+type Builder struct {
+	domain string
+	code   ErrCode
+	opts   []Option
+}
+
+// Build starts building an error, see NewE.
+func Build(domain string, code ErrCode) *Builder {
+	return &Builder{domain: domain, code: code}
+}
+
+// Info adds a key and value to the Info of the error.
+func (b *Builder) Info(key string, value interface{}) *Builder {
+	return b.With(WithInfo(key, value))
+}
+
+// Cause sets the inner error, see Chain.
+func (b *Builder) Cause(err error) *Builder {
+	return b.With(WithInner(err))
+}
+
+// Severity sets the severity of the error.
+func (b *Builder) Severity(s Severity) *Builder {
+	return b.With(WithSeverity(s))
+}
+
+// With applies other options, such as WithStackDepth.
+func (b *Builder) With(opts ...Option) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Err creates the error. The stack starts at the caller.
+func (b *Builder) Err() *Error {
+	return NewE(b.domain, b.code, append(b.opts[:len(b.opts):len(b.opts)], WithSkip(1))...)
+}
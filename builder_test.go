@@ -0,0 +1,48 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"errors"
+	gc "github.com/motain/gocheck"
+	"io"
+)
+
+func (t *TestSuite) TestBuild(c *gc.C) {
+	err := Build("ergo", EMyErrorArgs).
+		Info("arg1", "a").
+		Info("arg2", 2).
+		Cause(io.EOF).
+		Severity(SeverityWarn).
+		Err()
+	c.Check(err.Message(), gc.Equals, NewError(EMyErrorArgs, "arg1", "a", "arg2", 2).Message())
+	c.Check(err.Severity, gc.Equals, SeverityWarn)
+	c.Check(errors.Is(err, io.EOF), gc.Equals, true)
+	c.Check(err.Stack.Frames()[0].Function, gc.Matches, ".*TestBuild$")
+	c.Check(err.Inner.Stack.Frames()[0].Function, gc.Matches, ".*TestBuild$")
+
+	err = Build("ergo", EMyError0).With(WithoutStack()).Err()
+	c.Check(err.Stack, gc.IsNil)
+	c.Check(err.Inner, gc.IsNil)
+}
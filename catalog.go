@@ -0,0 +1,275 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Catalog is a domain described outside of Go source, so that its
+// messages can be edited by non-developers. For example, in JSON:
+//
+//	{
+//	  "domain": "db",
+//	  "codes": [
+//	    {"code": 1, "name": "ENotFound", "message": "The {{.name}} was not found"}
+//	  ]
+//	}
+//
+// A catalog with a locale holds translations, see DomainLocale.
+type Catalog struct {
+	Domain string         `json:"domain" yaml:"domain" toml:"domain"`
+	Locale string         `json:"locale,omitempty" yaml:"locale,omitempty" toml:"locale,omitempty"`
+	Codes  []CatalogEntry `json:"codes" yaml:"codes" toml:"codes"`
+}
+
+// CatalogEntry describes an error code of a Catalog.
+type CatalogEntry struct {
+	Code ErrCode `json:"code" yaml:"code" toml:"code"`
+
+	// The symbolic name of the code, such as "ENotFound", see NameCodes.
+	Name string `json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty"`
+
+	// The message format, as given to Domain.
+	Message string `json:"message" yaml:"message" toml:"message"`
+
+	// The HTTP status of the code, if any, see HTTPStatus.
+	Status int `json:"status,omitempty" yaml:"status,omitempty" toml:"status,omitempty"`
+
+	// The severity of errors created by generated constructors,
+	// such as "warn", see ParseSeverity.
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty" toml:"severity,omitempty"`
+
+	// What users or operators can do about the error, for documentation.
+	Remediation string `json:"remediation,omitempty" yaml:"remediation,omitempty" toml:"remediation,omitempty"`
+
+	// Additional data for tools, ignored by ergo.
+	Metadata map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty" toml:"metadata,omitempty"`
+}
+
+// CatalogDecoder decodes a catalog in some format.
+type CatalogDecoder func(r io.Reader, cat *Catalog) error
+
+var catalogFormats sync.Map
+
+func init() {
+	RegisterCatalogFormat("json", func(r io.Reader, cat *Catalog) error {
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		return dec.Decode(cat)
+	})
+}
+
+// RegisterCatalogFormat makes a catalog format available to the loaders,
+// under its name and file extension, such as "yaml".
+// JSON is always available; the yamlergo and tomlergo packages
+// register YAML and TOML when imported.
+func RegisterCatalogFormat(format string, dec CatalogDecoder) {
+	catalogFormats.Store(strings.ToLower(format), dec)
+}
+
+// LoadDomainReader decodes a catalog in "format" and registers it,
+// see Catalog.Register.
+func LoadDomainReader(r io.Reader, format string) (*Catalog, error) {
+	cat, err := DecodeCatalog(r, format)
+	if err != nil {
+		return nil, err
+	}
+	return cat, cat.Register()
+}
+
+// DecodeCatalog decodes a catalog in "format" without registering it,
+// for instance to generate code from it.
+func DecodeCatalog(r io.Reader, format string) (*Catalog, error) {
+	dec, ok := catalogFormats.Load(strings.ToLower(format))
+	if !ok {
+		return nil, fmt.Errorf("ergo: unknown catalog format %q", format)
+	}
+	cat := new(Catalog)
+	if err := dec.(CatalogDecoder)(r, cat); err != nil {
+		return nil, fmt.Errorf("ergo: invalid %v catalog: %v", format, err)
+	}
+	return cat, nil
+}
+
+// catalogSource is a catalog file, which is read from the
+// operating system if there is no file system.
+type catalogSource struct {
+	fsys fs.FS
+	name string
+}
+
+// decode reads the catalog, whose format is given by its extension.
+func (src catalogSource) decode() (*Catalog, error) {
+	var f io.ReadCloser
+	var err error
+	if src.fsys == nil {
+		f, err = os.Open(src.name)
+	} else {
+		f, err = src.fsys.Open(src.name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cat, err := DecodeCatalog(f, strings.TrimPrefix(path.Ext(src.name), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", src.name, err)
+	}
+	return cat, nil
+}
+
+// load reads and registers the catalog,
+// remembering its source for ReloadDomain.
+func (src catalogSource) load() (*Catalog, error) {
+	cat, err := src.decode()
+	if err != nil {
+		return nil, err
+	}
+	if err := cat.Register(); err != nil {
+		return nil, fmt.Errorf("%v: %v", src.name, err)
+	}
+	addSource(cat.Domain, src)
+	return cat, nil
+}
+
+// LoadDomainFile loads a catalog file, whose format is given
+// by its extension, see LoadDomainReader.
+// The domain may later be reloaded from the file, see ReloadDomain.
+func LoadDomainFile(path string) (*Catalog, error) {
+	return catalogSource{name: filepath.ToSlash(path)}.load()
+}
+
+// LoadDomainFS loads every catalog of a file system matching a pattern,
+// see fs.Glob and LoadDomainFile. Applications can thus embed their
+// catalogs, including translations, and register them at startup:
+//
+//	//go:embed errors/*.json
+//	var catalogs embed.FS
+//
+//	ergo.LoadDomainFS(catalogs, "errors/*.json")
+//
+// Loading stops at the first invalid catalog.
+func LoadDomainFS(fsys fs.FS, pattern string) ([]*Catalog, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	var cats []*Catalog
+	for _, name := range names {
+		cat, err := catalogSource{fsys: fsys, name: name}.load()
+		if err != nil {
+			return cats, err
+		}
+		cats = append(cats, cat)
+	}
+	return cats, nil
+}
+
+// DomainMap returns the message formats of the catalog.
+func (cat *Catalog) DomainMap() (DomainMap, error) {
+	if cat.Domain == "" {
+		return nil, fmt.Errorf("ergo: catalog has no domain")
+	}
+	domain := make(DomainMap, len(cat.Codes))
+	for _, entry := range cat.Codes {
+		if _, ok := domain[entry.Code]; ok {
+			return nil, fmt.Errorf("ergo: [%v:%d] is defined twice", cat.Domain, entry.Code)
+		}
+		if _, err := ParseSeverity(entry.Severity); err != nil {
+			return nil, fmt.Errorf("ergo: [%v:%d] has %v", cat.Domain, entry.Code, err)
+		}
+		domain[entry.Code] = entry.Message
+	}
+	return domain, nil
+}
+
+// compile parses the message formats of the catalog.
+func (cat *Catalog) compile() (DomainMap, templates, error) {
+	domain, err := cat.DomainMap()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkRange(cat.Domain, domain, reservedRange(cat.Domain)); err != nil {
+		return nil, nil, err
+	}
+	locale := normalizeLocale(cat.Locale)
+	if locale == "" {
+		locale = "en"
+	}
+	tmpls, err := parseTemplates(cat.Domain, funcsFor(cat.Domain, locale), domain)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ergo: %v", err)
+	}
+	if err := checkKeys(cat.Domain, tmpls, declaredKeys(cat.Domain), cat.Locale == ""); err != nil {
+		return nil, nil, err
+	}
+	return domain, tmpls, nil
+}
+
+// Register defines the domain of the catalog, see Domain,
+// or its translations if it has a locale, see DomainLocale.
+// Unlike those, it reports invalid catalogs instead of panicking.
+func (cat *Catalog) Register() error {
+	domain, tmpls, err := cat.compile()
+	if err != nil {
+		return err
+	}
+	if cat.Locale != "" {
+		key := localeKey{domain: cat.Domain, locale: normalizeLocale(cat.Locale)}
+		translations.Store(key, tmpls)
+		return nil
+	}
+	if _, ok := domains.Load(cat.Domain); ok {
+		def, ok := defined.Load(cat.Domain)
+		if !ok || !identicalDomainsAllowed() || !sameDomain(def.(*definition).catalog, domain) {
+			return fmt.Errorf("ergo: domain %v is already defined", cat.Domain)
+		}
+	}
+	Domain(cat.Domain, domain)
+	cat.nameCodes()
+	return nil
+}
+
+// nameCodes registers the names and HTTP statuses of the codes of the catalog.
+func (cat *Catalog) nameCodes() {
+	names := make(map[ErrCode]string)
+	for _, entry := range cat.Codes {
+		if entry.Name != "" {
+			names[entry.Code] = entry.Name
+		}
+		if entry.Status != 0 {
+			HTTPStatus(cat.Domain, entry.Code, entry.Status)
+		}
+	}
+	NameCodes(cat.Domain, names)
+}
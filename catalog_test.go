@@ -0,0 +1,123 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	gc "github.com/motain/gocheck"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing/fstest"
+)
+
+func (t *TestSuite) TestLoadDomainReader(c *gc.C) {
+	cat, err := LoadDomainReader(strings.NewReader(`{
+	  "domain": "catalog",
+	  "codes": [
+	    {"code": 1, "name": "ENotFound", "message": "The {{.name}} was not found",
+	     "metadata": {"status": 404}},
+	    {"code": 2, "message": "{{.count}} {{plural .count \"file\" \"files\"}}"}
+	  ]
+	}`), "JSON")
+	c.Assert(err, gc.IsNil)
+	c.Check(cat.Codes[0].Name, gc.Equals, "ENotFound")
+	c.Check(cat.Codes[0].Metadata, gc.DeepEquals, map[string]interface{}{"status": 404.0})
+	c.Check(New(0, "catalog", 1, "name", "x").Message(), gc.Equals, "The x was not found")
+	c.Check(New(0, "catalog", 2, "count", 1).Message(), gc.Equals, "1 file")
+
+	_, err = LoadDomainReader(strings.NewReader(`{
+	  "domain": "catalog", "locale": "de",
+	  "codes": [{"code": 1, "message": "{{.name}} wurde nicht gefunden"}]
+	}`), "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(New(0, "catalog", 1, "name", "x").MessageIn("de"), gc.Equals, "x wurde nicht gefunden")
+
+	for _, test := range []struct{ doc, format, err string }{
+		{`{}`, "ini", `ergo: unknown catalog format "ini"`},
+		{`{"domian": "x"}`, "json", `ergo: invalid json catalog: .*unknown field "domian"`},
+		{`{"codes": []}`, "json", "ergo: catalog has no domain"},
+		{`{"domain": "catalog2", "codes": [{"code": 1}, {"code": 1}]}`, "json",
+			`ergo: \[catalog2:1\] is defined twice`},
+		{`{"domain": "catalog2", "codes": [{"code": 1, "message": "{{.x"}]}`, "json",
+			"ergo: template: \\[catalog2:1\\]:1: unclosed action"},
+		{`{"domain": "catalog2", "codes": [{"code": 1, "severity": "bad"}]}`, "json",
+			`ergo: \[catalog2:1\] has unknown severity "bad"`},
+		{`{"domain": "catalog", "codes": []}`, "json", "ergo: domain catalog is already defined"},
+	} {
+		_, err := LoadDomainReader(strings.NewReader(test.doc), test.format)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (t *TestSuite) TestLoadDomainFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "file.json")
+	c.Assert(os.WriteFile(path, []byte(`{"domain": "catalogfile",
+	  "codes": [{"code": 0, "message": "Loaded"}]}`), 0644), gc.IsNil)
+	_, err := LoadDomainFile(path)
+	c.Assert(err, gc.IsNil)
+	c.Check(New(0, "catalogfile", 0).Message(), gc.Equals, "Loaded")
+
+	_, err = LoadDomainFile(path)
+	c.Check(err, gc.ErrorMatches, ".*file.json: ergo: domain catalogfile is already defined")
+	_, err = LoadDomainFile(path + ".missing")
+	c.Check(os.IsNotExist(err), gc.Equals, true)
+}
+
+func (t *TestSuite) TestLoadDomainFS(c *gc.C) {
+	fsys := fstest.MapFS{
+		"errors/fs.json": {Data: []byte(`{"domain": "fs",
+		  "codes": [{"code": 0, "message": "Embedded"}]}`)},
+		"errors/fs.de.json": {Data: []byte(`{"domain": "fs", "locale": "de",
+		  "codes": [{"code": 0, "message": "Eingebettet"}]}`)},
+		"errors/README.md": {Data: []byte("Catalogs")},
+	}
+	cats, err := LoadDomainFS(fsys, "errors/*.json")
+	c.Assert(err, gc.IsNil)
+	c.Check(cats, gc.HasLen, 2)
+	c.Check(New(0, "fs", 0).Message(), gc.Equals, "Embedded")
+	c.Check(New(0, "fs", 0).MessageIn("de"), gc.Equals, "Eingebettet")
+
+	fsys["errors/fs2.json"] = &fstest.MapFile{Data: []byte(`{"domain": "fs2"}`)}
+	cats, err = LoadDomainFS(fsys, "errors/fs2*")
+	c.Check(err, gc.IsNil)
+	c.Check(cats, gc.HasLen, 1)
+	cats, err = LoadDomainFS(fsys, "errors/*")
+	c.Check(err, gc.ErrorMatches, `errors/README.md: ergo: unknown catalog format "md"`)
+	c.Check(cats, gc.HasLen, 0)
+	_, err = LoadDomainFS(fsys, "[")
+	c.Check(err, gc.NotNil)
+}
+
+func (t *TestSuite) TestCatalogStatus(c *gc.C) {
+	defer RemoveDomain("catstatus")
+	cat, err := DecodeCatalog(strings.NewReader(`{"domain": "catstatus", "codes": [
+	  {"code": 1, "message": "Missing", "status": 404, "severity": "warn"}
+	]}`), "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(cat.Codes[0].Severity, gc.Equals, "warn")
+	c.Check(StatusFor(New(0, "catstatus", 1)), gc.Equals, DefaultHTTPStatus)
+	c.Assert(cat.Register(), gc.IsNil)
+	c.Check(StatusFor(New(0, "catstatus", 1)), gc.Equals, 404)
+	HTTPStatus("catstatus", 1, 0)
+}
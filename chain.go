@@ -0,0 +1,145 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// DefaultChainDepth is the default maximum number of links
+// followed through a chain, see SetMaxChainDepth.
+const DefaultChainDepth = 100
+
+var maxChainDepth int32 = DefaultChainDepth
+
+// SetMaxChainDepth sets the maximum number of links followed through
+// a chain by Error(), Oneline(), Chain(), Cause(), Walk and every other
+// traversal, so that absurdly deep chains, or cycles created by assigning
+// Inner or Causes, cannot make them run forever. The links of Causes are
+// one link deeper than the error they belong to. Links beyond the maximum
+// are left out. Unbounded follows every link.
+func SetMaxChainDepth(n int) {
+	atomic.StoreInt32(&maxChainDepth, int32(n))
+}
+
+// maxDepth returns the maximum chain depth, see SetMaxChainDepth.
+func maxDepth() int {
+	return int(atomic.LoadInt32(&maxChainDepth))
+}
+
+// appendLinks appends the links of the chain ending at this error to
+// "links", outermost first. The outermost link is "depth" links away from
+// where the traversal started. It reports whether links were left out
+// because of the maximum depth.
+func (err *Error) appendLinks(links []*Error, depth int) ([]*Error, bool) {
+	max := maxDepth()
+	for link := err; link != nil; link = link.Inner {
+		if max >= 0 && depth >= max {
+			return links, true
+		}
+		links = append(links, link)
+		depth++
+	}
+	return links, false
+}
+
+// Chain returns the links of the chain ending at this error,
+// innermost first, as Error() renders them. Additional Causes are not
+// included, see Walk.
+func (err *Error) Chain() []*Error {
+	links, _ := err.appendLinks(nil, 0)
+	for i, j := 0, len(links)-1; i < j; i, j = i+1, j-1 {
+		links[i], links[j] = links[j], links[i]
+	}
+	return links
+}
+
+// checkCycle panics if linking "inner" to "err" would create a cycle,
+// through Inner or Causes.
+func checkCycle(inner, err *Error) {
+	inner.walk(func(link *Error) bool {
+		if link == err {
+			panic(fmt.Sprintf("ergo: [%v:%d] cannot be chained to itself", err.Domain, err.Code))
+		}
+		return true
+	}, 0, maxDepth())
+}
+
+// Walk calls "fn" for every link of the chain of "err", outermost first,
+// until "fn" returns false. The chains of additional Causes are walked
+// after their link. If "err" is not an Error, the first Error it wraps
+// is walked, if any. Links beyond the maximum depth, counted through
+// Causes as well, are left out, see SetMaxChainDepth.
+//
+//	ergo.Walk(err, func(link *ergo.Error) bool {
+//		if link.Domain == "db" {
+//			found = link
+//		}
+//		return found == nil
+//	})
+func Walk(err error, fn func(*Error) bool) {
+	var ergo *Error
+	if errors.As(err, &ergo) {
+		ergo.walk(fn, 0, maxDepth())
+	}
+}
+
+// walkLinks calls "fn" for every Error of the chain of "err", outermost
+// first, until "fn" returns false. Unlike Walk, the chain is followed with
+// errors.Unwrap, through standard errors as well, and Causes are left out.
+// Links beyond the maximum depth are left out, see SetMaxChainDepth.
+func walkLinks(err error, fn func(*Error) bool) {
+	max := maxDepth()
+	for depth := 0; err != nil && (max < 0 || depth < max); depth++ {
+		ergo, ok := err.(*Error)
+		if ok && (ergo == nil || !fn(ergo)) {
+			return
+		}
+		err = errors.Unwrap(err)
+	}
+}
+
+// walk walks the chain ending at this error, whose outermost link is
+// "depth" links away from where the walk started. The chains of Causes
+// are one link deeper than their link, so that cycles through Causes
+// stop at the maximum depth as well.
+func (err *Error) walk(fn func(*Error) bool, depth, max int) bool {
+	for link := err; link != nil; link = link.Inner {
+		if max >= 0 && depth >= max {
+			return true
+		}
+		if !fn(link) {
+			return false
+		}
+		for _, cause := range link.Causes {
+			if !cause.walk(fn, depth+1, max) {
+				return false
+			}
+		}
+		depth++
+	}
+	return true
+}
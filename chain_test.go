@@ -0,0 +1,161 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"io"
+)
+
+func (t *TestSuite) TestChainCycle(c *gc.C) {
+	outer := NewError(EMyError0)
+	middle := NewError(EMyError1)
+	c.Check(Chain(outer, middle), gc.Equals, middle)
+	c.Check(func() { Chain(middle, outer) }, gc.PanicMatches,
+		`ergo: \[ergo:0\] cannot be chained to itself`)
+	c.Check(func() { Chain(outer, outer) }, gc.PanicMatches,
+		`ergo: \[ergo:0\] cannot be chained to itself`)
+	c.Check(func() { Chain(Join(outer, io.EOF), outer) }, gc.PanicMatches,
+		`ergo: \[ergo:0\] cannot be chained to itself`)
+	c.Check(outer.Inner, gc.IsNil)
+}
+
+func (t *TestSuite) TestCausesCycle(c *gc.C) {
+	defer SetMaxChainDepth(DefaultChainDepth)
+	SetMaxChainDepth(3)
+
+	// A cycle created by assigning Causes directly.
+	err := NewError(EMyError0, WithoutStack())
+	err.Causes = []*Error{err}
+	c.Check(err.Error(), gc.Equals, "...\n[ergo:0] My error 0\n\n"+
+		"[ergo:0] My error 0\n\n[ergo:0] My error 0\n")
+	c.Check(err.Oneline(), gc.Equals, "[ergo:0] My error 0 <- ([ergo:0] My error 0 <- ([ergo:0] My error 0 <- (...)))")
+	c.Check(fmt.Sprintf("%#v", err), gc.Equals, `&ergo.Error{Domain:"ergo", Code:0, Causes:[]*ergo.Error{`+
+		`&ergo.Error{Domain:"ergo", Code:0, Causes:[]*ergo.Error{`+
+		`&ergo.Error{Domain:"ergo", Code:0, Causes:[]*ergo.Error{...}}}}}}`)
+	c.Check(errors.Is(err, io.EOF), gc.Equals, false)
+	var target *json.SyntaxError
+	c.Check(errors.As(err, &target), gc.Equals, false)
+}
+
+func (t *TestSuite) TestMaxChainDepth(c *gc.C) {
+	defer SetMaxChainDepth(DefaultChainDepth)
+
+	first := NewError(EMyError0, WithoutStack())
+	second := NewError(EMyError1, WithoutStack())
+	third := NewError(EMyErrorArgs, "name", "x", WithoutStack())
+	Chain(first, second)
+	Chain(second, third)
+	c.Check(Cause(third), gc.Equals, first)
+
+	SetMaxChainDepth(2)
+	c.Check(Cause(third), gc.Equals, second)
+	c.Check(third.Error(), gc.Equals, "...\n[ergo:1] My error 1\n\n[ergo:2] The x failed\n")
+	c.Check(third.Oneline(), gc.Equals, "[ergo:2] The x failed <- [ergo:1] My error 1 <- ...")
+
+	// A cycle created by assigning Inner directly.
+	first.Inner = third
+	c.Check(Cause(first), gc.Equals, third)
+	c.Check(first.Oneline(), gc.Equals, "[ergo:0] My error 0 <- [ergo:2] The x failed <- ...")
+
+	SetMaxChainDepth(Unbounded)
+	first.Inner = nil
+	c.Check(Cause(Chain(io.EOF, first)), gc.Equals, first.Inner)
+}
+
+func (t *TestSuite) TestInnerCycle(c *gc.C) {
+	defer SetMaxChainDepth(DefaultChainDepth)
+	SetMaxChainDepth(3)
+
+	// A cycle created by assigning Inner directly.
+	err := &Error{Domain: "x", Code: 1}
+	err.Inner = err
+	c.Check(StatusFor(err), gc.Equals, DefaultHTTPStatus)
+	c.Check(ExitCodeFor(err), gc.Equals, DefaultExitCode)
+	_, ok := err.PanicValue()
+	c.Check(ok, gc.Equals, false)
+	c.Check(Fingerprint(err), gc.DeepEquals, []string{"x", "1"})
+	c.Check(err.Hash(), gc.Equals, (&Error{Domain: "x", Code: 1}).Hash())
+}
+
+func (t *TestSuite) TestChainLinks(c *gc.C) {
+	root := NewError(EMyError0)
+	middle := NewError(EMyError1)
+	outer := NewError(EMyErrorArgs, "name", "outer")
+	Chain(root, middle)
+	Chain(middle, outer)
+	c.Check(outer.Chain(), gc.DeepEquals, []*Error{root, middle, outer})
+	c.Check(root.Chain(), gc.DeepEquals, []*Error{root})
+
+	defer SetMaxChainDepth(DefaultChainDepth)
+	SetMaxChainDepth(2)
+	c.Check(outer.Chain(), gc.DeepEquals, []*Error{middle, outer})
+}
+
+func (t *TestSuite) TestWalk(c *gc.C) {
+	root := NewError(EMyError0)
+	first := NewError(EMyError1)
+	cause := NewError(EMyErrorArgs, "name", "cause")
+	Chain(root, first)
+	joined := Join(first, cause)
+	outer := NewError(EMyErrorArgs, "name", "outer")
+	Chain(joined, outer)
+
+	var visited []*Error
+	Walk(fmt.Errorf("request: %w", outer), func(link *Error) bool {
+		visited = append(visited, link)
+		return true
+	})
+	c.Check(visited, gc.DeepEquals, []*Error{outer, joined, first, root, cause})
+
+	visited = nil
+	Walk(outer, func(link *Error) bool {
+		visited = append(visited, link)
+		return link != root
+	})
+	c.Check(visited, gc.DeepEquals, []*Error{outer, joined, first, root})
+
+	cyclic := &Error{Domain: "x"}
+	cyclic.Causes = []*Error{cyclic}
+	SetMaxChainDepth(5)
+	defer SetMaxChainDepth(DefaultChainDepth)
+	n := 0
+	Walk(cyclic, func(*Error) bool {
+		n++
+		return true
+	})
+	c.Check(n, gc.Equals, 5)
+
+	Walk(io.EOF, func(*Error) bool {
+		c.Error("walked a go error")
+		return true
+	})
+	Walk(nil, func(*Error) bool {
+		c.Error("walked nil")
+		return true
+	})
+}
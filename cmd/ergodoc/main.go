@@ -0,0 +1,107 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Command ergodoc generates reference documentation of the error codes
+// described by catalog files, see ergo.Catalog:
+//
+//	ergodoc -format html -o errors.html errors/*.yaml
+//
+// JSON, YAML and TOML catalogs are supported. Translations are ignored.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/flaub/ergo"
+	"github.com/flaub/ergo/ergodoc"
+	_ "github.com/flaub/ergo/tomlergo"
+	_ "github.com/flaub/ergo/yamlergo"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	format = flag.String("format", "markdown", "the output format, markdown or html")
+	output = flag.String("o", "", "the output file (default standard output)")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: ergodoc [flags] catalog...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "ergodoc: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(paths []string) error {
+	var write func(io.Writer, []ergodoc.Domain) error
+	switch strings.ToLower(*format) {
+	case "markdown", "md":
+		write = ergodoc.Markdown
+	case "html":
+		write = ergodoc.HTML
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	var cats []*ergo.Catalog
+	for _, path := range paths {
+		cat, err := decode(path)
+		if err != nil {
+			return err
+		}
+		cats = append(cats, cat)
+	}
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return write(w, ergodoc.FromCatalogs(cats))
+}
+
+func decode(path string) (*ergo.Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cat, err := ergo.DecodeCatalog(f, strings.TrimPrefix(filepath.Ext(path), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return cat, nil
+}
@@ -0,0 +1,182 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package main
+
+import (
+	"fmt"
+	"github.com/flaub/ergo"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// runCatalog generates the constants, the registration and the
+// constructors of the domain of a catalog, so that the catalog is
+// the single source of truth. Paths are relative to the current
+// directory, that of the package when run by go generate.
+func (gen *generator) runCatalog(output string) error {
+	f, err := os.Open(gen.catalog)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	cat, err := ergo.DecodeCatalog(f, strings.TrimPrefix(filepath.Ext(gen.catalog), "."))
+	if err != nil {
+		return fmt.Errorf("%v: %v", gen.catalog, err)
+	}
+	if cat.Locale != "" {
+		return fmt.Errorf("%v: catalog holds translations to %v", gen.catalog, cat.Locale)
+	}
+	if _, err := cat.DomainMap(); err != nil {
+		return fmt.Errorf("%v: %v", gen.catalog, err)
+	}
+	gen.domain = cat.Domain
+	if output == "" {
+		output = strings.ToLower(strings.Replace(gen.domain, ".", "_", -1)) + "_ergo.go"
+	}
+	pkg := gen.pkg
+	if pkg == "" {
+		if pkg, err = packageOf(filepath.Dir(output), output); err != nil {
+			return err
+		}
+	}
+	codes, err := catalogCodes(cat)
+	if err != nil {
+		return fmt.Errorf("%v: %v", gen.catalog, err)
+	}
+	src, err := gen.generate(pkg, codes)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, src, 0644)
+}
+
+// catalogCodes returns the codes of a catalog, ordered by value.
+func catalogCodes(cat *ergo.Catalog) ([]code, error) {
+	entries := append([]ergo.CatalogEntry(nil), cat.Codes...)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	codes := make([]code, 0, len(entries))
+	for _, entry := range entries {
+		if !token.IsIdentifier(entry.Name) || !token.IsExported(entry.Name) {
+			return nil, fmt.Errorf("[%v:%d] needs an exported Go name, not %q", cat.Domain, entry.Code, entry.Name)
+		}
+		keys, err := ergo.MessageKeys(entry.Message)
+		if err != nil {
+			return nil, fmt.Errorf("[%v:%d]: %v", cat.Domain, entry.Code, err)
+		}
+		severity, _ := ergo.ParseSeverity(entry.Severity)
+		c := code{
+			Name:    entry.Name,
+			Message: entry.Message,
+			Value:   int(entry.Code),
+			Status:  entry.Status,
+			Ctor:    constructorName(entry.Name),
+		}
+		if severity != ergo.SeverityUnset {
+			name := severity.String()
+			c.Severity = "Severity" + name[:1] + strings.ToLower(name[1:])
+		}
+		used := make(map[string]bool)
+		for _, key := range keys {
+			c.Params = append(c.Params, param{Name: paramName(key, used), Key: key})
+		}
+		codes = append(codes, c)
+	}
+	return codes, nil
+}
+
+// packageOf returns the name of the package in "dir", ignoring tests
+// and the previous output, or the name of the directory if it is empty.
+func packageOf(dir, output string) (string, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") || filepath.Clean(name) == filepath.Clean(output) {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), name, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", err
+		}
+		return file.Name.Name, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(identifier(filepath.Base(abs))), nil
+}
+
+// constructorName names the constructor of a code,
+// such as NewInvoiceNotFound for EInvoiceNotFound.
+func constructorName(name string) string {
+	runes := []rune(name)
+	if len(runes) > 1 && runes[0] == 'E' && unicode.IsUpper(runes[1]) {
+		return "New" + string(runes[1:])
+	}
+	return "New" + name
+}
+
+// paramName turns an Info key such as "user_id" into a parameter name
+// such as "userId", distinct from keywords and from the names in "used".
+func paramName(key string, used map[string]bool) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range key {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			upper = b.Len() != 0
+		case b.Len() == 0 && unicode.IsDigit(r):
+			b.WriteString("arg")
+			b.WriteRune(r)
+		case b.Len() == 0:
+			b.WriteRune(unicode.ToLower(r))
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	name := b.String()
+	if name == "" {
+		name = "arg"
+	}
+	if token.IsKeyword(name) || name == "ergo" {
+		name += "_"
+	}
+	for i := 2; used[name]; i++ {
+		name = strings.TrimRight(name, "0123456789") + strconv.Itoa(i)
+	}
+	used[name] = true
+	return name
+}
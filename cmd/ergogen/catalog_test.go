@@ -0,0 +1,146 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package main
+
+import (
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"os"
+	"path/filepath"
+)
+
+const billingCatalog = `domain: billing
+codes:
+  - code: 14
+    name: EInvoiceNotFound
+    message: "The invoice {{.id}} of {{.user_id}} was not found"
+    status: 404
+    severity: warn
+  - code: 2
+    name: ECardDeclined
+    message: The card was declined
+`
+
+const generatedCatalog = `// Code generated by "ergogen -catalog %v"; DO NOT EDIT.
+
+package billing
+
+import (
+	"github.com/flaub/ergo"
+)
+
+// The error codes of the billing domain.
+const (
+	// The card was declined
+	ECardDeclined ergo.ErrCode = 2
+	// The invoice {{.id}} of {{.user_id}} was not found
+	EInvoiceNotFound ergo.ErrCode = 14
+)
+
+// billingDomain holds the message formats of the billing domain.
+var billingDomain = ergo.DomainMap{
+	ECardDeclined:    "The card was declined",
+	EInvoiceNotFound: "The invoice {{.id}} of {{.user_id}} was not found",
+}
+
+// billingNames holds the names of the codes of the billing domain.
+var billingNames = map[ergo.ErrCode]string{
+	ECardDeclined:    "ECardDeclined",
+	EInvoiceNotFound: "EInvoiceNotFound",
+}
+
+func init() {
+	ergo.Domain("billing", billingDomain, ergo.WithNames(billingNames))
+	ergo.HTTPStatus("billing", EInvoiceNotFound, 404)
+}
+
+// NewCardDeclined returns an error of code ECardDeclined.
+func NewCardDeclined() *ergo.Error {
+	return ergo.NewE("billing", ECardDeclined, ergo.WithSkip(1))
+}
+
+// NewInvoiceNotFound returns an error of code EInvoiceNotFound.
+func NewInvoiceNotFound(id, userId interface{}) *ergo.Error {
+	return ergo.NewE("billing", EInvoiceNotFound, ergo.WithSkip(1), ergo.WithInfo("id", id), ergo.WithInfo("user_id", userId), ergo.WithSeverity(ergo.SeverityWarn))
+}
+`
+
+func (t *TestSuite) TestCatalog(c *gc.C) {
+	dir := c.MkDir()
+	write(c, dir, "errors.yaml", billingCatalog)
+	write(c, dir, "doc.go", "// Package billing bills.\npackage billing\n")
+	path := filepath.Join(dir, "errors.yaml")
+	gen := &generator{catalog: path, typeName: "ErrCode", register: true}
+	c.Assert(gen.runCatalog(filepath.Join(dir, "billing_ergo.go")), gc.IsNil)
+	out, err := os.ReadFile(filepath.Join(dir, "billing_ergo.go"))
+	c.Assert(err, gc.IsNil)
+	c.Check(string(out), gc.Equals, fmt.Sprintf(generatedCatalog, filepath.ToSlash(path)))
+}
+
+func (t *TestSuite) TestCatalogVerbatim(c *gc.C) {
+	dir := c.MkDir()
+	write(c, dir, "errors.json", `{"domain": "billing", "codes": [{"code": 1, "name": "EOne", "message": "The  card\nwas declined "}]}`)
+	gen := &generator{catalog: filepath.Join(dir, "errors.json"), pkg: "billing", register: true}
+	c.Assert(gen.runCatalog(filepath.Join(dir, "out.go")), gc.IsNil)
+	out, err := os.ReadFile(filepath.Join(dir, "out.go"))
+	c.Assert(err, gc.IsNil)
+	c.Check(string(out), gc.Matches, `(?s).*\t// The  card\n\t// was declined\n\tEOne ergo.ErrCode = 1\n.*`)
+	c.Check(string(out), gc.Matches, `(?s).*\tEOne: "The  card\\nwas declined ",\n.*`)
+}
+
+func (t *TestSuite) TestCatalogErrors(c *gc.C) {
+	dir := c.MkDir()
+	for _, test := range []struct{ doc, err string }{
+		{`{"domain": "billing", "locale": "de", "codes": []}`, ".*: catalog holds translations to de"},
+		{`{"domain": "billing", "codes": [{"code": 1, "message": "x"}]}`,
+			`.*: \[billing:1\] needs an exported Go name, not ""`},
+		{`{"domain": "billing", "codes": [{"code": 1, "name": "eOne", "message": "x"}]}`,
+			`.*: \[billing:1\] needs an exported Go name, not "eOne"`},
+		{`{"domain": "billing", "codes": [{"code": 1, "name": "EOne", "message": "{{.x"}]}`,
+			`.*: \[billing:1\]: .*unclosed action`},
+		{`{"domain": "billing", "codes": [{"code": 1, "name": "A"}, {"code": 1, "name": "B"}]}`,
+			`.*: ergo: \[billing:1\] is defined twice`},
+		{`{"domain": "billing", "codes": [{"code": 1, "name": "A", "severity": "bad"}]}`,
+			`.*: ergo: \[billing:1\] has unknown severity "bad"`},
+	} {
+		write(c, dir, "errors.json", test.doc)
+		gen := &generator{catalog: filepath.Join(dir, "errors.json"), register: true}
+		c.Check(gen.runCatalog(filepath.Join(dir, "out.go")), gc.ErrorMatches, test.err)
+	}
+}
+
+func (t *TestSuite) TestNames(c *gc.C) {
+	c.Check(constructorName("EInvoiceNotFound"), gc.Equals, "NewInvoiceNotFound")
+	c.Check(constructorName("Eager"), gc.Equals, "NewEager")
+	c.Check(constructorName("E"), gc.Equals, "NewE")
+
+	used := make(map[string]bool)
+	c.Check(paramName("user_id", used), gc.Equals, "userId")
+	c.Check(paramName("user-id", used), gc.Equals, "userId2")
+	c.Check(paramName("user.id", used), gc.Equals, "userId3")
+	c.Check(paramName("Type", used), gc.Equals, "type_")
+	c.Check(paramName("ergo", used), gc.Equals, "ergo_")
+	c.Check(paramName("2fa", used), gc.Equals, "arg2fa")
+	c.Check(paramName("_", used), gc.Equals, "arg")
+}
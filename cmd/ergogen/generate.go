@@ -0,0 +1,326 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// generator generates the registration of a domain.
+type generator struct {
+	domain   string
+	typeName string
+	register bool
+
+	// The catalog file to generate from, if any, see runCatalog.
+	catalog string
+	pkg     string
+}
+
+// code is an error code of the generated domain.
+// Constants, statuses and constructors are only generated from catalogs.
+type code struct {
+	Name    string
+	Message string
+
+	Value    int
+	Status   int
+	Severity string
+	Ctor     string
+	Params   []param
+}
+
+// param is a parameter of a constructor, setting an Info key.
+type param struct {
+	Name string
+	Key  string
+}
+
+// run scans the package in "dir" and writes the generated file.
+func (gen *generator) run(dir, output string) error {
+	if output == "" {
+		output = strings.ToLower(strings.Replace(gen.domain, ".", "_", -1)) + "_ergo.go"
+	}
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(dir, output)
+	}
+	pkg, codes, err := gen.scan(dir, output)
+	if err != nil {
+		return err
+	}
+	src, err := gen.generate(pkg, codes)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, src, 0644)
+}
+
+// scan returns the name of the package in "dir" and its error codes,
+// ignoring tests and the previous output.
+func (gen *generator) scan(dir, output string) (string, []code, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+	sort.Strings(names)
+	fset := token.NewFileSet()
+	var pkg string
+	var codes []code
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") || filepath.Clean(name) == filepath.Clean(output) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return "", nil, err
+		}
+		if pkg != "" && file.Name.Name != pkg {
+			return "", nil, fmt.Errorf("%v: package %v, expected %v", name, file.Name.Name, pkg)
+		}
+		pkg = file.Name.Name
+		found, err := gen.constants(fset, file)
+		if err != nil {
+			return "", nil, err
+		}
+		codes = append(codes, found...)
+	}
+	if pkg == "" {
+		return "", nil, fmt.Errorf("no Go files in %v", dir)
+	}
+	if len(codes) == 0 {
+		return "", nil, fmt.Errorf("no constants of type %v in %v", gen.typeName, dir)
+	}
+	return pkg, codes, nil
+}
+
+// constants returns the error codes declared in a file.
+// Within a const block, specs without a type or values
+// repeat the previous spec, as they do in Go.
+func (gen *generator) constants(fset *token.FileSet, file *ast.File) ([]code, error) {
+	var codes []code
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.CONST {
+			continue
+		}
+		typed := false
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.ValueSpec)
+			if spec.Type != nil {
+				typed = gen.isType(spec.Type)
+			} else if len(spec.Values) != 0 {
+				typed = gen.isConversion(spec.Values[0])
+			}
+			if !typed {
+				continue
+			}
+			doc := spec.Doc
+			if doc == nil && len(decl.Specs) == 1 {
+				doc = decl.Doc
+			}
+			if doc == nil {
+				doc = spec.Comment
+			}
+			for _, ident := range spec.Names {
+				if ident.Name == "_" {
+					continue
+				}
+				msg := strings.Join(strings.Fields(doc.Text()), " ")
+				if msg == "" {
+					return nil, fmt.Errorf("%v: %v has no message", fset.Position(ident.Pos()), ident.Name)
+				}
+				codes = append(codes, code{Name: ident.Name, Message: msg})
+			}
+		}
+	}
+	return codes, nil
+}
+
+// isType reports whether an expression names the type of the codes,
+// possibly qualified by its package.
+func (gen *generator) isType(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name == gen.typeName
+	case *ast.SelectorExpr:
+		return expr.Sel.Name == gen.typeName
+	}
+	return false
+}
+
+// isConversion reports whether an expression converts to the type of the codes.
+func (gen *generator) isConversion(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	return ok && len(call.Args) == 1 && gen.isType(call.Fun)
+}
+
+// generate returns the formatted source of the generated file.
+func (gen *generator) generate(pkg string, codes []code) ([]byte, error) {
+	qualifier := "ergo."
+	if pkg == "ergo" {
+		qualifier = ""
+	}
+	data := struct {
+		Args      string
+		Package   string
+		Qualifier string
+		Domain    string
+		Ident     string
+		Register  bool
+		Catalog   bool
+		Codes     []code
+	}{
+		Args:      strings.Join(gen.args(), " "),
+		Package:   pkg,
+		Qualifier: qualifier,
+		Domain:    gen.domain,
+		Ident:     identifier(gen.domain),
+		Register:  gen.register,
+		Catalog:   gen.catalog != "",
+		Codes:     codes,
+	}
+	var b bytes.Buffer
+	if err := fileTemplate.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid output: %v", err)
+	}
+	return src, nil
+}
+
+// args returns the arguments reproducing the generated file.
+func (gen *generator) args() []string {
+	args := []string{"-domain", gen.domain}
+	if gen.catalog != "" {
+		args = []string{"-catalog", filepath.ToSlash(gen.catalog)}
+		if gen.pkg != "" {
+			args = append(args, "-package", gen.pkg)
+		}
+	}
+	if gen.typeName != "ErrCode" {
+		args = append(args, "-type", gen.typeName)
+	}
+	if !gen.register {
+		args = append(args, "-init=false")
+	}
+	return args
+}
+
+// identifier turns a domain name such as "billing.invoices"
+// into an unexported identifier such as "billingInvoices".
+func identifier(domain string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range domain {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			upper = b.Len() != 0
+		case b.Len() == 0 && unicode.IsDigit(r):
+			b.WriteString("domain")
+			b.WriteRune(r)
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	if b.Len() == 0 {
+		return "domain"
+	}
+	return b.String()
+}
+
+// comment turns a message, which may span lines, into a line comment.
+func comment(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+line, " \t\r")
+	}
+	return strings.Join(lines, "\n\t")
+}
+
+var fileTemplate = template.Must(template.New("output").Funcs(template.FuncMap{
+	"quote":   strconv.Quote,
+	"comment": comment,
+}).Parse(`// Code generated by "ergogen {{.Args}}"; DO NOT EDIT.
+
+package {{.Package}}
+{{if .Qualifier}}
+import (
+	"github.com/flaub/ergo"
+)
+{{end}}
+{{- if .Catalog}}
+// The error codes of the {{.Domain}} domain.
+const (
+{{- range .Codes}}
+	{{comment .Message}}
+	{{.Name}} {{$.Qualifier}}ErrCode = {{.Value}}
+{{- end}}
+)
+{{end}}
+// {{.Ident}}Domain holds the message formats of the {{.Domain}} domain.
+var {{.Ident}}Domain = {{.Qualifier}}DomainMap{
+{{- range .Codes}}
+	{{.Name}}: {{quote .Message}},
+{{- end}}
+}
+
+// {{.Ident}}Names holds the names of the codes of the {{.Domain}} domain.
+var {{.Ident}}Names = map[{{.Qualifier}}ErrCode]string{
+{{- range .Codes}}
+	{{.Name}}: {{quote .Name}},
+{{- end}}
+}
+{{if .Register}}
+func init() {
+	{{.Qualifier}}Domain({{quote .Domain}}, {{.Ident}}Domain, {{.Qualifier}}WithNames({{.Ident}}Names))
+{{- range .Codes}}{{if .Status}}
+	{{$.Qualifier}}HTTPStatus({{quote $.Domain}}, {{.Name}}, {{.Status}})
+{{- end}}{{end}}
+}
+{{end}}
+{{- range .Codes}}{{if .Ctor}}
+// {{.Ctor}} returns an error of code {{.Name}}.
+func {{.Ctor}}({{range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Name}}{{end}}{{if .Params}} interface{}{{end}}) *{{$.Qualifier}}Error {
+	return {{$.Qualifier}}NewE({{quote $.Domain}}, {{.Name}}, {{$.Qualifier}}WithSkip(1)
+		{{- range .Params}}, {{$.Qualifier}}WithInfo({{quote .Key}}, {{.Name}}){{end}}
+		{{- if .Severity}}, {{$.Qualifier}}WithSeverity({{$.Qualifier}}{{.Severity}}){{end}})
+}
+{{end}}{{end}}`))
@@ -0,0 +1,157 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package main
+
+import (
+	gc "github.com/motain/gocheck"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const billing = `package billing
+
+import "github.com/flaub/ergo"
+
+//go:generate ergogen -domain billing.invoices
+
+const (
+	// The invoice {{.id}} was not found
+	EInvoiceNotFound ergo.ErrCode = iota + 1
+
+	// The invoice {{.id}}
+	// was already paid
+	EInvoicePaid
+
+	_
+	EInvoiceLocked // The invoice is locked
+)
+
+// The card was declined
+const ECardDeclined = ergo.ErrCode(10)
+
+const maxRetries = 3
+`
+
+const generated = `// Code generated by "ergogen -domain billing.invoices"; DO NOT EDIT.
+
+package billing
+
+import (
+	"github.com/flaub/ergo"
+)
+
+// billingInvoicesDomain holds the message formats of the billing.invoices domain.
+var billingInvoicesDomain = ergo.DomainMap{
+	EInvoiceNotFound: "The invoice {{.id}} was not found",
+	EInvoicePaid:     "The invoice {{.id}} was already paid",
+	EInvoiceLocked:   "The invoice is locked",
+	ECardDeclined:    "The card was declined",
+}
+
+// billingInvoicesNames holds the names of the codes of the billing.invoices domain.
+var billingInvoicesNames = map[ergo.ErrCode]string{
+	EInvoiceNotFound: "EInvoiceNotFound",
+	EInvoicePaid:     "EInvoicePaid",
+	EInvoiceLocked:   "EInvoiceLocked",
+	ECardDeclined:    "ECardDeclined",
+}
+
+func init() {
+	ergo.Domain("billing.invoices", billingInvoicesDomain, ergo.WithNames(billingInvoicesNames))
+}
+`
+
+func write(c *gc.C, dir, name, src string) {
+	c.Assert(os.WriteFile(filepath.Join(dir, name), []byte(src), 0644), gc.IsNil)
+}
+
+func (t *TestSuite) TestGenerate(c *gc.C) {
+	dir := c.MkDir()
+	write(c, dir, "billing.go", billing)
+	write(c, dir, "billing_test.go", "package billing\n\nconst ETest ergo.ErrCode = 0\n")
+	gen := &generator{domain: "billing.invoices", typeName: "ErrCode", register: true}
+	c.Assert(gen.run(dir, ""), gc.IsNil)
+	out, err := os.ReadFile(filepath.Join(dir, "billing_invoices_ergo.go"))
+	c.Assert(err, gc.IsNil)
+	c.Check(string(out), gc.Equals, generated)
+
+	// The previous output is ignored when generating again.
+	c.Assert(gen.run(dir, ""), gc.IsNil)
+	again, err := os.ReadFile(filepath.Join(dir, "billing_invoices_ergo.go"))
+	c.Assert(err, gc.IsNil)
+	c.Check(string(again), gc.Equals, generated)
+}
+
+func (t *TestSuite) TestWithoutInit(c *gc.C) {
+	dir := c.MkDir()
+	write(c, dir, "codes.go", "package ergo\n\nconst (\n\t// Zero\n\tEZero Code = iota\n)\n")
+	gen := &generator{domain: "ergo", typeName: "Code", register: false}
+	c.Assert(gen.run(dir, "codes_gen.go"), gc.IsNil)
+	out, err := os.ReadFile(filepath.Join(dir, "codes_gen.go"))
+	c.Assert(err, gc.IsNil)
+	c.Check(string(out), gc.Equals, `// Code generated by "ergogen -domain ergo -type Code -init=false"; DO NOT EDIT.
+
+package ergo
+
+// ergoDomain holds the message formats of the ergo domain.
+var ergoDomain = DomainMap{
+	EZero: "Zero",
+}
+
+// ergoNames holds the names of the codes of the ergo domain.
+var ergoNames = map[ErrCode]string{
+	EZero: "EZero",
+}
+`)
+}
+
+func (t *TestSuite) TestErrors(c *gc.C) {
+	gen := &generator{domain: "billing", typeName: "ErrCode", register: true}
+	dir := c.MkDir()
+	c.Check(gen.run(dir, ""), gc.ErrorMatches, "no Go files in .*")
+	write(c, dir, "a.go", "package a\n\nconst x = 1\n")
+	c.Check(gen.run(dir, ""), gc.ErrorMatches, "no constants of type ErrCode in .*")
+	write(c, dir, "b.go", "package a\n\nconst (\n\t// Documented\n\tEOne ErrCode = 1\n\tETwo\n)\n")
+	c.Check(gen.run(dir, ""), gc.ErrorMatches, ".*b.go:6:2: ETwo has no message")
+	write(c, dir, "b.go", "package a\n\nconst (\n\t// Documented\n\tEOne ErrCode = 1\n)\n")
+	write(c, dir, "c.go", "package c\n")
+	c.Check(gen.run(dir, ""), gc.ErrorMatches, ".*c.go: package c, expected a")
+}
+
+func (t *TestSuite) TestIdentifier(c *gc.C) {
+	c.Check(identifier("billing"), gc.Equals, "billing")
+	c.Check(identifier("Billing.invoices-v2"), gc.Equals, "billingInvoicesV2")
+	c.Check(identifier("2fa"), gc.Equals, "domain2fa")
+	c.Check(identifier("..."), gc.Equals, "domain")
+}
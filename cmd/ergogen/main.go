@@ -0,0 +1,95 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Command ergogen generates the registration of an ergo domain from the
+// error codes of a package, so that constants and messages cannot drift:
+//
+//	//go:generate ergogen -domain billing
+//
+//	const (
+//		// The invoice {{.id}} was not found
+//		EInvoiceNotFound ergo.ErrCode = iota + 1
+//		// The invoice {{.id}} was already paid
+//		EInvoicePaid
+//	)
+//
+// The doc comment of each constant of type ErrCode is its message format.
+// The generated file, billing_ergo.go by default, holds the DomainMap,
+// the names of the codes, and an init function defining the domain.
+//
+// Alternatively, a catalog file may be the single source of truth,
+// see ergo.Catalog. Every code then needs a name:
+//
+//	//go:generate ergogen -catalog errors.yaml
+//
+// Besides the above, the constants of the codes are generated, along
+// with their HTTP statuses and a constructor for each code taking the
+// Info keys of its message, such as NewInvoiceNotFound(id interface{})
+// for EInvoiceNotFound. The constructors apply the severity of the code.
+// YAML and TOML catalogs are supported as well as JSON.
+package main
+
+import (
+	"flag"
+	"fmt"
+	_ "github.com/flaub/ergo/tomlergo"
+	_ "github.com/flaub/ergo/yamlergo"
+	"os"
+)
+
+var (
+	domain   = flag.String("domain", "", "the name of the domain (required without -catalog)")
+	catalog  = flag.String("catalog", "", "the catalog file to generate from")
+	pkg      = flag.String("package", "", "the package of the output, with -catalog")
+	typeName = flag.String("type", "ErrCode", "the type of the error codes, ergo.ErrCode or an alias of it")
+	output   = flag.String("output", "", "the output file (default <domain>_ergo.go)")
+	register = flag.Bool("init", true, "define the domain in an init function")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: ergogen -domain name [flags] [directory]\n")
+		fmt.Fprintf(os.Stderr, "       ergogen -catalog file [flags]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	if (*domain == "") == (*catalog == "") || flag.NArg() > 1 || (*catalog != "" && flag.NArg() > 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	gen := &generator{domain: *domain, typeName: *typeName, register: *register, catalog: *catalog, pkg: *pkg}
+	var err error
+	if *catalog != "" {
+		err = gen.runCatalog(*output)
+	} else {
+		err = gen.run(dir, *output)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ergogen: %v\n", err)
+		os.Exit(1)
+	}
+}
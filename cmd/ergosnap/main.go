@@ -0,0 +1,89 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Command ergosnap checks that the error codes of catalog files, see
+// ergo.Catalog, do not change in ways breaking clients, by comparing
+// them with a snapshot, see the snapshot package:
+//
+//	ergosnap -snapshot errors.snapshot.json errors/*.yaml
+//
+// The snapshot is written if it does not exist yet, or with -update
+// to acknowledge intended changes. JSON, YAML and TOML catalogs are
+// supported. Translations are ignored.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/flaub/ergo"
+	"github.com/flaub/ergo/snapshot"
+	_ "github.com/flaub/ergo/tomlergo"
+	_ "github.com/flaub/ergo/yamlergo"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	path   = flag.String("snapshot", "", "the snapshot file (required)")
+	strict = flag.Bool("strict", false, "report reworded messages as well")
+	update = flag.Bool("update", false, "write the snapshot instead of checking it")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: ergosnap -snapshot file [flags] catalog...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *path == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var cats []*ergo.Catalog
+	for _, name := range flag.Args() {
+		cat, err := decode(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ergosnap: %v\n", err)
+			os.Exit(1)
+		}
+		cats = append(cats, cat)
+	}
+	if err := snapshot.Check(*path, snapshot.FromCatalogs(cats), *strict, *update); err != nil {
+		fmt.Fprintf(os.Stderr, "ergosnap: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func decode(name string) (*ergo.Catalog, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cat, err := ergo.DecodeCatalog(f, strings.TrimPrefix(filepath.Ext(name), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", name, err)
+	}
+	return cat, nil
+}
@@ -0,0 +1,200 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+var (
+	// The code ranges given to Domain with WithCodeRange, by domain.
+	domainRanges sync.Map
+
+	// The names of codes by Target, and the codes of names by nameKey.
+	codeNames  sync.Map
+	namedCodes sync.Map
+)
+
+// nameKey identifies a named code of a domain.
+type nameKey struct {
+	domain string
+	name   string
+}
+
+// codeRange is an inclusive range of error codes.
+type codeRange struct {
+	min, max ErrCode
+}
+
+// Merge adds the message formats of other maps to a domain, so that
+// large domains can be assembled from the maps of several subsystems.
+// Domain panics if a code is defined more than once, instead of
+// silently keeping the last message format.
+func Merge(maps ...DomainMap) DomainOption {
+	return func(opts *domainOptions) {
+		opts.merge = append(opts.merge, maps...)
+	}
+}
+
+// WithCodeRange reserves the codes from min to max, inclusive, to a domain.
+// Domain panics if a code lies outside of the range, and catalogs,
+// translations and updates of the domain are rejected likewise.
+func WithCodeRange(min, max ErrCode) DomainOption {
+	return func(opts *domainOptions) {
+		opts.codes = &codeRange{min: min, max: max}
+	}
+}
+
+// WithNames gives symbolic names to the codes of a domain, see NameCodes.
+func WithNames(names map[ErrCode]string) DomainOption {
+	return func(opts *domainOptions) {
+		opts.names = names
+	}
+}
+
+// NameCodes gives symbolic names to the codes of a domain, such as
+// "EInvoiceNotFound", so that errors render as [billing:EInvoiceNotFound]
+// rather than [billing:14]. Names are kept by the wire and text formats,
+// and resolved back to codes when decoding.
+func NameCodes(domain string, names map[ErrCode]string) {
+	for code, name := range names {
+		codeNames.Store(Target{Domain: domain, Code: code}, name)
+		namedCodes.Store(nameKey{domain: domain, name: name}, code)
+	}
+}
+
+// CodeName returns the name of a code, reporting false if it has none.
+func CodeName(domain string, code ErrCode) (string, bool) {
+	name, ok := codeNames.Load(Target{Domain: domain, Code: code})
+	if !ok {
+		return "", false
+	}
+	return name.(string), true
+}
+
+// CodeByName returns the code of a name, reporting false if there is none.
+func CodeByName(domain, name string) (ErrCode, bool) {
+	code, ok := namedCodes.Load(nameKey{domain: domain, name: name})
+	if !ok {
+		return 0, false
+	}
+	return code.(ErrCode), true
+}
+
+// CodeString returns the name of a code, or its number if it has none.
+func CodeString(domain string, code ErrCode) string {
+	if name, ok := CodeName(domain, code); ok {
+		return name
+	}
+	return strconv.Itoa(int(code))
+}
+
+// String implements fmt.Stringer. Since a code alone does not tell
+// its domain, the name is only used if no other domain names the code
+// differently; otherwise, the number is returned. Use CodeString instead
+// whenever the domain is known.
+func (code ErrCode) String() string {
+	var found string
+	codeNames.Range(func(key, name interface{}) bool {
+		if key.(Target).Code != code {
+			return true
+		}
+		if found != "" && found != name.(string) {
+			found = ""
+			return false
+		}
+		found = name.(string)
+		return true
+	})
+	if found == "" {
+		return strconv.Itoa(int(code))
+	}
+	return found
+}
+
+// unnameCodes removes the names of the codes of a domain.
+func unnameCodes(domain string) {
+	codeNames.Range(func(key, name interface{}) bool {
+		if key.(Target).Domain == domain {
+			codeNames.Delete(key)
+			namedCodes.Delete(nameKey{domain: domain, name: name.(string)})
+		}
+		return true
+	})
+}
+
+// mergeDomains merges maps into a new domain,
+// reporting codes defined more than once.
+func mergeDomains(name string, domain DomainMap, maps []DomainMap) (DomainMap, error) {
+	if len(maps) == 0 {
+		return domain, nil
+	}
+	merged := make(DomainMap, len(domain))
+	for code, text := range domain {
+		merged[code] = text
+	}
+	for _, m := range maps {
+		for _, code := range sortedCodes(m) {
+			if _, ok := merged[code]; ok {
+				return nil, fmt.Errorf("ergo: [%v:%d] is defined twice", name, code)
+			}
+			merged[code] = m[code]
+		}
+	}
+	return merged, nil
+}
+
+// reservedRange returns the range reserved to a domain, if any.
+func reservedRange(name string) *codeRange {
+	r, _ := domainRanges.Load(name)
+	rng, _ := r.(*codeRange)
+	return rng
+}
+
+// checkRange reports codes outside of the range reserved to a domain.
+func checkRange(name string, domain DomainMap, r *codeRange) error {
+	if r == nil {
+		return nil
+	}
+	for _, code := range sortedCodes(domain) {
+		if code < r.min || code > r.max {
+			return fmt.Errorf("ergo: [%v:%d] is outside of the codes %d to %d of the domain",
+				name, code, r.min, r.max)
+		}
+	}
+	return nil
+}
+
+// sortedCodes returns the codes of a domain in ascending order.
+func sortedCodes(domain DomainMap) []ErrCode {
+	codes := make([]ErrCode, 0, len(domain))
+	for code := range domain {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
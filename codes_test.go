@@ -0,0 +1,102 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	gc "github.com/motain/gocheck"
+	"strings"
+)
+
+func (t *TestSuite) TestMerge(c *gc.C) {
+	defer RemoveDomain("merged")
+	Domain("merged", DomainMap{0: "Zero"}, Merge(DomainMap{1: "One"}, DomainMap{2: "Two"}))
+	codes, _ := ListCodes("merged")
+	c.Check(codes, gc.DeepEquals, DomainMap{0: "Zero", 1: "One", 2: "Two"})
+
+	c.Check(func() {
+		Domain("overlapping", DomainMap{0: "Zero", 1: "One"}, Merge(DomainMap{2: "Two", 1: "Uno"}))
+	}, gc.PanicMatches, `ergo: \[overlapping:1\] is defined twice`)
+	_, ok := ListCodes("overlapping")
+	c.Check(ok, gc.Equals, false)
+}
+
+func (t *TestSuite) TestCodeRange(c *gc.C) {
+	defer RemoveDomain("ranged")
+	c.Check(func() {
+		Domain("ranged", DomainMap{100: "In", 200: "Out"}, WithCodeRange(100, 199))
+	}, gc.PanicMatches, `ergo: \[ranged:200\] is outside of the codes 100 to 199 of the domain`)
+
+	Domain("ranged", DomainMap{100: "In", 199: "Last"}, WithCodeRange(100, 199))
+	c.Check(UpdateDomain("ranged", DomainMap{99: "Out"}), gc.ErrorMatches,
+		`ergo: \[ranged:99\] is outside of the codes 100 to 199 of the domain`)
+	c.Check(func() { DomainLocale("ranged", "de", DomainMap{300: "Aus"}) }, gc.PanicMatches,
+		`ergo: \[ranged:300\] is outside .*`)
+	_, err := LoadDomainReader(strings.NewReader(
+		`{"domain": "ranged", "locale": "fr", "codes": [{"code": 5, "message": "Hors"}]}`), "json")
+	c.Check(err, gc.ErrorMatches, `ergo: \[ranged:5\] is outside .*`)
+	c.Check(UpdateDomain("ranged", DomainMap{150: "Middle"}), gc.IsNil)
+	c.Check(New(0, "ranged", 150).Message(), gc.Equals, "Middle")
+}
+
+func (t *TestSuite) TestNames(c *gc.C) {
+	defer RemoveDomain("named")
+	defer RemoveDomain("alsonamed")
+	Domain("named", DomainMap{14: "Invoice not found", 15: "Paid"},
+		WithNames(map[ErrCode]string{14: "EInvoiceNotFound"}))
+
+	name, ok := CodeName("named", 14)
+	c.Check(ok, gc.Equals, true)
+	c.Check(name, gc.Equals, "EInvoiceNotFound")
+	code, ok := CodeByName("named", "EInvoiceNotFound")
+	c.Check(ok, gc.Equals, true)
+	c.Check(code, gc.Equals, ErrCode(14))
+	c.Check(CodeString("named", 15), gc.Equals, "15")
+
+	err := New(0, "named", 14)
+	c.Check(Code("named", 14).Error(), gc.Equals, "[named:EInvoiceNotFound]")
+	c.Check(err.Oneline(), gc.Equals, "[named:EInvoiceNotFound] Invoice not found")
+
+	parsed, perr := ParseText(err.Error())
+	c.Assert(perr, gc.IsNil)
+	c.Check(parsed.Code, gc.Equals, ErrCode(14))
+	wire := err.ToWire()
+	c.Check(wire.Name, gc.Equals, "EInvoiceNotFound")
+	wire.Code = 15
+	_, perr = wire.ToError()
+	c.Check(perr, gc.ErrorMatches, `ergo: \[named:15\] is named EInvoiceNotFound, but EInvoiceNotFound is 14`)
+
+	c.Check(ErrCode(14).String(), gc.Equals, "EInvoiceNotFound")
+	NameCodes("alsonamed", map[ErrCode]string{14: "EOther"})
+	c.Check(ErrCode(14).String(), gc.Equals, "14")
+}
+
+func (t *TestSuite) TestCatalogNames(c *gc.C) {
+	defer RemoveDomain("catnamed")
+	_, err := LoadDomainReader(strings.NewReader(
+		`{"domain": "catnamed", "codes": [{"code": 3, "name": "EThree", "message": "Three"}]}`), "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(CodeString("catnamed", 3), gc.Equals, "EThree")
+	RemoveDomain("catnamed")
+	c.Check(CodeString("catnamed", 3), gc.Equals, "3")
+}
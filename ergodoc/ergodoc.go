@@ -0,0 +1,204 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package ergodoc generates reference documentation of error codes,
+// in Markdown or HTML, from the domains defined in a process or from
+// catalog files, so that support teams can look up what an error means
+// and what to do about it. See also the ergodoc command.
+package ergodoc
+
+import (
+	"github.com/flaub/ergo"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Domain documents the codes of a domain.
+type Domain struct {
+	Name  string
+	Codes []Code
+}
+
+// Code documents an error code. Fields may be empty,
+// for instance the severity of codes not described by a catalog.
+type Code struct {
+	Code        ergo.ErrCode
+	Name        string
+	Message     string
+	Status      int
+	Severity    string
+	Remediation string
+}
+
+// FromRegistry documents the domains defined with ergo.Domain in this
+// process, with their code names and HTTP statuses. Domains defined with
+// ergo.DomainFunc have no codes to document and are omitted.
+func FromRegistry() []Domain {
+	var domains []Domain
+	for _, name := range ergo.ListDomains() {
+		formats, ok := ergo.ListCodes(name)
+		if !ok {
+			continue
+		}
+		domain := Domain{Name: name}
+		for code, message := range formats {
+			doc := Code{Code: code, Message: message}
+			doc.Name, _ = ergo.CodeName(name, code)
+			doc.Status, _ = ergo.DeclaredHTTPStatus(name, code)
+			domain.Codes = append(domain.Codes, doc)
+		}
+		sortCodes(domain.Codes)
+		domains = append(domains, domain)
+	}
+	return domains
+}
+
+// FromCatalogs documents the domains of catalogs, sorted by name.
+// Catalogs of the same domain are merged, and translations are ignored.
+func FromCatalogs(cats []*ergo.Catalog) []Domain {
+	byName := make(map[string]*Domain)
+	var names []string
+	for _, cat := range cats {
+		if cat.Locale != "" {
+			continue
+		}
+		domain, ok := byName[cat.Domain]
+		if !ok {
+			domain = &Domain{Name: cat.Domain}
+			byName[cat.Domain] = domain
+			names = append(names, cat.Domain)
+		}
+		for _, entry := range cat.Codes {
+			domain.Codes = append(domain.Codes, Code{
+				Code:        entry.Code,
+				Name:        entry.Name,
+				Message:     entry.Message,
+				Status:      entry.Status,
+				Severity:    strings.ToLower(entry.Severity),
+				Remediation: entry.Remediation,
+			})
+		}
+	}
+	sort.Strings(names)
+	domains := make([]Domain, 0, len(names))
+	for _, name := range names {
+		sortCodes(byName[name].Codes)
+		domains = append(domains, *byName[name])
+	}
+	return domains
+}
+
+func sortCodes(codes []Code) {
+	sort.SliceStable(codes, func(i, j int) bool { return codes[i].Code < codes[j].Code })
+}
+
+// Markdown writes the documentation of domains as Markdown,
+// with a table of the codes of each domain.
+func Markdown(w io.Writer, domains []Domain) error {
+	var b strings.Builder
+	b.WriteString("# Error reference\n")
+	for _, domain := range domains {
+		b.WriteString("\n## " + domain.Name + "\n\n")
+		b.WriteString("| Code | Name | HTTP status | Severity | Message | Remediation |\n")
+		b.WriteString("| ---: | --- | ---: | --- | --- | --- |\n")
+		for _, code := range domain.Codes {
+			b.WriteString("| " + strconv.Itoa(int(code.Code)))
+			b.WriteString(" | " + cell(code.Name))
+			b.WriteString(" | " + status(code.Status))
+			b.WriteString(" | " + cell(code.Severity))
+			b.WriteString(" | " + codeSpan(code.Message))
+			b.WriteString(" | " + cell(code.Remediation) + " |\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var cellReplacer = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+func cell(text string) string {
+	return cellReplacer.Replace(text)
+}
+
+// codeSpan formats a message format as code, with delimiters
+// longer than any run of backticks within it.
+func codeSpan(text string) string {
+	if text == "" {
+		return ""
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	delim := "`"
+	for strings.Contains(text, delim) {
+		delim += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return delim + strings.Replace(text, "|", "\\|", -1) + delim
+}
+
+func status(code int) string {
+	if code == 0 {
+		return ""
+	}
+	return strconv.Itoa(code)
+}
+
+// HTML writes the documentation of domains as a standalone HTML page.
+// Each code can be linked to as #domain-code, such as #billing-14.
+func HTML(w io.Writer, domains []Domain) error {
+	return page.Execute(w, domains)
+}
+
+var page = template.Must(template.New("page").Funcs(template.FuncMap{
+	"status": status,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Error reference</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+code { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Error reference</h1>
+{{- range .}}
+<h2 id="{{.Name}}">{{.Name}}</h2>
+<table>
+<tr><th>Code</th><th>Name</th><th>HTTP status</th><th>Severity</th><th>Message</th><th>Remediation</th></tr>
+{{- $domain := .Name}}
+{{- range .Codes}}
+<tr id="{{$domain}}-{{printf "%d" .Code}}"><td>{{printf "%d" .Code}}</td><td>{{.Name}}</td><td>{{status .Status}}</td><td>{{.Severity}}</td><td><code>{{.Message}}</code></td><td>{{.Remediation}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,110 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergodoc
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"strings"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ErrCode0 ergo.ErrCode = iota
+	ErrCode1
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("ergodoc", ergo.DomainMap{
+		ErrCode1: "The {{.name}} | {{.other}} was not found",
+		ErrCode0: "Failed",
+	}, ergo.WithNames(map[ergo.ErrCode]string{ErrCode1: "ENotFound"}))
+	ergo.HTTPStatus("ergodoc", ErrCode1, 404)
+}
+
+func (t *TestSuite) TestFromRegistry(c *gc.C) {
+	var found *Domain
+	domains := FromRegistry()
+	for i := range domains {
+		c.Check(domains[i].Name, gc.Not(gc.Equals), "go")
+		if domains[i].Name == "ergodoc" {
+			found = &domains[i]
+		}
+	}
+	c.Assert(found, gc.NotNil)
+	c.Check(found.Codes, gc.DeepEquals, []Code{
+		{Code: ErrCode0, Message: "Failed"},
+		{Code: ErrCode1, Name: "ENotFound", Message: "The {{.name}} | {{.other}} was not found", Status: 404},
+	})
+
+	var b strings.Builder
+	c.Assert(Markdown(&b, []Domain{*found}), gc.IsNil)
+	c.Check(b.String(), gc.Equals, "# Error reference\n\n## ergodoc\n\n"+
+		"| Code | Name | HTTP status | Severity | Message | Remediation |\n"+
+		"| ---: | --- | ---: | --- | --- | --- |\n"+
+		"| 0 |  |  |  | `Failed` |  |\n"+
+		"| 1 | ENotFound | 404 |  | `The {{.name}} \\| {{.other}} was not found` |  |\n")
+}
+
+func (t *TestSuite) TestFromCatalogs(c *gc.C) {
+	cat, err := ergo.DecodeCatalog(strings.NewReader(`{"domain": "shop", "codes": [
+	  {"code": 2, "name": "EOutOfStock", "message": "Out of stock", "status": 409,
+	   "severity": "WARN", "remediation": "Try again\nlater"},
+	  {"code": 1, "message": "Uses `+"`ticks`"+`"}
+	]}`), "json")
+	c.Assert(err, gc.IsNil)
+	other := &ergo.Catalog{Domain: "bank", Codes: []ergo.CatalogEntry{{Code: 7, Message: "Closed"}}}
+	more := &ergo.Catalog{Domain: "shop", Codes: []ergo.CatalogEntry{{Code: 0, Message: "Closed"}}}
+	de := &ergo.Catalog{Domain: "shop", Locale: "de", Codes: []ergo.CatalogEntry{{Code: 3, Message: "Zu"}}}
+	domains := FromCatalogs([]*ergo.Catalog{cat, other, de, more})
+	c.Assert(domains, gc.HasLen, 2)
+	c.Check(domains[0].Name, gc.Equals, "bank")
+	c.Check(domains[1].Codes, gc.DeepEquals, []Code{
+		{Code: 0, Message: "Closed"},
+		{Code: 1, Message: "Uses `ticks`"},
+		{Code: 2, Name: "EOutOfStock", Message: "Out of stock", Status: 409, Severity: "warn",
+			Remediation: "Try again\nlater"},
+	})
+
+	var b strings.Builder
+	c.Assert(Markdown(&b, domains[1:]), gc.IsNil)
+	c.Check(b.String(), gc.Matches, "(?s).*\\| 1 \\|  \\|  \\|  \\| `` Uses `ticks` `` \\|  \\|\n.*")
+	c.Check(b.String(), gc.Matches, "(?s).*\\| 2 \\| EOutOfStock \\| 409 \\| warn \\| `Out of stock` \\| Try again<br>later \\|\n")
+
+	b.Reset()
+	c.Assert(HTML(&b, domains), gc.IsNil)
+	c.Check(b.String(), gc.Matches, `(?s)<!DOCTYPE html>.*<h2 id="bank">bank</h2>.*`)
+	c.Check(b.String(), gc.Matches, `(?s).*<tr id="shop-2"><td>2</td><td>EOutOfStock</td><td>409</td><td>warn</td>`+
+		`<td><code>Out of stock</code></td><td>Try again\nlater</td></tr>.*`)
+	c.Check(b.String(), gc.Matches, "(?s).*<code>Uses `ticks`</code>.*")
+}
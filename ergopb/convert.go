@@ -0,0 +1,123 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package ergopb defines a protocol buffer message for ergo errors,
+// so that gRPC services can attach them as typed error details.
+package ergopb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative ergo.proto
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/flaub/ergo"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ToProto converts an error into its protocol buffer form.
+// Info values are converted as they would be by encoding/json.
+func ToProto(err *ergo.Error) *Error {
+	return fromWire(err.ToWire())
+}
+
+// FromProto reconstructs an error from its protocol buffer form,
+// see ergo.Wire.ToError, which "opts" are passed to.
+func FromProto(pb *Error, opts ...ergo.Option) (*ergo.Error, error) {
+	return toWire(pb).ToError(opts...)
+}
+
+func fromWire(wire *ergo.Wire) *Error {
+	pb := &Error{
+		Version:  int32(wire.Version),
+		Domain:   wire.Domain,
+		Code:     int64(wire.Code),
+		Name:     wire.Name,
+		Context:  wire.Context,
+		Severity: int32(wire.Severity),
+	}
+	if len(wire.Info) != 0 {
+		pb.Info = &structpb.Struct{Fields: make(map[string]*structpb.Value, len(wire.Info))}
+		for key, value := range wire.Info {
+			pb.Info.Fields[key] = toValue(value)
+		}
+	}
+	for _, frame := range wire.Stack {
+		pb.Stack = append(pb.Stack, &Frame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     int64(frame.Line),
+		})
+	}
+	if wire.Inner != nil {
+		pb.Inner = fromWire(wire.Inner)
+	}
+	for _, cause := range wire.Causes {
+		pb.Causes = append(pb.Causes, fromWire(cause))
+	}
+	return pb
+}
+
+// toValue converts a value to a structpb.Value,
+// going through encoding/json for types structpb does not know.
+func toValue(value interface{}) *structpb.Value {
+	if v, err := structpb.NewValue(value); err == nil {
+		return v
+	}
+	if data, err := json.Marshal(value); err == nil {
+		var generic interface{}
+		if json.Unmarshal(data, &generic) == nil {
+			if v, err := structpb.NewValue(generic); err == nil {
+				return v
+			}
+		}
+	}
+	return structpb.NewStringValue(fmt.Sprintf("%v", value))
+}
+
+func toWire(pb *Error) *ergo.Wire {
+	wire := &ergo.Wire{
+		Version:  int(pb.GetVersion()),
+		Domain:   pb.GetDomain(),
+		Code:     ergo.ErrCode(pb.GetCode()),
+		Name:     pb.GetName(),
+		Context:  pb.GetContext(),
+		Severity: ergo.Severity(pb.GetSeverity()),
+	}
+	if pb.GetInfo() != nil {
+		wire.Info = ergo.ErrInfo(pb.GetInfo().AsMap())
+	}
+	for _, frame := range pb.GetStack() {
+		wire.Stack = append(wire.Stack, ergo.Frame{
+			Function: frame.GetFunction(),
+			File:     frame.GetFile(),
+			Line:     int(frame.GetLine()),
+		})
+	}
+	if pb.GetInner() != nil {
+		wire.Inner = toWire(pb.GetInner())
+	}
+	for _, cause := range pb.GetCauses() {
+		wire.Causes = append(wire.Causes, toWire(cause))
+	}
+	return wire
+}
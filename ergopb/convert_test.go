@@ -0,0 +1,95 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergopb
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"testing"
+	"time"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("ergopb", ergo.DomainMap{
+		0: "Outer {{.name}}",
+		1: "Inner",
+	})
+	ergo.NameCodes("ergopb", map[ergo.ErrCode]string{1: "EInner"})
+}
+
+func (t *TestSuite) TestRoundTrip(c *gc.C) {
+	err := ergo.Chain(ergo.Wrap(io.EOF), ergo.New(0, "ergopb", 0,
+		"name", "x",
+		"count", 3,
+		"timeout", time.Second,
+		"code", ergo.ErrCode(7),
+		"list", []interface{}{"a", true},
+	)).(*ergo.Error)
+	err.Causes = []*ergo.Error{ergo.New(0, "ergopb", 1, ergo.WithSeverity(ergo.SeverityWarn))}
+
+	pb := ToProto(err)
+	c.Check(pb.GetVersion(), gc.Equals, int32(ergo.SchemaVersion))
+	c.Check(pb.GetInner().GetVersion(), gc.Equals, int32(0))
+	c.Check(pb.GetCauses()[0].GetName(), gc.Equals, "EInner")
+	data, perr := proto.Marshal(pb)
+	c.Assert(perr, gc.IsNil)
+
+	var decoded Error
+	c.Assert(proto.Unmarshal(data, &decoded), gc.IsNil)
+	restored, perr := FromProto(&decoded)
+	c.Assert(perr, gc.IsNil)
+	c.Check(restored.Error(), gc.Equals, err.Error())
+	c.Check(restored.Info, gc.DeepEquals, ergo.ErrInfo{
+		"name":    "x",
+		"count":   3.0,
+		"timeout": float64(time.Second),
+		"code":    7.0,
+		"list":    []interface{}{"a", true},
+	})
+	c.Check(restored.Inner.Message(), gc.Equals, "Error: EOF")
+	c.Check(restored.Causes[0].Message(), gc.Equals, "Inner")
+	c.Check(restored.Causes[0].ToWire().Name, gc.Equals, "EInner")
+	c.Check(restored.Causes[0].Severity, gc.Equals, ergo.SeverityWarn)
+	c.Check(restored.Severity, gc.Equals, ergo.SeverityUnset)
+}
+
+func (t *TestSuite) TestValidation(c *gc.C) {
+	_, err := FromProto(&Error{Domain: "ergopb", Code: 5})
+	c.Check(err, gc.ErrorMatches, "ergo: \\[ergopb:5\\] is not defined")
+	_, err = FromProto(&Error{Domain: "ergopb", Inner: &Error{}})
+	c.Check(err, gc.ErrorMatches, "ergo: error has no domain")
+	_, err = FromProto(&Error{Domain: "ergopb", Code: 0, Name: "EInner"})
+	c.Check(err, gc.ErrorMatches, "ergo: \\[ergopb:0\\] is named EInner, but EInner is 1")
+}
@@ -0,0 +1,291 @@
+// Protocol buffer definition of ergo errors.
+// This mirrors the layout of ergo.Wire.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.29.3
+// source: ergo.proto
+
+package ergopb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Error is the serialized form of an ergo error.
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The schema version, only set on the outermost error.
+	Version int32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// The domain of this error.
+	Domain string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// The error code of this error.
+	Code int64 `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	// The name of the code, if any, see ergo.NameCodes.
+	Name string `protobuf:"bytes,10,opt,name=name,proto3" json:"name,omitempty"`
+	// A collection of named values associated with this error.
+	Info *structpb.Struct `protobuf:"bytes,4,opt,name=info,proto3" json:"info,omitempty"`
+	// Additional context to help developers determine the source of an error.
+	Context string `protobuf:"bytes,5,opt,name=context,proto3" json:"context,omitempty"`
+	// The stack trace captured when this error was created.
+	Stack []*Frame `protobuf:"bytes,6,rep,name=stack,proto3" json:"stack,omitempty"`
+	// The inner error of the chain.
+	Inner *Error `protobuf:"bytes,7,opt,name=inner,proto3" json:"inner,omitempty"`
+	// Additional causes of this error.
+	Causes []*Error `protobuf:"bytes,8,rep,name=causes,proto3" json:"causes,omitempty"`
+	// The seriousness of this error, see ergo.Severity.
+	Severity      int32 `protobuf:"varint,9,opt,name=severity,proto3" json:"severity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_ergo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_ergo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_ergo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Error) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Error) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Error) GetCode() int64 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Error) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Error) GetInfo() *structpb.Struct {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *Error) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *Error) GetStack() []*Frame {
+	if x != nil {
+		return x.Stack
+	}
+	return nil
+}
+
+func (x *Error) GetInner() *Error {
+	if x != nil {
+		return x.Inner
+	}
+	return nil
+}
+
+func (x *Error) GetCauses() []*Error {
+	if x != nil {
+		return x.Causes
+	}
+	return nil
+}
+
+func (x *Error) GetSeverity() int32 {
+	if x != nil {
+		return x.Severity
+	}
+	return 0
+}
+
+// Frame is a single frame of a stack trace.
+type Frame struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Function      string                 `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
+	File          string                 `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Line          int64                  `protobuf:"varint,3,opt,name=line,proto3" json:"line,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Frame) Reset() {
+	*x = Frame{}
+	mi := &file_ergo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Frame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Frame) ProtoMessage() {}
+
+func (x *Frame) ProtoReflect() protoreflect.Message {
+	mi := &file_ergo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Frame.ProtoReflect.Descriptor instead.
+func (*Frame) Descriptor() ([]byte, []int) {
+	return file_ergo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Frame) GetFunction() string {
+	if x != nil {
+		return x.Function
+	}
+	return ""
+}
+
+func (x *Frame) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Frame) GetLine() int64 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+var File_ergo_proto protoreflect.FileDescriptor
+
+const file_ergo_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"ergo.proto\x12\x04ergo\x1a\x1cgoogle/protobuf/struct.proto\"\xaf\x02\n" +
+	"\x05Error\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\x03R\x04code\x12\x12\n" +
+	"\x04name\x18\n" +
+	" \x01(\tR\x04name\x12+\n" +
+	"\x04info\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x04info\x12\x18\n" +
+	"\acontext\x18\x05 \x01(\tR\acontext\x12!\n" +
+	"\x05stack\x18\x06 \x03(\v2\v.ergo.FrameR\x05stack\x12!\n" +
+	"\x05inner\x18\a \x01(\v2\v.ergo.ErrorR\x05inner\x12#\n" +
+	"\x06causes\x18\b \x03(\v2\v.ergo.ErrorR\x06causes\x12\x1a\n" +
+	"\bseverity\x18\t \x01(\x05R\bseverity\"K\n" +
+	"\x05Frame\x12\x1a\n" +
+	"\bfunction\x18\x01 \x01(\tR\bfunction\x12\x12\n" +
+	"\x04file\x18\x02 \x01(\tR\x04file\x12\x12\n" +
+	"\x04line\x18\x03 \x01(\x03R\x04lineB\x1eZ\x1cgithub.com/flaub/ergo/ergopbb\x06proto3"
+
+var (
+	file_ergo_proto_rawDescOnce sync.Once
+	file_ergo_proto_rawDescData []byte
+)
+
+func file_ergo_proto_rawDescGZIP() []byte {
+	file_ergo_proto_rawDescOnce.Do(func() {
+		file_ergo_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ergo_proto_rawDesc), len(file_ergo_proto_rawDesc)))
+	})
+	return file_ergo_proto_rawDescData
+}
+
+var file_ergo_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_ergo_proto_goTypes = []any{
+	(*Error)(nil),           // 0: ergo.Error
+	(*Frame)(nil),           // 1: ergo.Frame
+	(*structpb.Struct)(nil), // 2: google.protobuf.Struct
+}
+var file_ergo_proto_depIdxs = []int32{
+	2, // 0: ergo.Error.info:type_name -> google.protobuf.Struct
+	1, // 1: ergo.Error.stack:type_name -> ergo.Frame
+	0, // 2: ergo.Error.inner:type_name -> ergo.Error
+	0, // 3: ergo.Error.causes:type_name -> ergo.Error
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_ergo_proto_init() }
+func file_ergo_proto_init() {
+	if File_ergo_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ergo_proto_rawDesc), len(file_ergo_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_ergo_proto_goTypes,
+		DependencyIndexes: file_ergo_proto_depIdxs,
+		MessageInfos:      file_ergo_proto_msgTypes,
+	}.Build()
+	File_ergo_proto = out.File
+	file_ergo_proto_goTypes = nil
+	file_ergo_proto_depIdxs = nil
+}
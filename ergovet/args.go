@@ -0,0 +1,104 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergovet
+
+import (
+	"go/ast"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// ArgsAnalyzer checks the key-value arguments of ergo.New, ergo.Wrap
+// and the other functions and methods taking Info as "args". It reports an odd number
+// of arguments, keys which are not strings, as they panic at runtime,
+// and keys given twice, as only the last value is kept.
+// Options may appear anywhere among the arguments. An argument whose
+// type could hold an Option at runtime, such as interface{}, may or may
+// not take a position, so only what holds either way is reported after it.
+var ArgsAnalyzer = &analysis.Analyzer{
+	Name:     "ergoargs",
+	Doc:      "check the key-value arguments of ergo.New and ergo.Wrap",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runArgs,
+}
+
+func runArgs(pass *analysis.Pass) (interface{}, error) {
+	funcs := keyValueFuncs(pass)
+	if len(funcs) == 0 {
+		return nil, nil
+	}
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		name, args, ok := keyValueCall(pass, funcs, call)
+		if !ok {
+			return
+		}
+		seen := make(map[string]bool)
+		// Whether the next argument may be a key, or a value.
+		key, value := true, false
+		for _, arg := range args {
+			t := pass.TypesInfo.TypeOf(arg)
+			if t == nil || isOption(t) {
+				continue
+			}
+			if mayHoldOption(t) {
+				key, value = key || value, key || value
+				continue
+			}
+			if value {
+				key, value = true, key
+				continue
+			}
+			key, value = false, true
+			if !isString(t) {
+				pass.Reportf(arg.Pos(), "key %v to %v must be a string, not %v",
+					types.ExprString(arg), name, t)
+				continue
+			}
+			k, ok := constString(pass, arg)
+			if !ok {
+				continue
+			}
+			if seen[k] {
+				pass.Reportf(arg.Pos(), "duplicate key %q in arguments to %v", k, name)
+			} else {
+				seen[k] = true
+			}
+		}
+		if !key {
+			pass.Reportf(call.Rparen, "odd number of key-value arguments to %v", name)
+		}
+	})
+	return nil, nil
+}
+
+// isString reports whether a type is string itself, since ergo asserts
+// keys to string: other string types panic as well.
+func isString(t types.Type) bool {
+	return types.Identical(t, types.Typ[types.String]) ||
+		types.Identical(t, types.Typ[types.UntypedString])
+}
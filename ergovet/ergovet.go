@@ -0,0 +1,126 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package ergovet provides static checks of the use of ergo,
+// catching at build time mistakes which otherwise panic or render
+// broken messages at runtime. Run them with the ergovet command:
+//
+//	go vet -vettool=$(which ergovet) ./...
+package ergovet
+
+import (
+	"go/ast"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+	"strings"
+)
+
+const ergoPath = "github.com/flaub/ergo"
+
+// Analyzers holds every analyzer of this package.
+var Analyzers = []*analysis.Analyzer{
+	ArgsAnalyzer,
+	FieldsAnalyzer,
+}
+
+// keyValueNames lists the functions and methods of ergo taking Info
+// as key-value "args" after their fixed parameters.
+var keyValueNames = []string{
+	"New", "Wrap", "WrapAs", "DeferWrap", "Check", "Recovered",
+	"DomainHandle.New", "DomainHandle.Wrap",
+}
+
+// keyValueFuncs resolves keyValueNames, see lookupFuncs.
+func keyValueFuncs(pass *analysis.Pass) map[*types.Func]string {
+	return lookupFuncs(pass, keyValueNames)
+}
+
+// lookupFuncs resolves the functions and methods of ergo named by
+// "names", such as "New" or "DomainHandle.New", in the ergo package
+// imported by the package under analysis, mapping each function to its
+// qualified name. It is empty if ergo is not imported.
+func lookupFuncs(pass *analysis.Pass, names []string) map[*types.Func]string {
+	funcs := make(map[*types.Func]string)
+	pkg := pass.Pkg
+	if pkg.Path() != ergoPath {
+		pkg = nil
+		for _, imp := range pass.Pkg.Imports() {
+			if imp.Path() == ergoPath {
+				pkg = imp
+			}
+		}
+	}
+	if pkg == nil {
+		return funcs
+	}
+	for _, name := range names {
+		var obj types.Object
+		if typ, method, ok := strings.Cut(name, "."); ok {
+			if tn, isType := pkg.Scope().Lookup(typ).(*types.TypeName); isType {
+				obj, _, _ = types.LookupFieldOrMethod(types.NewPointer(tn.Type()), false, pkg, method)
+			}
+		} else {
+			obj = pkg.Scope().Lookup(name)
+		}
+		if fn, ok := obj.(*types.Func); ok {
+			funcs[fn] = "ergo." + name
+		}
+	}
+	return funcs
+}
+
+// keyValueCall matches calls to one of "funcs", see lookupFuncs.
+// It returns the name of the callee and the arguments from the first key,
+// reporting false otherwise. Calls passing a slice with "args..."
+// are not matched.
+func keyValueCall(pass *analysis.Pass, funcs map[*types.Func]string, call *ast.CallExpr) (string, []ast.Expr, bool) {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || call.Ellipsis.IsValid() {
+		return "", nil, false
+	}
+	name, ok := funcs[fn.Origin()]
+	if !ok {
+		return "", nil, false
+	}
+	fixed := fn.Type().(*types.Signature).Params().Len() - 1
+	if len(call.Args) < fixed {
+		return "", nil, false
+	}
+	return name, call.Args[fixed:], true
+}
+
+// mayHoldOption reports whether a value of a type may hold an ergo.Option
+// at runtime: Option has no methods, so only interfaces without methods,
+// such as interface{}, may hold one.
+func mayHoldOption(t types.Type) bool {
+	iface, ok := t.Underlying().(*types.Interface)
+	return ok && iface.NumMethods() == 0
+}
+
+// isOption reports whether a type is ergo.Option.
+func isOption(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == ergoPath && named.Obj().Name() == "Option"
+}
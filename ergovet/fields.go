@@ -0,0 +1,378 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergovet
+
+import (
+	"github.com/flaub/ergo"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+	"sort"
+	"strings"
+)
+
+// FieldsAnalyzer checks that calls creating errors, such as ergo.New,
+// ergo.NewE and the methods of ergo.DomainHandle, pass the Info keys
+// used by the message format of their code, which would otherwise
+// render "<no value>". Keys given with ergo.WithInfo count as passed.
+// Domains are found where ergo.Domain or ergo.NewDomain is called
+// with constant names and DomainMap literals, including those given
+// to ergo.Merge, in the checked package and the packages it imports.
+// Calls with a domain or code which is not constant, with keys which
+// are not constant, or with a handle which is not a package variable
+// initialized by ergo.NewDomain, are not checked.
+var FieldsAnalyzer = &analysis.Analyzer{
+	Name:      "ergofields",
+	Doc:       "check that calls creating errors pass the keys used by their message",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(domainsFact)},
+	Run:       runFields,
+}
+
+// domainsFact holds the keys used by the message formats
+// of the domains defined by a package, by domain and code,
+// and the domains of its DomainHandle variables, by variable name.
+type domainsFact struct {
+	Domains map[string]map[int64][]string
+	Handles map[string]string
+}
+
+// constructors maps the functions and methods of ergo creating errors
+// to the positions of their domain and code arguments. The keys follow
+// the code. A negative domain position stands for the DomainHandle
+// the method is called on.
+var constructors = map[string][2]int{
+	"New":               {1, 2},
+	"NewE":              {0, 1},
+	"WrapAs":            {1, 2},
+	"DeferWrap":         {1, 2},
+	"Check":             {1, 2},
+	"Recovered":         {1, 2},
+	"DomainHandle.New":  {-1, 0},
+	"DomainHandle.Wrap": {-1, 1},
+}
+
+func (*domainsFact) AFact() {}
+
+func (fact *domainsFact) String() string {
+	names := make([]string, 0, len(fact.Domains))
+	for name := range fact.Domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "domains(" + strings.Join(names, ", ") + ")"
+}
+
+func runFields(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	own := &domainsFact{
+		Domains: make(map[string]map[int64][]string),
+		Handles: handles(pass),
+	}
+	literals := mapLiterals(pass)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		if !isErgoFunc(pass, call, "Domain") && !isErgoFunc(pass, call, "NewDomain") || len(call.Args) < 2 {
+			return
+		}
+		name, ok := constString(pass, call.Args[0])
+		if !ok {
+			return
+		}
+		maps := []ast.Expr{call.Args[1]}
+		for _, opt := range call.Args[2:] {
+			if opt, ok := opt.(*ast.CallExpr); ok && isErgoFunc(pass, opt, "Merge") {
+				maps = append(maps, opt.Args...)
+			}
+		}
+		for _, m := range maps {
+			addKeys(pass, own, name, literals, m)
+		}
+	})
+	if len(own.Domains) != 0 || len(own.Handles) != 0 {
+		pass.ExportPackageFact(own)
+	}
+
+	domains := own.Domains
+	for _, fact := range pass.AllPackageFacts() {
+		if imported, ok := fact.Fact.(*domainsFact); ok && fact.Package != pass.Pkg {
+			for name, codes := range imported.Domains {
+				if _, ok := domains[name]; !ok {
+					domains[name] = codes
+				}
+			}
+		}
+	}
+	if len(domains) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(constructors))
+	for name := range constructors {
+		names = append(names, name)
+	}
+	funcs := lookupFuncs(pass, names)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || call.Ellipsis.IsValid() {
+			return
+		}
+		callee, ok := funcs[fn.Origin()]
+		if !ok {
+			return
+		}
+		pos := constructors[strings.TrimPrefix(callee, "ergo.")]
+		if len(call.Args) <= pos[1] {
+			return
+		}
+		var name string
+		if pos[0] < 0 {
+			sel, isSel := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+			if !isSel {
+				return
+			}
+			name, ok = handleDomain(pass, own, sel.X)
+		} else {
+			name, ok = constString(pass, call.Args[pos[0]])
+		}
+		if !ok {
+			return
+		}
+		code, ok := constInt(pass, call.Args[pos[1]])
+		if !ok {
+			return
+		}
+		used, ok := domains[name][code]
+		if !ok {
+			return
+		}
+		passed, ok := passedKeys(pass, call.Args[pos[1]+1:])
+		if !ok {
+			return
+		}
+		var missing []string
+		for _, key := range used {
+			if !passed[key] {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) != 0 {
+			pass.Reportf(call.Pos(), "message of [%v:%d] uses keys not passed to %v: %v",
+				name, code, callee, strings.Join(missing, ", "))
+		}
+	})
+	return nil, nil
+}
+
+// handles returns the domains of the package variables initialized
+// by ergo.NewDomain with a constant name, by variable name.
+func handles(pass *analysis.Pass) map[string]string {
+	handles := make(map[string]string)
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec, ok := spec.(*ast.ValueSpec)
+				if !ok || len(spec.Names) != len(spec.Values) {
+					continue
+				}
+				for i, value := range spec.Values {
+					call, ok := value.(*ast.CallExpr)
+					if !ok || !isErgoFunc(pass, call, "NewDomain") || len(call.Args) == 0 {
+						continue
+					}
+					if name, ok := constString(pass, call.Args[0]); ok {
+						handles[spec.Names[i].Name] = name
+					}
+				}
+			}
+		}
+	}
+	return handles
+}
+
+// handleDomain returns the domain of a DomainHandle expression,
+// if it is a package variable found by handles, in this package
+// or an imported one.
+func handleDomain(pass *analysis.Pass, own *domainsFact, expr ast.Expr) (string, bool) {
+	var ident *ast.Ident
+	switch x := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = x
+	case *ast.SelectorExpr:
+		ident = x.Sel
+	default:
+		return "", false
+	}
+	v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return "", false
+	}
+	fact := own
+	if v.Pkg() != pass.Pkg {
+		fact = new(domainsFact)
+		if !pass.ImportPackageFact(v.Pkg(), fact) {
+			return "", false
+		}
+	}
+	name, ok := fact.Handles[v.Name()]
+	return name, ok
+}
+
+// mapLiterals returns the composite literals initializing
+// the package variables, such as DomainMaps.
+func mapLiterals(pass *analysis.Pass) map[types.Object]*ast.CompositeLit {
+	literals := make(map[types.Object]*ast.CompositeLit)
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec, ok := spec.(*ast.ValueSpec)
+				if !ok || len(spec.Names) != len(spec.Values) {
+					continue
+				}
+				for i, value := range spec.Values {
+					if lit, ok := value.(*ast.CompositeLit); ok {
+						literals[pass.TypesInfo.Defs[spec.Names[i]]] = lit
+					}
+				}
+			}
+		}
+	}
+	return literals
+}
+
+// addKeys adds the keys used by a DomainMap literal, or a package
+// variable initialized with one, to the keys of a domain.
+func addKeys(pass *analysis.Pass, fact *domainsFact, name string, literals map[types.Object]*ast.CompositeLit, expr ast.Expr) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if ident, isIdent := expr.(*ast.Ident); isIdent {
+		lit, ok = literals[pass.TypesInfo.Uses[ident]]
+	}
+	if !ok {
+		return
+	}
+	codes := fact.Domains[name]
+	if codes == nil {
+		codes = make(map[int64][]string)
+		fact.Domains[name] = codes
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		code, ok := constInt(pass, kv.Key)
+		if !ok {
+			continue
+		}
+		format, ok := constString(pass, kv.Value)
+		if !ok {
+			continue
+		}
+		if keys, err := ergo.MessageKeys(format); err == nil {
+			codes[code] = keys
+		}
+	}
+}
+
+// passedKeys returns the keys passed as key-value "args" or with
+// ergo.WithInfo, reporting false if they cannot be determined.
+// Options other than calls to ergo functions may carry keys as well,
+// and so may arguments whose type could hold an Option.
+func passedKeys(pass *analysis.Pass, args []ast.Expr) (map[string]bool, bool) {
+	keys := make(map[string]bool)
+	key := true
+	for _, arg := range args {
+		t := pass.TypesInfo.TypeOf(arg)
+		if t == nil {
+			continue
+		}
+		if isOption(t) {
+			call, ok := ast.Unparen(arg).(*ast.CallExpr)
+			if !ok || !isErgoCall(pass, call) {
+				return nil, false
+			}
+			if isErgoFunc(pass, call, "WithInfo") && len(call.Args) == 2 {
+				k, ok := constString(pass, call.Args[0])
+				if !ok {
+					return nil, false
+				}
+				keys[k] = true
+			}
+			continue
+		}
+		if mayHoldOption(t) {
+			return nil, false
+		}
+		if key {
+			k, ok := constString(pass, arg)
+			if !ok {
+				return nil, false
+			}
+			keys[k] = true
+		}
+		key = !key
+	}
+	return keys, true
+}
+
+// isErgoCall reports whether a call calls a function or method of ergo.
+func isErgoCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == ergoPath
+}
+
+// isErgoFunc reports whether a call calls a function of ergo.
+func isErgoFunc(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == ergoPath && fn.Name() == name &&
+		fn.Type().(*types.Signature).Recv() == nil
+}
+
+func constString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	value := pass.TypesInfo.Types[expr].Value
+	if value == nil || value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(value), true
+}
+
+func constInt(pass *analysis.Pass, expr ast.Expr) (int64, bool) {
+	value := pass.TypesInfo.Types[expr].Value
+	if value == nil || value.Kind() != constant.Int {
+		return 0, false
+	}
+	return constant.Int64Val(value)
+}
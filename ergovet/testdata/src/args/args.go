@@ -0,0 +1,42 @@
+package args
+
+import (
+	"errors"
+
+	"github.com/flaub/ergo"
+)
+
+type key string
+
+const userKey = "user"
+
+func calls(name string, k key, v interface{}, rest []interface{}, err error) {
+	ergo.New(0, "d", 1)
+	ergo.New(0, "d", 1, "user", name, "count", 3)
+	ergo.New(0, "d", 1, "user", name, ergo.WithSeverity(3), "count", 3)
+	ergo.New(0, "d", 1, name, 3)
+	ergo.New(0, "d", 1, rest...)
+	ergo.Chain(nil, "x")
+
+	ergo.New(0, "d", 1, "user")                       // want `odd number of key-value arguments to ergo.New`
+	ergo.Wrap(errors.New("x"), "user", name, "count") // want `odd number of key-value arguments to ergo.Wrap`
+	ergo.New(0, "d", 1, 42, name)                     // want `key 42 to ergo.New must be a string, not int`
+	ergo.New(0, "d", 1, k, name)                      // want `key k to ergo.New must be a string, not args.key`
+	ergo.New(0, "d", 1, "user", name, userKey, name)  // want `duplicate key "user" in arguments to ergo.New`
+	ergo.New(0, "d", 1, "user", name, v, 42, "count")
+	ergo.New(0, "d", 1, "cause", err, "user", name)
+	ergo.New(0, "d", 1, "cause", err, 42, "x")      // want `key 42 to ergo.New must be a string, not int`
+	ergo.New(0, "d", 1, "cause", err, "dangling")   // want `odd number of key-value arguments to ergo.New`
+	ergo.New(0, "d", 1, "cause", err, "cause", err) // want `duplicate key "cause" in arguments to ergo.New`
+	ergo.New(0, "d", 1, v, "user", "count")
+	ergo.New(0, "d", 1, "user", v, "count")
+
+	var d *ergo.DomainHandle
+	d.New(1, "user", name)
+	d.New(1, "user")                   // want `odd number of key-value arguments to ergo.DomainHandle.New`
+	d.Wrap(errors.New("x"), 1, "user") // want `odd number of key-value arguments to ergo.DomainHandle.Wrap`
+	d.Errorf(1, "%v", name)
+	ergo.WrapAs(errors.New("x"), "d", 1, 42, name) // want `key 42 to ergo.WrapAs must be a string, not int`
+	ergo.Sprint(name)
+	ergo.Wrap(errors.New("x"), "user", name, rest) // want `key rest to ergo.Wrap must be a string, not \[\]interface\{\}` `odd number`
+}
@@ -0,0 +1,32 @@
+package fields
+
+import (
+	"fieldsdef"
+
+	"github.com/flaub/ergo"
+)
+
+func calls(id int, key string, v interface{}, args []interface{}, err error, opt ergo.Option) {
+	ergo.New(0, fieldsdef.Domain, fieldsdef.ENotFound, "id", id, "user", "u")
+	ergo.New(0, "billing", fieldsdef.EPaid)
+	ergo.New(0, "billing", fieldsdef.ELocked)
+	ergo.New(0, "billing", fieldsdef.EDeclined, "cards", []string{"visa"}, ergo.WithSeverity(3))
+	ergo.New(0, "billing", fieldsdef.ENotFound, key, id)
+	ergo.New(0, "billing", fieldsdef.ENotFound, v)
+	ergo.New(0, "billing", fieldsdef.ENotFound, args...)
+	ergo.New(0, "shipping", fieldsdef.ENotFound)
+	ergo.New(0, "billing", fieldsdef.ENotFound, ergo.WithInfo("id", id), "user", "u")
+	ergo.New(0, "billing", fieldsdef.ENotFound, "id", err, "user", "u")
+	ergo.New(0, "billing", fieldsdef.ENotFound, opt)
+	ergo.NewE("billing", fieldsdef.ENotFound, ergo.WithInfo("id", id), ergo.WithInfo("user", "u"))
+	fieldsdef.Orders.New(0, "id", id)
+	ergo.WrapAs(err, "billing", fieldsdef.EPaid)
+
+	ergo.New(0, fieldsdef.Domain, fieldsdef.ENotFound, "id", id)          // want `message of \[billing:1\] uses keys not passed to ergo.New: user`
+	ergo.New(0, "billing", 3)                                             // want `message of \[billing:3\] uses keys not passed to ergo.New: cards`
+	ergo.New(0, "billing", fieldsdef.ENotFound, ergo.WithInfo("id", id))  // want `message of \[billing:1\] uses keys not passed to ergo.New: user`
+	ergo.New(0, "billing", fieldsdef.ENotFound, "id", err)                // want `message of \[billing:1\] uses keys not passed to ergo.New: user`
+	ergo.NewE("billing", fieldsdef.ENotFound, ergo.WithInfo("user", "u")) // want `message of \[billing:1\] uses keys not passed to ergo.NewE: id`
+	fieldsdef.Orders.New(0)                                               // want `message of \[orders:0\] uses keys not passed to ergo.DomainHandle.New: id`
+	ergo.WrapAs(err, "billing", 3)                                        // want `message of \[billing:3\] uses keys not passed to ergo.WrapAs: cards`
+}
@@ -0,0 +1,28 @@
+package fieldsdef // want package:"domains\\(billing, local, orders\\)"
+
+import "github.com/flaub/ergo"
+
+const Domain = "billing"
+
+const (
+	ENotFound ergo.ErrCode = iota + 1
+	EPaid
+	EDeclined
+	ELocked
+)
+
+var billingDomain = ergo.DomainMap{
+	ENotFound: "The invoice {{.id}} of {{.user}} was not found",
+	EPaid:     "Already paid",
+}
+
+var Orders = ergo.NewDomain("orders", ergo.DomainMap{0: "Order {{.id}}"})
+
+func init() {
+	ergo.Domain(Domain, billingDomain, ergo.Merge(ergo.DomainMap{
+		EDeclined: "{{range .cards}}{{.}}{{end}} declined",
+	}))
+	ergo.Domain("local", ergo.DomainMap{0: "{{.x}}"})
+	ergo.New(0, "local", 0) // want `message of \[local:0\] uses keys not passed to ergo.New: x`
+	Orders.New(0)           // want `message of \[orders:0\] uses keys not passed to ergo.DomainHandle.New: id`
+}
@@ -0,0 +1,46 @@
+// Package ergo is a stub of the functions checked by ergovet.
+package ergo
+
+type ErrCode int
+
+type DomainMap map[ErrCode]string
+
+type DomainOption func(*domainOptions)
+
+type domainOptions struct{}
+
+func Domain(name string, domain DomainMap, opts ...DomainOption) {}
+
+func Merge(maps ...DomainMap) DomainOption { return nil }
+
+type Error struct{}
+
+type Option func(*options)
+
+type options struct{}
+
+func WithSeverity(s int) Option { return nil }
+
+func WithInfo(key string, value interface{}) Option { return nil }
+
+func New(skip int, domain string, code ErrCode, args ...interface{}) *Error { return nil }
+
+func NewE(domain string, code ErrCode, opts ...Option) *Error { return nil }
+
+func Wrap(x interface{}, args ...interface{}) *Error { return nil }
+
+func Chain(err error, rest ...interface{}) *Error { return nil }
+
+type DomainHandle struct{}
+
+func NewDomain(name string, domain DomainMap, opts ...DomainOption) *DomainHandle { return nil }
+
+func (d *DomainHandle) New(code ErrCode, args ...interface{}) *Error { return nil }
+
+func WrapAs(err error, domain string, code ErrCode, args ...interface{}) *Error { return nil }
+
+func Sprint(args ...interface{}) string { return "" }
+
+func (d *DomainHandle) Wrap(err error, code ErrCode, args ...interface{}) *Error { return nil }
+
+func (d *DomainHandle) Errorf(code ErrCode, format string, a ...interface{}) *Error { return nil }
@@ -26,9 +26,11 @@ package ergo
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"log"
-	"runtime"
+	"strings"
+	"sync"
 	"text/template"
 )
 
@@ -59,22 +61,67 @@ type Error struct {
 	// A collection of named values associated with this error.
 	Info ErrInfo `json:",omitempty"`
 
+	// The seriousness of this error, see WithSeverity.
+	Severity Severity `json:",omitempty"`
+
 	// Additional context to help developers determine the source of an error.
-	// In go, this is a stack trace. In C++, this could be file:line.
+	// In C++, this could be file:line.
+	// Errors created in go record a structured Stack instead,
+	// unless the StackCaller mode is in effect.
 	Context string `json:",omitempty"`
 
+	// The stack trace captured when this error was created.
+	Stack *Stack `json:",omitempty"`
+
 	// Used for defining a chain of errors.
 	// The innermost error represents the original error.
 	Inner *Error `json:",omitempty"`
+
+	// Additional causes of this error, see Join.
+	Causes []*Error `json:",omitempty"`
+
+	// The original error passed to Wrap, if any.
+	// Only its string form (Info["_err"]) is serialized.
+	wrapped error
+
+	// The value recovered by Recover, if any. It is not serialized.
+	panicked interface{}
+}
+
+// Target identifies every error with a given domain and code.
+// It is intended to be used as the target of errors.Is.
+type Target struct {
+	Domain string
+	Code   ErrCode
 }
 
 var (
-	domains = make(map[string]FormatFunc)
+	// The FormatFunc of each domain, read while errors are formatted.
+	domains sync.Map
+
+	// The definitions of domains defined with Domain,
+	// replaced as a whole on every update, see UpdateDomain.
+	defined sync.Map
 )
 
+// definition holds the message formats of a domain defined with Domain.
+type definition struct {
+	tmpls   templates
+	catalog DomainMap
+}
+
 func init() {
+	defineGo()
+}
+
+// defineGo defines the domain of wrapped go errors.
+func defineGo() {
 	DomainFunc("go", func(err *Error) string {
-		return "Error: " + err.Info["_err"].(string)
+		msg, _ := err.Info["_err"].(string)
+		if note, ok := err.Info["_note"].(string); ok {
+			msg = note + ": " + msg
+		}
+		return "Error: " + msg
 	})
 }
 
@@ -83,28 +130,92 @@ func init() {
 // a value of 0 means the stack will start at the call site of Make().
 // "args" is a set of pairs to be used to populate "Info":
 // first is the key, second is the value.
+// "args" may also contain Options, which are applied instead.
+//
+// Deprecated: use NewE, whose options replace the positional "skip"
+// and the key-value pairs.
 func New(skip int, domain string, code ErrCode, args ...interface{}) *Error {
-	err := &Error{
-		Domain:  domain,
-		Code:    code,
-		Info:    make(ErrInfo),
-		Context: stackTrace(skip + 2),
-	}
+	err := allocError(domain, code)
+	opts := defaultOptions()
 	var name string
 	for _, arg := range args {
-		if name == "" {
+		if opt, ok := arg.(Option); ok {
+			opt(&opts)
+		} else if name == "" {
 			name = arg.(string)
 		} else {
 			err.Info[name] = arg
 			name = ""
 		}
 	}
+	for i := 0; i < len(opts.info); i += 2 {
+		err.Info[opts.info[i].(string)] = opts.info[i+1]
+	}
+	skip += opts.skip
+	switch {
+	case opts.depth == 0:
+	case stackMode(domain) == StackCaller || !sampled(domain, code):
+		if opts.pcs != nil {
+			err.Context = callerAt(opts.pcs)
+		} else {
+			err.Context = caller(skip + 2)
+		}
+	case opts.pcs != nil:
+		err.Stack = stackOf(opts.pcs, opts.depth)
+	default:
+		err.Stack = callers(skip+2, opts.depth)
+	}
+	if opts.inner != nil {
+		if inner, ok := opts.inner.(*Error); ok {
+			err.Inner = inner
+		} else {
+			err.Inner = _Wrap(skip+1, opts.inner)
+		}
+	}
+	err.Severity = opts.severity
+	err.wrapped = opts.wrapped
+	err.Causes = opts.causes
+	err.panicked = opts.panicked
+	runHooks(err)
 	return err
 }
 
+// Wrapf wraps a go error like Wrap, attaching a message formatted with
+// fmt.Sprintf, which is kept in Info["_note"]:
+//
+//	ergo.Wrapf(err, "reading config %q", path).Message()
+//	// Error: reading config "app.yaml": open app.yaml: no such file or directory
+//
+// An Error becomes the inner error of the result.
+// If "err" is nil, nil is returned.
+func Wrapf(err error, format string, a ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+	note := fmt.Sprintf(format, a...)
+	if inner, ok := err.(*Error); ok {
+		return New(1, "go", 0, "_err", inner.Message(), "_note", note, WithInner(inner))
+	}
+	return _Wrap(1, err, "_note", note)
+}
+
+// NewE creates a new error, customized by options such as WithInfo,
+// WithInner, WithSkip and WithoutStack:
+//
+//	ergo.NewE("billing", ENotFound, ergo.WithInfo("id", id), ergo.WithInner(err))
+//
+// The stack starts at the caller, unless WithSkip is given.
+func NewE(domain string, code ErrCode, opts ...Option) *Error {
+	args := make([]interface{}, len(opts))
+	for i, opt := range opts {
+		args[i] = opt
+	}
+	return New(1, domain, code, args...)
+}
+
 func _Wrap(skip int, err error, args ...interface{}) *Error {
 	sys := []interface{}{"_err", err.Error()}
-	return New(skip+1, "go", 0, append(sys, args...)...)
+	return New(skip+1, "go", 0, append(append(sys, args...), WithWrapped(err))...)
 }
 
 // Wrap takes a generic interface "x" and returns an Error.
@@ -125,94 +236,503 @@ func Wrap(x interface{}, args ...interface{}) *Error {
 	return _Wrap(1, fmt.Errorf("%v", x), args...)
 }
 
+// WrapAs creates an error of a domain with "err" as its inner error,
+// in place of Chain(err, New(...)). Only the outer error has a stack,
+// since the inner one was raised at the same place.
+// If "err" is nil, nil is returned.
+func WrapAs(err error, domain string, code ErrCode, args ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+	return New(1, domain, code, append(args[:len(args):len(args)], WithInner(unstacked(err)))...)
+}
+
+// DeferWrap wraps the error returned by a function, if any, with an
+// error of a domain, see WrapAs. It is meant to be deferred by
+// functions with a named error result:
+//
+//	func loadConfig(path string) (err error) {
+//		defer ergo.DeferWrap(&err, "config", ELoad, "path", path)
+//		...
+//	}
+//
+// The stack starts at the function returning the error.
+func DeferWrap(errp *error, domain string, code ErrCode, args ...interface{}) {
+	if *errp == nil {
+		return
+	}
+	*errp = New(1, domain, code, append(args[:len(args):len(args)], WithInner(unstacked(*errp)))...)
+}
+
+// unstacked wraps an inner error without a stack of its own.
+func unstacked(err error) *Error {
+	if ergo, ok := err.(*Error); ok {
+		return ergo
+	}
+	return _Wrap(0, err, WithoutStack())
+}
+
+// Join creates an error with multiple causes,
+// for example a cleanup failure following an operation failure.
+// Nil errors, including a nil *Error, are discarded;
+// if all of "errs" are nil, nil is returned.
+func Join(errs ...error) *Error {
+	var causes []*Error
+	var msgs []string
+	for _, err := range errs {
+		if ergo, ok := err.(*Error); err == nil || ok && ergo == nil {
+			continue
+		}
+		cause := Wrap(err)
+		causes = append(causes, cause)
+		if cause.wrapped != nil {
+			msgs = append(msgs, err.Error())
+		} else {
+			msgs = append(msgs, cause.Message())
+		}
+	}
+	if causes == nil {
+		return nil
+	}
+	return New(1, "go", 0, "_err", strings.Join(msgs, "; "), withCauses(causes))
+}
+
 // Chain links an inner error to an outer one.
 // The result is the outer error.
+// Chain panics if the outer error is already part of the inner chain,
+// since the chain would then have no end.
 func Chain(inner error, err *Error) error {
 	if inner == nil {
 		return nil
 	}
-	err.Inner = Wrap(inner)
+	wrapped := Wrap(inner)
+	checkCycle(wrapped, err)
+	err.Inner = wrapped
 	return err
 }
 
 // Cause returns the cause of the error,
-// which is the innermost error in a chain.
+// which is the innermost error in a chain,
+// or the deepest one within the maximum depth (see SetMaxChainDepth).
 func Cause(err error) error {
 	if ergo, ok := err.(*Error); ok {
-		if ergo.Inner == nil {
+		var buf [8]*Error
+		links, _ := ergo.appendLinks(buf[:0], 0)
+		if len(links) == 0 {
 			return ergo
 		}
-		return Cause(ergo.Inner)
+		return links[len(links)-1]
 	}
 	return err
 }
 
+// RootCause returns the cause of the error, as Cause does, along with
+// its root: the final error reached by unwrapping the go error wrapped
+// by the cause with errors.Unwrap, such as a syscall error.
+// If the cause wraps nothing, the root is the cause itself.
+//
+//	cause, root := ergo.RootCause(err)
+//	// cause: [go:0] Error: open app.yaml: no such file or directory
+//	// root: syscall.ENOENT
+func RootCause(err error) (cause, root error) {
+	cause = Cause(err)
+	root = cause
+	for next := errors.Unwrap(root); next != nil; next = errors.Unwrap(root) {
+		root = next
+	}
+	return cause, root
+}
+
+// Unwrap returns the inner error, if any.
+// For an error created by Wrap, this is the original error value.
+// This allows chains built with Chain to be used with
+// errors.Is, errors.As and errors.Unwrap.
+func (err *Error) Unwrap() error {
+	if err.Inner != nil {
+		return err.Inner
+	}
+	if err.wrapped != nil {
+		return err.wrapped
+	}
+	return nil
+}
+
+// Is reports whether this error has the same domain and code as "target".
+// "target" may be a Target or another *Error.
+// Any additional Causes are searched as well.
+func (err *Error) Is(target error) bool {
+	if err.is(target) || err.Inner != nil && err.wrapped != nil && errors.Is(err.wrapped, target) {
+		return true
+	}
+	found := false
+	err.walkCauses(func(link *Error) bool {
+		found = link.is(target) || link.wrapped != nil && errors.Is(link.wrapped, target)
+		return !found
+	})
+	return found
+}
+
+// is reports whether this error alone matches "target", see Is.
+func (err *Error) is(target error) bool {
+	switch t := target.(type) {
+	case Target:
+		return err.Domain == t.Domain && err.Code == t.Code
+	case *Error:
+		return t != nil && err.Domain == t.Domain && err.Code == t.Code
+	}
+	return false
+}
+
+// As implements the interface used by errors.As.
+// Besides **Error, "target" may be a *Target,
+// which receives the domain and code of this error.
+func (err *Error) As(target interface{}) bool {
+	if err.as(target) || err.Inner != nil && err.wrapped != nil && errors.As(err.wrapped, target) {
+		return true
+	}
+	found := false
+	err.walkCauses(func(link *Error) bool {
+		found = link.as(target) || link.wrapped != nil && errors.As(link.wrapped, target)
+		return !found
+	})
+	return found
+}
+
+// as assigns this error alone to "target", see As.
+func (err *Error) as(target interface{}) bool {
+	switch t := target.(type) {
+	case **Error:
+		*t = err
+		return true
+	case *Target:
+		*t = Target{Domain: err.Domain, Code: err.Code}
+		return true
+	}
+	return false
+}
+
+// walkCauses walks the chains of the additional Causes of this error,
+// one link deep, so that Is and As stop at the maximum depth
+// rather than following cycles through Causes.
+func (err *Error) walkCauses(fn func(*Error) bool) {
+	max := maxDepth()
+	for _, cause := range err.Causes {
+		if !cause.walk(fn, 1, max) {
+			return
+		}
+	}
+}
+
+// Errors returns every error directly wrapped by this one:
+// the inner error followed by any additional Causes.
+// This mirrors the "Unwrap() []error" form of Go 1.20,
+// which cannot coexist with Unwrap() on the same type.
+func (err *Error) Errors() []error {
+	var errs []error
+	if inner := err.Unwrap(); inner != nil {
+		errs = append(errs, inner)
+	}
+	for _, cause := range err.Causes {
+		errs = append(errs, cause)
+	}
+	return errs
+}
+
+// AsError finds the first *Error in the chain of "err".
+// The chain may freely mix standard errors and ergo errors.
+func AsError(err error) (*Error, bool) {
+	var ergo *Error
+	if errors.As(err, &ergo) {
+		return ergo, true
+	}
+	return nil, false
+}
+
+// Code returns a Target matching any error with the given domain and code.
+//
+//	if errors.Is(err, ergo.Code("ergo", EMyError1)) { ... }
+func Code(domain string, code ErrCode) Target {
+	return Target{Domain: domain, Code: code}
+}
+
+// Error implements error.Error().
+func (t Target) Error() string {
+	return "[" + t.Domain + ":" + CodeString(t.Domain, t.Code) + "]"
+}
+
 // DomainFunc allows users to define custom domains.
 // This is a low-level API.
 func DomainFunc(name string, fn FormatFunc) {
-	_, ok := domains[name]
-	if ok {
+	if _, loaded := domains.LoadOrStore(name, fn); loaded {
 		log.Panicf("Domain conflict: %v", name)
 	}
-	domains[name] = fn
 }
 
 // Domain allows users to define custom domains.
 // A domain represents a set of error codes and their associated
 // message formats. The format string is processed by text/template.
-func Domain(name string, domain DomainMap) {
-	tmpls := make(map[ErrCode]*template.Template)
-	for code, text := range domain {
-		name := fmt.Sprintf("[%v:%d]", name, code)
-		tmpl := template.Must(template.New(name).Parse(text))
-		tmpls[code] = tmpl
+// The plural and ordinal functions select a form by the plural rules
+// of the locale, English for Domain:
+//
+//	"{{.count}} {{plural .count \"file\" \"files\"}} failed"
+//	"The {{.n}}{{ordinal .n \"st\" \"nd\" \"rd\" \"th\"}} attempt failed"
+//
+// Further functions may be added with TemplateFuncs and WithFuncs.
+// Translations may be added with DomainLocale. The maps of several
+// subsystems may be combined with Merge, and codes restricted to a
+// range with WithCodeRange.
+func Domain(name string, domain DomainMap, opts ...DomainOption) {
+	var options domainOptions
+	for _, opt := range opts {
+		opt(&options)
 	}
-	DomainFunc(name, func(err *Error) string {
-		tmpl, ok := tmpls[err.Code]
-		if !ok {
-			return "Unknown error"
+	domain, err := mergeDomains(name, domain, options.merge)
+	if err != nil {
+		panic(err)
+	}
+	if err := checkRange(name, domain, options.codes); err != nil {
+		panic(err)
+	}
+	if identicalDomainsAllowed() {
+		if def, ok := defined.Load(name); ok && sameDomain(def.(*definition).catalog, domain) {
+			return
 		}
-		var buf bytes.Buffer
-		terr := tmpl.Execute(&buf, err.Info)
-		if terr != nil {
-			panic(terr)
+	}
+	funcs := funcsFor(name, "en")
+	for key, fn := range options.funcs {
+		funcs[key] = fn
+	}
+	tmpls := compile(name, funcs, domain)
+	if err := checkKeys(name, tmpls, options.keys, true); err != nil {
+		panic(err)
+	}
+	def := define(tmpls, domain)
+	DomainFunc(name, func(err *Error) string {
+		if def, ok := defined.Load(name); ok {
+			if msg, ok := def.(*definition).tmpls.format(err); ok {
+				return msg
+			}
 		}
-		return buf.String()
+		return "Unknown error"
 	})
+	defined.Store(name, def)
+	if len(options.funcs) != 0 {
+		domainFuncs.Store(name, options.funcs)
+	}
+	if len(options.keys) != 0 {
+		domainKeys.Store(name, options.keys)
+	}
+	if options.codes != nil {
+		domainRanges.Store(name, options.codes)
+	}
+	NameCodes(name, options.names)
+}
+
+// define creates the definition of a domain, copying its message formats.
+func define(tmpls templates, domain DomainMap) *definition {
+	catalog := make(DomainMap, len(domain))
+	for code, text := range domain {
+		catalog[code] = text
+	}
+	return &definition{tmpls: tmpls, catalog: catalog}
 }
 
-func stackTrace(skip int) string {
-	buf := bytes.Buffer{}
-	stack := [50]uintptr{}
-	n := runtime.Callers(skip+1, stack[:])
-	for _, pc := range stack[:n] {
-		fn := runtime.FuncForPC(pc)
-		file, line := fn.FileLine(pc)
-		fmt.Fprintf(&buf, "%v:%v\n", file, line)
-		fmt.Fprintf(&buf, "\t%v\n", fn.Name())
+// templates holds the parsed message formats of a domain.
+type templates map[ErrCode]*template.Template
+
+// compile parses the message formats of a domain,
+// panicking if one cannot be parsed.
+func compile(name string, funcs template.FuncMap, domain DomainMap) templates {
+	tmpls, err := parseTemplates(name, funcs, domain)
+	if err != nil {
+		panic(err)
+	}
+	return tmpls
+}
+
+// parseTemplates parses the message formats of a domain
+// with template functions, see funcsFor.
+func parseTemplates(name string, funcs template.FuncMap, domain DomainMap) (templates, error) {
+	tmpls := make(templates)
+	for code, text := range domain {
+		name := fmt.Sprintf("[%v:%d]", name, code)
+		tmpl, err := template.New(name).Funcs(funcs).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		tmpls[code] = tmpl
+	}
+	return tmpls, nil
+}
+
+// format formats the message of an error,
+// reporting false if its code has no format.
+func (tmpls templates) format(err *Error) (string, bool) {
+	tmpl, ok := tmpls[err.Code]
+	if !ok {
+		return "", false
+	}
+	return execute(tmpl, err), true
+}
+
+// execute formats a message with the Info of an error.
+// Since formatting an error must not fail, a format which cannot be
+// executed yields the template error along with the raw Info.
+func execute(tmpl *template.Template, err *Error) string {
+	var buf bytes.Buffer
+	info := RedactInfo(err.Info)
+	terr := tmpl.Execute(&buf, info)
+	if terr != nil {
+		return fmt.Sprintf("Message failed: %v %v", terr, info)
 	}
 	return buf.String()
 }
 
 // Message returns the friendly error message without context.
 // This is appropriate for displaying to end users.
+// The message is translated to the default locale, if any,
+// see SetDefaultLocale.
+// An error restored from text carries its preformatted message
+// in Info["_msg"], which takes precedence.
 func (err *Error) Message() string {
-	domain, ok := domains[err.Domain]
-	if ok {
-		return domain(err)
+	return err.MessageIn("")
+}
+
+// MessageIn returns the friendly error message translated to "locale",
+// a BCP 47 language tag such as "de-DE". Without a translation for the
+// locale or its parents ("de"), the default locale is tried, and then
+// the message formats given to Domain. Overrides take precedence over
+// every locale, see Override.
+//
+// Domains may be nested with dotted names such as "billing.invoices".
+// If a nested domain has neither a message format nor a translation
+// for a code, the message of its parent domain "billing" is used.
+//
+// Info values are redacted, see SetRedactionPolicy.
+func (err *Error) MessageIn(locale string) string {
+	return redactText(err.messageIn(locale))
+}
+
+func (err *Error) messageIn(locale string) string {
+	if msg, ok := err.Info["_msg"].(string); ok {
+		return msg
+	}
+	known := false
+	for name := err.Domain; name != ""; name = parentDomain(name) {
+		if msg, ok := err.overridden(name); ok {
+			return msg
+		}
+		if msg, ok := err.localize(name, locale); ok {
+			return msg
+		}
+		if msg, ok := err.localize(name, DefaultLocale()); ok {
+			return msg
+		}
+		if def, ok := defined.Load(name); ok {
+			if msg, ok := def.(*definition).tmpls.format(err); ok {
+				return msg
+			}
+			known = true
+		} else if domain, ok := domains.Load(name); ok {
+			return domain.(FormatFunc)(err)
+		}
+	}
+	if known {
+		return "Unknown error"
+	}
+	return missingDomain()(err)
+}
+
+// parentDomain removes the last component of a dotted domain name.
+func parentDomain(name string) string {
+	if sep := strings.LastIndexByte(name, '.'); sep >= 0 {
+		return name[:sep]
 	}
-	return fmt.Sprintf("Domain missing: [%v:%d] %v",
-		err.Domain, err.Code, err.Info)
+	return ""
 }
 
 // Error implements error.Error().
-// The entire chain along with context is returned.
+// The entire chain along with context is returned,
+// including every branch of the additional Causes.
 // Use Message() to display end user friendly messages.
+// If SetOneline is enabled, Oneline() is returned instead.
 func (err *Error) Error() string {
-	str := fmt.Sprintf("[%v:%d] %v\n%v",
-		err.Domain, err.Code, err.Message(), err.Context)
-	if err.Inner == nil {
-		return str
+	if onelineEnabled() {
+		return err.Oneline()
+	}
+	var b strings.Builder
+	b.Grow(err.sizeHint(0))
+	err.writeChain(&b, 0)
+	return b.String()
+}
+
+// writeChain writes every link of the chain ending at this error,
+// innermost first, each preceded by its additional causes.
+// The outermost link is "depth" links deep, see appendLinks.
+// Links beyond the maximum depth are replaced by "...".
+func (err *Error) writeChain(b *strings.Builder, depth int) {
+	var links [8]*Error
+	chain, truncated := err.appendLinks(links[:0], depth)
+	if truncated {
+		b.WriteString("...")
+		if len(chain) != 0 {
+			b.WriteByte('\n')
+		}
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		link := chain[i]
+		for _, cause := range link.Causes {
+			cause.writeChain(b, depth+i+1)
+			b.WriteByte('\n')
+		}
+		link.writeEntry(b)
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+	}
+}
+
+// writeEntry writes the header and context of this error alone.
+func (err *Error) writeEntry(b *strings.Builder) {
+	b.WriteByte('[')
+	b.WriteString(err.Domain)
+	b.WriteByte(':')
+	b.WriteString(CodeString(err.Domain, err.Code))
+	b.WriteString("] ")
+	b.WriteString(err.Message())
+	b.WriteByte('\n')
+	err.writeContext(b)
+}
+
+// writeContext writes the developer context of this error,
+// formatting the stack trace if there is one.
+func (err *Error) writeContext(b *strings.Builder) {
+	if err.Stack != nil {
+		err.Stack.writeTo(b)
+		return
+	}
+	b.WriteString(err.Context)
+	if err.Context != "" && !strings.HasSuffix(err.Context, "\n") {
+		b.WriteByte('\n')
+	}
+}
+
+// sizeHint estimates the length of Error(),
+// for a chain whose outermost link is "depth" links deep.
+func (err *Error) sizeHint(depth int) int {
+	n := 0
+	var links [8]*Error
+	chain, _ := err.appendLinks(links[:0], depth)
+	for i, link := range chain {
+		n += 64 + len(link.Domain) + len(link.Context)
+		if link.Stack != nil {
+			for _, frame := range link.Stack.Frames() {
+				n += len(frame.File) + len(frame.Function) + 16
+			}
+		}
+		for _, cause := range link.Causes {
+			n += cause.sizeHint(depth+i+1) + 1
+		}
 	}
-	return err.Inner.Error() + "\n" + str
+	return n
 }
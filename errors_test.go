@@ -24,9 +24,13 @@ CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
 package ergo
 
 import (
+	"errors"
+	"fmt"
 	gc "github.com/motain/gocheck"
 	"io"
+	"os"
 	"strings"
+	"syscall"
 	"testing"
 )
 
@@ -46,7 +50,7 @@ const (
 )
 
 var (
-	errors = DomainMap{
+	messages = DomainMap{
 		EMyError0:    "My error 0",
 		EMyError1:    "My error 1",
 		EMyErrorArgs: "The {{.name}} failed",
@@ -58,7 +62,7 @@ func NewError(code ErrCode, args ...interface{}) *Error {
 }
 
 func (t *TestSuite) SetUpSuite(c *gc.C) {
-	Domain("ergo", errors)
+	Domain("ergo", messages)
 }
 
 func (t *TestSuite) TestNew(c *gc.C) {
@@ -66,9 +70,9 @@ func (t *TestSuite) TestNew(c *gc.C) {
 	c.Check(err, gc.NotNil)
 	c.Check(err.Domain, gc.Equals, "ergo")
 	c.Check(err.Code, gc.Equals, EMyError0)
-	first := strings.SplitN(err.Context, "\n", 3)
+	first := strings.SplitN(err.Stack.String(), "\n", 3)
 	c.Check(first[1], gc.Matches, "*TestNew$")
-	c.Check(err.Message(), gc.Equals, errors[EMyError0])
+	c.Check(err.Message(), gc.Equals, messages[EMyError0])
 	lines := strings.Split(err.Error(), "\n")
 	c.Check(lines[0], gc.Equals, "[ergo:0] My error 0")
 }
@@ -78,9 +82,9 @@ func (t *TestSuite) TestCustom(c *gc.C) {
 	c.Check(err, gc.NotNil)
 	c.Check(err.Domain, gc.Equals, "ergo")
 	c.Check(err.Code, gc.Equals, EMyError1)
-	first := strings.SplitN(err.Context, "\n", 3)
+	first := strings.SplitN(err.Stack.String(), "\n", 3)
 	c.Check(first[1], gc.Matches, "*TestCustom$")
-	c.Check(err.Message(), gc.Equals, errors[EMyError1])
+	c.Check(err.Message(), gc.Equals, messages[EMyError1])
 	lines := strings.Split(err.Error(), "\n")
 	c.Check(lines[0], gc.Equals, "[ergo:1] My error 1")
 
@@ -88,7 +92,7 @@ func (t *TestSuite) TestCustom(c *gc.C) {
 	c.Check(err, gc.NotNil)
 	c.Check(err.Domain, gc.Equals, "ergo")
 	c.Check(err.Code, gc.Equals, EMyErrorArgs)
-	first = strings.SplitN(err.Context, "\n", 3)
+	first = strings.SplitN(err.Stack.String(), "\n", 3)
 	c.Check(first[1], gc.Matches, "*TestCustom$")
 	c.Check(err.Message(), gc.Equals, "The x failed")
 	lines = strings.Split(err.Error(), "\n")
@@ -100,7 +104,7 @@ func (t *TestSuite) TestWrap(c *gc.C) {
 	c.Check(err, gc.NotNil)
 	c.Check(err.Domain, gc.Equals, "go")
 	c.Check(err.Code, gc.Equals, ErrCode(0))
-	first := strings.SplitN(err.Context, "\n", 3)
+	first := strings.SplitN(err.Stack.String(), "\n", 3)
 	c.Check(first[1], gc.Matches, "*TestWrap$")
 	c.Check(err.Info["_err"], gc.Equals, "EOF")
 	c.Check(err.Message(), gc.Equals, "Error: EOF")
@@ -110,7 +114,7 @@ func (t *TestSuite) TestWrap(c *gc.C) {
 	err = Wrap("Random error")
 	c.Check(err.Domain, gc.Equals, "go")
 	c.Check(err.Code, gc.Equals, ErrCode(0))
-	first = strings.SplitN(err.Context, "\n", 3)
+	first = strings.SplitN(err.Stack.String(), "\n", 3)
 	c.Check(first[1], gc.Matches, "*TestWrap$")
 	c.Check(err.Info["_err"], gc.Equals, "Random error")
 	c.Check(err.Message(), gc.Equals, "Error: Random error")
@@ -121,9 +125,9 @@ func (t *TestSuite) TestWrap(c *gc.C) {
 	c.Check(err, gc.NotNil)
 	c.Check(err.Domain, gc.Equals, "ergo")
 	c.Check(err.Code, gc.Equals, EMyError1)
-	first = strings.SplitN(err.Context, "\n", 3)
+	first = strings.SplitN(err.Stack.String(), "\n", 3)
 	c.Check(first[1], gc.Matches, "*TestWrap$")
-	c.Check(err.Message(), gc.Equals, errors[EMyError1])
+	c.Check(err.Message(), gc.Equals, messages[EMyError1])
 	lines = strings.Split(err.Error(), "\n")
 	c.Check(lines[0], gc.Equals, "[ergo:1] My error 1")
 
@@ -136,7 +140,7 @@ func (t *TestSuite) TestNoDomain(c *gc.C) {
 	c.Check(err, gc.NotNil)
 	c.Check(err.Domain, gc.Equals, "x")
 	c.Check(err.Code, gc.Equals, ErrCode(1))
-	first := strings.SplitN(err.Context, "\n", 3)
+	first := strings.SplitN(err.Stack.String(), "\n", 3)
 	c.Check(first[1], gc.Matches, "*TestNoDomain$")
 	const msg = "Domain missing: [x:1] map[arg:x]"
 	c.Check(err.Message(), gc.Equals, msg)
@@ -144,6 +148,14 @@ func (t *TestSuite) TestNoDomain(c *gc.C) {
 	c.Check(lines[0], gc.Equals, "[x:1] "+msg)
 }
 
+func (t *TestSuite) TestMessageFailed(c *gc.C) {
+	Domain("failed", DomainMap{0: "The {{.name.first}} failed"})
+	err := New(0, "failed", 0, "name", 3)
+	c.Check(err.Message(), gc.Matches,
+		`Message failed: template: \[failed:0\]:1:\d+: executing .* `+
+			`can't evaluate field first in type .* map\[name:3\]`)
+}
+
 func (t *TestSuite) TestChain(c *gc.C) {
 	inner := NewError(EMyError0)
 	middle := Chain(inner, NewError(EMyError0))
@@ -161,3 +173,229 @@ func (t *TestSuite) TestChain(c *gc.C) {
 	c.Check(lines1[0], gc.Equals, "[ergo:0] My error 0")
 	c.Check(lines2[0], gc.Equals, "[ergo:1] My error 1")
 }
+
+func (t *TestSuite) TestUnwrap(c *gc.C) {
+	inner := NewError(EMyError0)
+	outer := Chain(inner, NewError(EMyError1))
+	c.Check(errors.Unwrap(outer), gc.Equals, inner)
+	c.Check(errors.Unwrap(inner), gc.IsNil)
+	c.Check(errors.Is(outer, inner), gc.Equals, true)
+	c.Check(errors.Is(inner, outer), gc.Equals, false)
+
+	var target *Error
+	c.Check(errors.As(outer, &target), gc.Equals, true)
+	c.Check(target, gc.Equals, outer)
+}
+
+func (t *TestSuite) TestIs(c *gc.C) {
+	inner := NewError(EMyError0)
+	outer := Chain(inner, NewError(EMyError1))
+	c.Check(errors.Is(outer, Code("ergo", EMyError0)), gc.Equals, true)
+	c.Check(errors.Is(outer, Code("ergo", EMyError1)), gc.Equals, true)
+	c.Check(errors.Is(outer, Code("ergo", EMyErrorArgs)), gc.Equals, false)
+	c.Check(errors.Is(outer, Code("x", EMyError0)), gc.Equals, false)
+	c.Check(errors.Is(inner, Code("ergo", EMyError1)), gc.Equals, false)
+	c.Check(errors.Is(outer, NewError(EMyError0)), gc.Equals, true)
+	c.Check(errors.Is(inner, io.EOF), gc.Equals, false)
+	c.Check(Code("ergo", EMyError1).Error(), gc.Equals, "[ergo:1]")
+}
+
+func (t *TestSuite) TestAs(c *gc.C) {
+	inner := NewError(EMyErrorArgs, "name", "x")
+	wrapped := fmt.Errorf("context: %w", inner)
+
+	err, ok := AsError(wrapped)
+	c.Check(ok, gc.Equals, true)
+	c.Check(err, gc.Equals, inner)
+
+	err, ok = AsError(io.EOF)
+	c.Check(ok, gc.Equals, false)
+	c.Check(err, gc.IsNil)
+
+	var target Target
+	c.Check(errors.As(wrapped, &target), gc.Equals, true)
+	c.Check(target, gc.Equals, Code("ergo", EMyErrorArgs))
+}
+
+func (t *TestSuite) TestWrapf(c *gc.C) {
+	perr := &os.PathError{Op: "open", Path: "app.yaml", Err: os.ErrNotExist}
+	err := Wrapf(perr, "reading config %q", "app.yaml")
+	c.Check(err.Domain, gc.Equals, "go")
+	c.Check(err.Info["_err"], gc.Equals, perr.Error())
+	c.Check(err.Info["_note"], gc.Equals, `reading config "app.yaml"`)
+	c.Check(err.Message(), gc.Equals,
+		`Error: reading config "app.yaml": open app.yaml: file does not exist`)
+	first := strings.SplitN(err.Stack.String(), "\n", 3)
+	c.Check(first[1], gc.Matches, "*TestWrapf$")
+	c.Check(errors.Is(err, os.ErrNotExist), gc.Equals, true)
+
+	inner := NewError(EMyError1)
+	err = Wrapf(inner, "step %d", 2)
+	c.Check(err.Domain, gc.Equals, "go")
+	c.Check(err.Inner, gc.Equals, inner)
+	c.Check(err.Message(), gc.Equals, "Error: step 2: My error 1")
+	first = strings.SplitN(err.Stack.String(), "\n", 3)
+	c.Check(first[1], gc.Matches, "*TestWrapf$")
+
+	c.Check(Wrapf(nil, "unused"), gc.IsNil)
+}
+
+func (t *TestSuite) TestWrapAs(c *gc.C) {
+	err := WrapAs(io.EOF, "ergo", EMyErrorArgs, "name", "read")
+	c.Check(err.Domain, gc.Equals, "ergo")
+	c.Check(err.Code, gc.Equals, EMyErrorArgs)
+	c.Check(err.Info["name"], gc.Equals, "read")
+	first := strings.SplitN(err.Stack.String(), "\n", 3)
+	c.Check(first[1], gc.Matches, "*TestWrapAs$")
+	c.Assert(err.Inner, gc.NotNil)
+	c.Check(err.Inner.Domain, gc.Equals, "go")
+	c.Check(err.Inner.Message(), gc.Equals, "Error: EOF")
+	c.Check(err.Inner.Stack, gc.IsNil)
+	c.Check(err.Inner.Context, gc.Equals, "")
+	c.Check(errors.Is(err, io.EOF), gc.Equals, true)
+
+	inner := NewError(EMyError1)
+	err = WrapAs(inner, "ergo", EMyErrorArgs, "name", "read")
+	c.Check(err.Inner, gc.Equals, inner)
+
+	c.Check(WrapAs(nil, "ergo", EMyError1), gc.IsNil)
+}
+
+func deferWrap(fail error) (err error) {
+	defer DeferWrap(&err, "ergo", EMyErrorArgs, "name", "load")
+	return fail
+}
+
+func (t *TestSuite) TestDeferWrap(c *gc.C) {
+	c.Check(deferWrap(nil), gc.IsNil)
+
+	err, ok := AsError(deferWrap(io.EOF))
+	c.Assert(ok, gc.Equals, true)
+	c.Check(err.Code, gc.Equals, EMyErrorArgs)
+	c.Check(err.Message(), gc.Equals, "The load failed")
+	first := strings.SplitN(err.Stack.String(), "\n", 3)
+	c.Check(first[1], gc.Matches, "*deferWrap$")
+	c.Assert(err.Inner, gc.NotNil)
+	c.Check(err.Inner.Stack, gc.IsNil)
+	c.Check(errors.Is(err, io.EOF), gc.Equals, true)
+}
+
+func (t *TestSuite) TestRootCause(c *gc.C) {
+	perr := &os.PathError{Op: "open", Path: "x", Err: syscall.ENOENT}
+	inner := Wrap(fmt.Errorf("config: %w", perr))
+	outer := NewError(EMyError1)
+	Chain(inner, outer)
+	cause, root := RootCause(outer)
+	c.Check(cause, gc.Equals, inner)
+	c.Check(root, gc.Equals, syscall.ENOENT)
+
+	inner = NewError(EMyError0)
+	Chain(inner, outer)
+	cause, root = RootCause(outer)
+	c.Check(cause, gc.Equals, inner)
+	c.Check(root, gc.Equals, inner)
+
+	cause, root = RootCause(perr)
+	c.Check(cause, gc.Equals, perr)
+	c.Check(root, gc.Equals, syscall.ENOENT)
+
+	cause, root = RootCause(nil)
+	c.Check(cause, gc.IsNil)
+	c.Check(root, gc.IsNil)
+}
+
+func (t *TestSuite) TestWrapPreservesError(c *gc.C) {
+	err := Wrap(io.EOF)
+	c.Check(errors.Is(err, io.EOF), gc.Equals, true)
+	c.Check(errors.Unwrap(err), gc.Equals, io.EOF)
+
+	outer := Chain(io.ErrUnexpectedEOF, NewError(EMyError1))
+	c.Check(errors.Is(outer, io.ErrUnexpectedEOF), gc.Equals, true)
+	c.Check(errors.Is(outer, io.EOF), gc.Equals, false)
+
+	perr := &os.PathError{Op: "open", Path: "x", Err: os.ErrNotExist}
+	var target *os.PathError
+	c.Check(errors.As(Wrap(perr), &target), gc.Equals, true)
+	c.Check(target, gc.Equals, perr)
+	c.Check(errors.Is(Wrap(perr), os.ErrNotExist), gc.Equals, true)
+}
+
+func (t *TestSuite) TestJoin(c *gc.C) {
+	c.Check(Join(), gc.IsNil)
+	c.Check(Join(nil, nil), gc.IsNil)
+	var none *Error
+	c.Check(Join(none, nil), gc.IsNil)
+	c.Check(Join(none, io.EOF).Causes, gc.HasLen, 1)
+
+	first := NewError(EMyErrorArgs, "name", "write")
+	err := Join(first, nil, io.ErrClosedPipe)
+	c.Check(err, gc.NotNil)
+	c.Check(err.Domain, gc.Equals, "go")
+	c.Check(err.Causes, gc.HasLen, 2)
+	c.Check(err.Causes[0], gc.Equals, first)
+	c.Check(err.Message(), gc.Equals,
+		"Error: The write failed; io: read/write on closed pipe")
+
+	c.Check(errors.Is(err, Code("ergo", EMyErrorArgs)), gc.Equals, true)
+	c.Check(errors.Is(err, io.ErrClosedPipe), gc.Equals, true)
+	c.Check(errors.Is(err, io.EOF), gc.Equals, false)
+	c.Check(err.Errors(), gc.HasLen, 2)
+
+	perr := &os.PathError{Op: "close", Path: "x", Err: os.ErrClosed}
+	var target *os.PathError
+	c.Check(errors.As(Join(io.EOF, perr), &target), gc.Equals, true)
+	c.Check(target, gc.Equals, perr)
+
+	chains := strings.Split(err.Error(), "\n\n")
+	c.Check(strings.SplitN(chains[0], "\n", 2)[0], gc.Equals, "[ergo:2] The write failed")
+	c.Check(strings.SplitN(chains[1], "\n", 2)[0], gc.Equals,
+		"[go:0] Error: io: read/write on closed pipe")
+	c.Check(strings.SplitN(chains[2], "\n", 2)[0], gc.Equals, "[go:0] "+err.Message())
+}
+
+func chainOf(n int) *Error {
+	err := NewError(EMyError0)
+	for i := 1; i < n; i++ {
+		err = Chain(err, NewError(EMyErrorArgs, "name", "x")).(*Error)
+	}
+	return err
+}
+
+func (t *TestSuite) BenchmarkError(c *gc.C) {
+	err := NewError(EMyErrorArgs, "name", "x")
+	for i := 0; i < c.N; i++ {
+		_ = err.Error()
+	}
+}
+
+func (t *TestSuite) BenchmarkErrorChain(c *gc.C) {
+	err := chainOf(10)
+	for i := 0; i < c.N; i++ {
+		_ = err.Error()
+	}
+}
+
+func (t *TestSuite) TestNestedDomains(c *gc.C) {
+	Domain("billing", DomainMap{0: "Billing failed", 1: "Card declined"})
+	Domain("billing.invoices", DomainMap{0: "Invoice {{.id}} failed"})
+	DomainLocale("billing", "de", DomainMap{1: "Karte abgelehnt"})
+	defer RemoveDomain("billing")
+	defer RemoveDomain("billing.invoices")
+
+	c.Check(New(0, "billing.invoices", 0, "id", 7).Message(), gc.Equals, "Invoice 7 failed")
+	c.Check(New(0, "billing.invoices", 1).Message(), gc.Equals, "Card declined")
+	c.Check(New(0, "billing.invoices", 1).MessageIn("de"), gc.Equals, "Karte abgelehnt")
+	c.Check(New(0, "billing.refunds.partial", 0).Message(), gc.Equals, "Billing failed")
+	c.Check(New(0, "billing.invoices", 2).Message(), gc.Equals, "Unknown error")
+	c.Check(New(0, "shipping.invoices", 0).Message(), gc.Equals, "Domain missing: [shipping.invoices:0] map[]")
+
+	msg, ok := New(0, "billing.invoices", 1).Translation("de")
+	c.Check(ok, gc.Equals, true)
+	c.Check(msg, gc.Equals, "Karte abgelehnt")
+	_, ok = New(0, "billing.invoices", 0).Translation("de")
+	c.Check(ok, gc.Equals, false)
+
+	c.Assert(Override("billing", 1, "Overridden"), gc.IsNil)
+	defer ClearOverride("billing", 1)
+	c.Check(New(0, "billing.invoices", 1).Message(), gc.Equals, "Overridden")
+}
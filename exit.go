@@ -0,0 +1,104 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultExitCode is the exit code of errors without a code of their own.
+const DefaultExitCode = 1
+
+var (
+	exitCodes   sync.Map
+	verboseExit int32
+
+	// Replaced by tests.
+	exit             = os.Exit
+	stderr io.Writer = os.Stderr
+)
+
+// ExitCode declares the process exit code of errors with a domain and code.
+// An exit code of 0 removes the declaration.
+func ExitCode(domain string, code ErrCode, status int) {
+	key := Target{Domain: domain, Code: code}
+	if status == 0 {
+		exitCodes.Delete(key)
+	} else {
+		exitCodes.Store(key, status)
+	}
+}
+
+// ExitCodeFor returns the process exit code of "err".
+// The chain is walked from the outermost error inwards,
+// and the first declared exit code is returned.
+// If there is none, DefaultExitCode is returned; a nil error is 0.
+func ExitCodeFor(err error) int {
+	if err == nil {
+		return 0
+	}
+	code := DefaultExitCode
+	walkLinks(err, func(link *Error) bool {
+		declared, ok := exitCodes.Load(Target{Domain: link.Domain, Code: link.Code})
+		if ok {
+			code = declared.(int)
+		}
+		return !ok
+	})
+	return code
+}
+
+// SetVerboseExit controls whether Exit prints the full Error()
+// rather than Message().
+func SetVerboseExit(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&verboseExit, v)
+}
+
+// Exit terminates the process with the exit code of "err",
+// see ExitCodeFor, after printing it to stderr.
+// The friendly Message() of the outermost ergo error is printed,
+// or the full Error() if SetVerboseExit is enabled.
+// A nil error exits with 0 and prints nothing.
+//
+//	func main() {
+//		ergo.Exit(run())
+//	}
+func Exit(err error) {
+	if err != nil {
+		msg := err.Error()
+		if ergo, ok := AsError(err); ok && atomic.LoadInt32(&verboseExit) == 0 {
+			msg = ergo.Message()
+		}
+		fmt.Fprintln(stderr, msg)
+	}
+	exit(ExitCodeFor(err))
+}
@@ -0,0 +1,78 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"bytes"
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"os"
+)
+
+func (t *TestSuite) TestExitCodeFor(c *gc.C) {
+	ExitCode("ergo", EMyError0, 3)
+	defer ExitCode("ergo", EMyError0, 0)
+
+	c.Check(ExitCodeFor(nil), gc.Equals, 0)
+	c.Check(ExitCodeFor(New(0, "ergo", EMyError0)), gc.Equals, 3)
+	c.Check(ExitCodeFor(New(0, "ergo", EMyError1)), gc.Equals, DefaultExitCode)
+	c.Check(ExitCodeFor(fmt.Errorf("plain")), gc.Equals, DefaultExitCode)
+
+	chain := Chain(New(0, "ergo", EMyError0), New(0, "ergo", EMyError1))
+	c.Check(ExitCodeFor(fmt.Errorf("main: %w", chain)), gc.Equals, 3)
+}
+
+func (t *TestSuite) TestExit(c *gc.C) {
+	var buf bytes.Buffer
+	var code int
+	exit = func(n int) { code = n }
+	stderr = &buf
+	defer func() {
+		exit = os.Exit
+		stderr = os.Stderr
+	}()
+	ExitCode("ergo", EMyErrorArgs, 4)
+	defer ExitCode("ergo", EMyErrorArgs, 0)
+
+	err := New(0, "ergo", EMyErrorArgs, "name", "build")
+	Exit(err)
+	c.Check(code, gc.Equals, 4)
+	c.Check(buf.String(), gc.Equals, "The build failed\n")
+
+	buf.Reset()
+	SetVerboseExit(true)
+	Exit(err)
+	SetVerboseExit(false)
+	c.Check(buf.String(), gc.Equals, err.Error()+"\n")
+
+	buf.Reset()
+	Exit(fmt.Errorf("plain"))
+	c.Check(code, gc.Equals, DefaultExitCode)
+	c.Check(buf.String(), gc.Equals, "plain\n")
+
+	buf.Reset()
+	Exit(nil)
+	c.Check(code, gc.Equals, 0)
+	c.Check(buf.String(), gc.Equals, "")
+}
@@ -0,0 +1,110 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package expvarergo publishes counters of ergo errors with expvar,
+// for services which do not run Prometheus.
+package expvarergo
+
+import (
+	"encoding/json"
+	"expvar"
+	"github.com/flaub/ergo"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Stats is an expvar.Var holding the number of errors of each domain
+// and code, along with the time of their last occurrence:
+//
+//	{"mydomain": {"3": {"count": 2, "last": "2013-06-01T12:00:00Z"}}}
+//
+// Errors are only counted once Observe is installed as a hook,
+// see Publish.
+type Stats struct {
+	mu      sync.Mutex
+	entries map[ergo.Target]*entry
+}
+
+type entry struct {
+	Count uint64    `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// NewStats creates stats with no errors counted.
+func NewStats() *Stats {
+	return &Stats{entries: make(map[ergo.Target]*entry)}
+}
+
+// Publish creates stats, publishes them under "name"
+// and installs them as a hook, so that every error created
+// by ergo.New or ergo.Wrap is counted.
+// Like expvar.Publish, it panics if "name" is already in use.
+func Publish(name string) *Stats {
+	s := NewStats()
+	expvar.Publish(name, s)
+	ergo.AddHook(s.Observe)
+	return s
+}
+
+// Observe counts an error.
+func (s *Stats) Observe(err *ergo.Error) {
+	now := time.Now()
+	key := ergo.Code(err.Domain, err.Code)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		e = new(entry)
+		s.entries[key] = e
+	}
+	e.Count++
+	e.Last = now
+}
+
+// Count returns the number of errors counted with a domain and code.
+func (s *Stats) Count(domain string, code ergo.ErrCode) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[ergo.Code(domain, code)]; ok {
+		return e.Count
+	}
+	return 0
+}
+
+// String implements expvar.Var.
+func (s *Stats) String() string {
+	s.mu.Lock()
+	domains := make(map[string]map[string]entry)
+	for key, e := range s.entries {
+		codes, ok := domains[key.Domain]
+		if !ok {
+			codes = make(map[string]entry)
+			domains[key.Domain] = codes
+		}
+		codes[strconv.Itoa(int(key.Code))] = *e
+	}
+	s.mu.Unlock()
+	data, _ := json.Marshal(domains)
+	return string(data)
+}
@@ -0,0 +1,80 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package expvarergo
+
+import (
+	"encoding/json"
+	"expvar"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"io"
+	"testing"
+	"time"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+	EBroken
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("expvarergo", ergo.DomainMap{
+		ENotFound: "Not found",
+		EBroken:   "Broken",
+	})
+}
+
+func (t *TestSuite) TestPublish(c *gc.C) {
+	before := time.Now()
+	stats := Publish("ergo_errors")
+	c.Check(expvar.Get("ergo_errors"), gc.Equals, stats)
+
+	ergo.New(0, "expvarergo", ENotFound)
+	ergo.New(0, "expvarergo", ENotFound)
+	ergo.New(0, "expvarergo", EBroken)
+	ergo.Wrap(io.EOF)
+	c.Check(stats.Count("expvarergo", ENotFound), gc.Equals, uint64(2))
+	c.Check(stats.Count("expvarergo", 7), gc.Equals, uint64(0))
+
+	var published map[string]map[string]struct {
+		Count uint64
+		Last  time.Time
+	}
+	c.Assert(json.Unmarshal([]byte(expvar.Get("ergo_errors").String()), &published), gc.IsNil)
+	c.Check(published, gc.HasLen, 2)
+	c.Check(published["expvarergo"], gc.HasLen, 2)
+	c.Check(published["expvarergo"]["0"].Count, gc.Equals, uint64(2))
+	c.Check(published["expvarergo"]["1"].Count, gc.Equals, uint64(1))
+	c.Check(published["go"]["0"].Count, gc.Equals, uint64(1))
+	c.Check(published["go"]["0"].Last.Before(before), gc.Equals, false)
+}
@@ -0,0 +1,136 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HashFrames is the number of application frames covered by Hash.
+const HashFrames = 3
+
+// Fingerprint derives a stable grouping key for error trackers,
+// such as the fingerprint of a Sentry event.
+// The key is made of the domain and code of the outermost ergo error
+// in the chain of "err", followed by the function of its top application
+// frame. Line numbers and closure numbering are left out, so that
+// unrelated edits to a file do not split groups. Frames of the standard
+// library are skipped.
+// If the error has no stack, the nearest inner error with one is used.
+// Standard errors are keyed by their type.
+func Fingerprint(err error) []string {
+	if err == nil {
+		return nil
+	}
+	ergo, ok := AsError(err)
+	if !ok {
+		return []string{fmt.Sprintf("%T", err)}
+	}
+	key := []string{ergo.Domain, strconv.Itoa(int(ergo.Code))}
+	walkLinks(ergo, func(link *Error) bool {
+		function := topFunction(link)
+		if function != "" {
+			key = append(key, function)
+		}
+		return function == ""
+	})
+	return key
+}
+
+// topFunction returns the first application function
+// in the stack or context of an error.
+func topFunction(err *Error) string {
+	if functions := topFunctions(err, 1); len(functions) != 0 {
+		return functions[0]
+	}
+	return ""
+}
+
+// topFunctions returns up to "n" application functions
+// in the stack or context of an error, innermost first,
+// normalized by stableFunction.
+func topFunctions(err *Error, n int) []string {
+	var functions []string
+	if err.Stack != nil {
+		for _, frame := range err.Stack.Frames() {
+			if len(functions) == n {
+				break
+			}
+			if !frame.isStd() {
+				functions = append(functions, stableFunction(frame.Function))
+			}
+		}
+		return functions
+	}
+	// A context recorded by StackCaller is "file:line function".
+	if sep := strings.LastIndexByte(err.Context, ' '); sep >= 0 {
+		functions = append(functions, stableFunction(strings.TrimSpace(err.Context[sep+1:])))
+	}
+	return functions
+}
+
+// closureSuffix matches the numbering of closures,
+// which shifts when closures are added to a function.
+var closureSuffix = regexp.MustCompile(`\.func\d+(\.\d+)*`)
+
+// stableFunction strips the numbering of closures from a function name.
+func stableFunction(function string) string {
+	return closureSuffix.ReplaceAllString(function, ".func")
+}
+
+// Hash returns a stable 64-bit fingerprint of an error, suitable as a
+// map key for deduplication, sampling or grouping; format it with %016x
+// for a hex form. It covers the domain and code of the error and the
+// functions of its top HashFrames application frames, taken from the
+// nearest error of the chain with a stack, see Fingerprint.
+// Line numbers and closure numbering are left out.
+func (err *Error) Hash() uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%v\x00%d", err.Domain, err.Code)
+	walkLinks(err, func(link *Error) bool {
+		functions := topFunctions(link, HashFrames)
+		for _, function := range functions {
+			fmt.Fprintf(h, "\x00%v", function)
+		}
+		return len(functions) == 0
+	})
+	return h.Sum64()
+}
+
+// isStd reports whether the frame belongs to the standard library,
+// whose import paths have no dot in their first element.
+func (f Frame) isStd() bool {
+	pkg := f.pkg()
+	if pkg == "" || pkg == "main" {
+		return false
+	}
+	if slash := strings.IndexByte(pkg, '/'); slash >= 0 {
+		pkg = pkg[:slash]
+	}
+	return !strings.Contains(pkg, ".")
+}
@@ -0,0 +1,90 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"io"
+)
+
+func (t *TestSuite) TestFingerprint(c *gc.C) {
+	c.Check(Fingerprint(nil), gc.IsNil)
+	c.Check(Fingerprint(io.EOF), gc.DeepEquals, []string{"*errors.errorString"})
+
+	fn := "github.com/flaub/ergo.(*TestSuite).TestFingerprint"
+	err := New(0, "ergo", EMyError1)
+	c.Check(Fingerprint(err), gc.DeepEquals, []string{"ergo", "1", fn})
+
+	// Different lines of the same function share a fingerprint.
+	other := New(0, "ergo", EMyError1, "name", "x")
+	c.Check(Fingerprint(other), gc.DeepEquals, Fingerprint(err))
+	c.Check(Fingerprint(fmt.Errorf("ctx: %w", err)), gc.DeepEquals, Fingerprint(err))
+
+	outer := New(0, "ergo", EMyError0, WithStackDepth(0))
+	chain := Chain(err, outer)
+	c.Check(Fingerprint(chain), gc.DeepEquals, []string{"ergo", "0", fn})
+
+	stack := NewStack([]Frame{
+		{Function: "runtime.gopanic"},
+		{Function: "net/http.HandlerFunc.ServeHTTP"},
+		{Function: "main.handler"},
+	})
+	c.Check(Fingerprint(&Error{Domain: "x", Code: 2, Stack: stack}), gc.DeepEquals,
+		[]string{"x", "2", "main.handler"})
+	c.Check(Fingerprint(&Error{Domain: "x", Code: 2, Context: "/src/main.go:10 main.run"}),
+		gc.DeepEquals, []string{"x", "2", "main.run"})
+	c.Check(Fingerprint(&Error{Domain: "x", Code: 2}), gc.DeepEquals, []string{"x", "2"})
+
+	// Closures keep their fingerprint when other closures are added.
+	closure := func(function string) []string {
+		return Fingerprint(&Error{Domain: "x", Code: 2, Stack: NewStack([]Frame{{Function: function}})})
+	}
+	c.Check(closure("main.run.func1"), gc.DeepEquals, []string{"x", "2", "main.run.func"})
+	c.Check(closure("main.run.func2.1"), gc.DeepEquals, closure("main.run.func1"))
+	c.Check(Fingerprint(&Error{Domain: "x", Code: 2, Context: "/src/main.go:10 main.run.func3"}),
+		gc.DeepEquals, closure("main.run.func1"))
+}
+
+func (t *TestSuite) TestHash(c *gc.C) {
+	err := New(0, "ergo", EMyError1)
+	c.Check(New(0, "ergo", EMyError1, "name", "x").Hash(), gc.Equals, err.Hash())
+	c.Check(New(0, "ergo", EMyError0).Hash(), gc.Not(gc.Equals), err.Hash())
+	c.Check(Chain(err, New(0, "ergo", EMyError1, WithStackDepth(0))).(*Error).Hash(),
+		gc.Equals, err.Hash())
+
+	frames := func(functions ...string) *Error {
+		stack := make([]Frame, len(functions))
+		for i, function := range functions {
+			stack[i] = Frame{Function: function, Line: i}
+		}
+		return &Error{Domain: "x", Code: 2, Stack: NewStack(stack)}
+	}
+	hash := frames("runtime.gopanic", "main.run.func1", "main.run", "main.main").Hash()
+	c.Check(frames("main.run.func2", "main.run", "main.main", "runtime.main").Hash(),
+		gc.Equals, hash)
+	c.Check(frames("main.run.func1", "main.run", "main.other").Hash(), gc.Not(gc.Equals), hash)
+	c.Check(frames("main.run.func1", "main.run", "main.main", "main.init").Hash(), gc.Equals, hash)
+	c.Check(fmt.Sprintf("%016x", hash), gc.Matches, "[0-9a-f]{16}")
+}
@@ -0,0 +1,146 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format implements fmt.Formatter.
+//
+//	%s, %v  the friendly message, see Message()
+//	%q      the friendly message, quoted
+//	%+v     the entire chain along with context, see Error()
+//	        (regardless of SetOneline)
+//	%#v     a Go-syntax representation of the error
+func (err *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('+'):
+			var b strings.Builder
+			b.Grow(err.sizeHint(0))
+			err.writeChain(&b, 0)
+			io.WriteString(s, b.String())
+		case s.Flag('#'):
+			io.WriteString(s, err.GoString())
+		default:
+			io.WriteString(s, err.Message())
+		}
+	case 's':
+		io.WriteString(s, err.Message())
+	case 'q':
+		fmt.Fprintf(s, "%q", err.Message())
+	default:
+		fmt.Fprintf(s, "%%!%c(*ergo.Error=%s)", verb, err.Message())
+	}
+}
+
+// GoString implements fmt.GoStringer.
+// Only fields which are set are included, and Info is redacted,
+// see RedactInfo.
+func (err *Error) GoString() string {
+	var b strings.Builder
+	err.writeGoString(&b, 0)
+	return b.String()
+}
+
+// writeGoString writes this error, "depth" links deep, see appendLinks.
+// Links beyond the maximum depth are replaced by "...".
+func (err *Error) writeGoString(b *strings.Builder, depth int) {
+	if max := maxDepth(); max >= 0 && depth >= max {
+		b.WriteString("...")
+		return
+	}
+	fmt.Fprintf(b, "&ergo.Error{Domain:%q, Code:%d", err.Domain, err.Code)
+	if len(err.Info) != 0 {
+		fmt.Fprintf(b, ", Info:%#v", RedactInfo(err.Info))
+	}
+	if err.Context != "" {
+		fmt.Fprintf(b, ", Context:%q", err.Context)
+	}
+	if err.Stack != nil {
+		fmt.Fprintf(b, ", Stack:ergo.NewStack(%#v)", err.Stack.Frames())
+	}
+	if err.Inner != nil {
+		b.WriteString(", Inner:")
+		err.Inner.writeGoString(b, depth+1)
+	}
+	if len(err.Causes) != 0 {
+		b.WriteString(", Causes:[]*ergo.Error{")
+		for i, cause := range err.Causes {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			cause.writeGoString(b, depth+1)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte('}')
+}
+
+// Oneline returns the entire chain on a single line, outermost first:
+//
+//	[domain:code] message <- [domain:code] message
+//
+// Additional causes follow their error in parentheses.
+// Context is omitted. This is suited to log pipelines which treat
+// newlines as record boundaries.
+func (err *Error) Oneline() string {
+	var b strings.Builder
+	err.writeOneline(&b, 0)
+	return b.String()
+}
+
+// writeOneline writes the chain ending at this error,
+// whose outermost link is "depth" links deep, see appendLinks.
+func (err *Error) writeOneline(b *strings.Builder, depth int) {
+	var links [8]*Error
+	chain, truncated := err.appendLinks(links[:0], depth)
+	for i, link := range chain {
+		if i > 0 {
+			b.WriteString(" <- ")
+		}
+		fmt.Fprintf(b, "[%v:%v] ", link.Domain, CodeString(link.Domain, link.Code))
+		b.WriteString(strings.Replace(link.Message(), "\n", " ", -1))
+		if len(link.Causes) == 0 {
+			continue
+		}
+		b.WriteString(" <- (")
+		for j, cause := range link.Causes {
+			if j > 0 {
+				b.WriteString(" | ")
+			}
+			cause.writeOneline(b, depth+i+1)
+		}
+		b.WriteByte(')')
+	}
+	if truncated && len(chain) != 0 {
+		b.WriteString(" <- ...")
+	} else if truncated {
+		b.WriteString("...")
+	}
+}
@@ -0,0 +1,78 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"io"
+)
+
+func (t *TestSuite) TestFormat(c *gc.C) {
+	inner := NewError(EMyError0)
+	err := Chain(inner, NewError(EMyErrorArgs, "name", "x"))
+
+	c.Check(fmt.Sprintf("%v", err), gc.Equals, "The x failed")
+	c.Check(fmt.Sprintf("%s", err), gc.Equals, "The x failed")
+	c.Check(fmt.Sprintf("%q", err), gc.Equals, `"The x failed"`)
+	c.Check(fmt.Sprintf("%+v", err), gc.Equals, err.Error())
+	c.Check(fmt.Sprintf("%d", err), gc.Equals, "%!d(*ergo.Error=The x failed)")
+	c.Check(fmt.Errorf("wrapped: %w", err).Error(), gc.Equals, "wrapped: The x failed")
+}
+
+func (t *TestSuite) TestGoString(c *gc.C) {
+	inner := &Error{Domain: "ergo", Code: EMyError0, Context: "main.cpp:42"}
+	err := &Error{
+		Domain: "ergo",
+		Code:   EMyErrorArgs,
+		Info:   ErrInfo{"name": "x"},
+		Stack:  NewStack([]Frame{{Function: "main.main", File: "main.go", Line: 7}}),
+		Inner:  inner,
+		Causes: []*Error{{Domain: "go"}},
+	}
+	c.Check(fmt.Sprintf("%#v", err), gc.Equals,
+		`&ergo.Error{Domain:"ergo", Code:2, Info:ergo.ErrInfo{"name":"x"}, `+
+			`Stack:ergo.NewStack([]ergo.Frame{ergo.Frame{PC:0x0, Function:"main.main", File:"main.go", Line:7}}), `+
+			`Inner:&ergo.Error{Domain:"ergo", Code:0, Context:"main.cpp:42"}, `+
+			`Causes:[]*ergo.Error{&ergo.Error{Domain:"go", Code:0}}}`)
+}
+
+func (t *TestSuite) TestOneline(c *gc.C) {
+	inner := NewError(EMyError0)
+	err := Chain(inner, NewError(EMyErrorArgs, "name", "x")).(*Error)
+	c.Check(err.Oneline(), gc.Equals, "[ergo:2] The x failed <- [ergo:0] My error 0")
+	c.Check(inner.Oneline(), gc.Equals, "[ergo:0] My error 0")
+
+	joined := Chain(Join(NewError(EMyError1), io.EOF), NewError(EMyError0)).(*Error)
+	c.Check(joined.Oneline(), gc.Equals, "[ergo:0] My error 0 <- "+
+		"[go:0] Error: My error 1; EOF <- ([ergo:1] My error 1 | [go:0] Error: EOF)")
+
+	multiline := Wrap("first\nsecond")
+	c.Check(multiline.Oneline(), gc.Equals, "[go:0] Error: first second")
+
+	SetOneline(true)
+	defer SetOneline(false)
+	c.Check(err.Error(), gc.Equals, err.Oneline())
+	c.Check(fmt.Sprintf("%+v", err), gc.Matches, "(?s)\\[ergo:0\\] My error 0\n.*")
+}
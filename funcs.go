@@ -0,0 +1,91 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"sync"
+	"sync/atomic"
+	"text/template"
+)
+
+var (
+	globalFuncsMu sync.Mutex
+	globalFuncs   atomic.Value
+
+	// The functions given to Domain with WithFuncs, by domain.
+	domainFuncs sync.Map
+)
+
+// DomainOption customizes the definition of a domain, see Domain.
+type DomainOption func(*domainOptions)
+
+type domainOptions struct {
+	funcs template.FuncMap
+	keys  map[ErrCode][]string
+	merge []DomainMap
+	names map[ErrCode]string
+	codes *codeRange
+}
+
+// TemplateFuncs adds functions to the message formats of every domain,
+// for instance to format sizes, durations or quantities instead of
+// rendering raw Info values. Since formats are parsed when domains are
+// defined, functions must be added before the domains using them.
+func TemplateFuncs(funcs template.FuncMap) {
+	globalFuncsMu.Lock()
+	defer globalFuncsMu.Unlock()
+	old, _ := globalFuncs.Load().(template.FuncMap)
+	merged := make(template.FuncMap, len(old)+len(funcs))
+	for name, fn := range old {
+		merged[name] = fn
+	}
+	for name, fn := range funcs {
+		merged[name] = fn
+	}
+	globalFuncs.Store(merged)
+}
+
+// WithFuncs adds functions to the message formats of a domain,
+// including its translations, taking precedence over TemplateFuncs.
+func WithFuncs(funcs template.FuncMap) DomainOption {
+	return func(opts *domainOptions) {
+		opts.funcs = funcs
+	}
+}
+
+// funcsFor returns the functions of the message formats
+// of a domain in a locale.
+func funcsFor(name, locale string) template.FuncMap {
+	funcs := pluralFuncs(locale)
+	global, _ := globalFuncs.Load().(template.FuncMap)
+	for key, fn := range global {
+		funcs[key] = fn
+	}
+	if own, ok := domainFuncs.Load(name); ok {
+		for key, fn := range own.(template.FuncMap) {
+			funcs[key] = fn
+		}
+	}
+	return funcs
+}
@@ -0,0 +1,60 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"strings"
+	"text/template"
+	"time"
+)
+
+func (t *TestSuite) TestTemplateFuncs(c *gc.C) {
+	TemplateFuncs(template.FuncMap{
+		"kib":   func(n int) string { return fmt.Sprintf("%d KiB", n/1024) },
+		"upper": strings.ToUpper,
+	})
+	Domain("funcs", DomainMap{
+		0: "{{upper .name}} exceeds {{kib .size}}",
+		1: "Timed out after {{seconds .timeout}}",
+	}, WithFuncs(template.FuncMap{
+		"seconds": func(d time.Duration) string { return fmt.Sprintf("%gs", d.Seconds()) },
+		"upper":   strings.ToLower,
+	}))
+	DomainLocale("funcs", "de", DomainMap{
+		1: "Zeitüberschreitung nach {{seconds .timeout}}",
+	})
+
+	c.Check(New(0, "funcs", 0, "name", "File", "size", 4096).Message(), gc.Equals,
+		"file exceeds 4 KiB")
+	err := New(0, "funcs", 1, "timeout", 1500*time.Millisecond)
+	c.Check(err.Message(), gc.Equals, "Timed out after 1.5s")
+	c.Check(err.MessageIn("de"), gc.Equals, "Zeitüberschreitung nach 1.5s")
+
+	Domain("funcs2", DomainMap{0: "{{upper .name}}"})
+	c.Check(New(0, "funcs2", 0, "name", "x").Message(), gc.Equals, "X")
+	c.Check(func() { Domain("funcs3", DomainMap{0: "{{seconds .x}}"}) },
+		gc.PanicMatches, `.*function "seconds" not defined`)
+}
@@ -0,0 +1,127 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package gettext builds translated ergo domains from gettext catalogs,
+// so that .po and .mo files maintained with the usual tooling
+// (Poedit, Weblate, msgfmt) can be registered with ergo.DomainLocale.
+//
+// The message of an ergo code is the entry whose msgctxt is
+// "domain:code"; entries with other contexts are ignored.
+// The msgid is the source format and the msgstr its translation,
+// both processed by text/template:
+//
+//	msgctxt "db:3"
+//	msgid "The {{.name}} was not found"
+//	msgstr "{{.name}} wurde nicht gefunden"
+package gettext
+
+import (
+	"fmt"
+	"github.com/flaub/ergo"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Catalog holds the translations of a gettext catalog.
+type Catalog struct {
+	// The locale of the translations, from the "Language" header.
+	Locale string
+
+	// The translated message formats, by domain.
+	Domains map[string]ergo.DomainMap
+}
+
+// Register defines the translations of every domain of the catalog,
+// see ergo.DomainLocale.
+func (cat *Catalog) Register() error {
+	if cat.Locale == "" {
+		return fmt.Errorf("gettext: catalog has no language")
+	}
+	for name, domain := range cat.Domains {
+		ergo.DomainLocale(name, cat.Locale, domain)
+	}
+	return nil
+}
+
+// LoadFile parses a .po or .mo file, depending on its extension,
+// and registers its translations.
+func LoadFile(path string) (*Catalog, error) {
+	parse := ParsePO
+	switch ext := filepath.Ext(path); ext {
+	case ".po":
+	case ".mo":
+		parse = ParseMO
+	default:
+		return nil, fmt.Errorf("gettext: unknown catalog extension %q", ext)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cat, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("gettext: %v: %v", path, err)
+	}
+	return cat, cat.Register()
+}
+
+func newCatalog() *Catalog {
+	return &Catalog{Domains: make(map[string]ergo.DomainMap)}
+}
+
+// add adds an entry to the catalog, ignoring entries
+// which do not translate an ergo code.
+func (cat *Catalog) add(ctxt, id, str string) {
+	if id == "" && ctxt == "" {
+		cat.Locale = header(str, "Language")
+		return
+	}
+	sep := strings.LastIndexByte(ctxt, ':')
+	if sep <= 0 || str == "" {
+		return
+	}
+	code, err := strconv.Atoi(ctxt[sep+1:])
+	if err != nil {
+		return
+	}
+	name := ctxt[:sep]
+	domain, ok := cat.Domains[name]
+	if !ok {
+		domain = make(ergo.DomainMap)
+		cat.Domains[name] = domain
+	}
+	domain[ergo.ErrCode(code)] = str
+}
+
+// header returns a field of the header entry of a catalog.
+func header(str, field string) string {
+	for _, line := range strings.Split(str, "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(key) == field {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
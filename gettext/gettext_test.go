@@ -0,0 +1,84 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package gettext
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+	EBroken
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("gettext", ergo.DomainMap{
+		ENotFound: "The {{.name}} was not found",
+		EBroken:   "Broken",
+	})
+}
+
+func (t *TestSuite) TestRegister(c *gc.C) {
+	cat := &Catalog{Domains: map[string]ergo.DomainMap{"gettext": {EBroken: "Kaputt"}}}
+	c.Check(cat.Register(), gc.ErrorMatches, "gettext: catalog has no language")
+
+	cat.Locale = "de"
+	c.Assert(cat.Register(), gc.IsNil)
+	c.Check(ergo.New(0, "gettext", EBroken).MessageIn("de-DE"), gc.Equals, "Kaputt")
+}
+
+func (t *TestSuite) TestLoadFile(c *gc.C) {
+	dir := c.MkDir()
+	path := filepath.Join(dir, "fr.po")
+	c.Assert(os.WriteFile(path, []byte(`msgid ""
+msgstr "Language: fr\n"
+
+msgctxt "gettext:0"
+msgid "The {{.name}} was not found"
+msgstr "{{.name}} introuvable"
+`), 0644), gc.IsNil)
+	cat, err := LoadFile(path)
+	c.Assert(err, gc.IsNil)
+	c.Check(cat.Locale, gc.Equals, "fr")
+	c.Check(ergo.New(0, "gettext", ENotFound, "name", "x").MessageIn("fr"),
+		gc.Equals, "x introuvable")
+
+	_, err = LoadFile(filepath.Join(dir, "fr.txt"))
+	c.Check(err, gc.ErrorMatches, `gettext: unknown catalog extension ".txt"`)
+	_, err = LoadFile(filepath.Join(dir, "missing.po"))
+	c.Check(os.IsNotExist(err), gc.Equals, true)
+}
@@ -0,0 +1,90 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package gettext
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The magic number of .mo files, in their byte order.
+const moMagic = 0x950412de
+
+// ParseMO parses a .mo file, as compiled by msgfmt.
+// Entries with plural forms are ignored.
+func ParseMO(r io.Reader) (*Catalog, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 20 {
+		return nil, fmt.Errorf("not a .mo file")
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if order.Uint32(data) != moMagic {
+		order = binary.BigEndian
+		if order.Uint32(data) != moMagic {
+			return nil, fmt.Errorf("not a .mo file")
+		}
+	}
+	count := order.Uint32(data[8:])
+	originals := order.Uint32(data[12:])
+	translations := order.Uint32(data[16:])
+	str := func(table, i uint32) (string, error) {
+		at := uint64(table) + 8*uint64(i)
+		if at+8 > uint64(len(data)) {
+			return "", fmt.Errorf("truncated string table")
+		}
+		length := uint64(order.Uint32(data[at:]))
+		offset := uint64(order.Uint32(data[at+4:]))
+		if offset+length > uint64(len(data)) {
+			return "", fmt.Errorf("truncated string")
+		}
+		return string(data[offset : offset+length]), nil
+	}
+	cat := newCatalog()
+	for i := uint32(0); i < count; i++ {
+		original, err := str(originals, i)
+		if err != nil {
+			return nil, err
+		}
+		translation, err := str(translations, i)
+		if err != nil {
+			return nil, err
+		}
+		// Plural forms are separated by NUL bytes.
+		if strings.IndexByte(original, 0) >= 0 {
+			continue
+		}
+		// A context precedes the msgid, separated by EOT.
+		var ctxt string
+		if sep := strings.IndexByte(original, 4); sep >= 0 {
+			ctxt, original = original[:sep], original[sep+1:]
+		}
+		cat.add(ctxt, original, translation)
+	}
+	return cat, nil
+}
@@ -0,0 +1,74 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package gettext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+)
+
+// compileMO encodes pairs of originals and translations as a .mo file.
+func compileMO(order binary.ByteOrder, pairs ...string) []byte {
+	n := uint32(len(pairs) / 2)
+	var strs bytes.Buffer
+	tables := make([]uint32, 0, 4*n)
+	offset := 28 + 16*n
+	for _, parity := range []int{0, 1} {
+		for i := parity; i < len(pairs); i += 2 {
+			tables = append(tables, uint32(len(pairs[i])), offset+uint32(strs.Len()))
+			strs.WriteString(pairs[i])
+			strs.WriteByte(0)
+		}
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, order, []uint32{moMagic, 0, n, 28, 28 + 8*n, 0, 0})
+	binary.Write(&buf, order, tables)
+	buf.Write(strs.Bytes())
+	return buf.Bytes()
+}
+
+func (t *TestSuite) TestParseMO(c *gc.C) {
+	pairs := []string{
+		"", "Language: pt-BR\nContent-Type: text/plain; charset=UTF-8\n",
+		"Hello", "Olá",
+		"db:0\x04Broken", "Quebrado",
+		"db:1\x04One file\x00{{.count}} files", "Um arquivo\x00{{.count}} arquivos",
+	}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		cat, err := ParseMO(bytes.NewReader(compileMO(order, pairs...)))
+		c.Assert(err, gc.IsNil)
+		c.Check(cat.Locale, gc.Equals, "pt-BR")
+		c.Check(cat.Domains, gc.DeepEquals, map[string]ergo.DomainMap{
+			"db": {0: "Quebrado"},
+		})
+	}
+
+	_, err := ParseMO(bytes.NewReader([]byte("msgid")))
+	c.Check(err, gc.ErrorMatches, "not a .mo file")
+	data := compileMO(binary.LittleEndian, pairs...)
+	_, err = ParseMO(bytes.NewReader(data[:60]))
+	c.Check(err, gc.ErrorMatches, "truncated string.*")
+}
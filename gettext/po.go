@@ -0,0 +1,109 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package gettext
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// poEntry is an entry of a .po file being parsed.
+type poEntry struct {
+	ctxt, id, str string
+	fuzzy, plural bool
+	translated    bool
+}
+
+// ParsePO parses a .po file.
+// Fuzzy entries and entries with plural forms are ignored.
+func ParsePO(r io.Reader) (*Catalog, error) {
+	cat := newCatalog()
+	var entry poEntry
+	var field *string
+	flush := func() {
+		if entry.translated && !entry.plural && (!entry.fuzzy || entry.id == "") {
+			cat.add(entry.ctxt, entry.id, entry.str)
+		}
+		entry = poEntry{}
+		field = nil
+	}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if entry.translated {
+				flush()
+			}
+			if strings.HasPrefix(line, "#,") && strings.Contains(line, "fuzzy") {
+				entry.fuzzy = true
+			}
+			continue
+		}
+		keyword, rest := "", line
+		if !strings.HasPrefix(line, `"`) {
+			keyword, rest, _ = strings.Cut(line, " ")
+		}
+		value, err := strconv.Unquote(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		switch {
+		case keyword == "":
+			if field == nil {
+				return nil, fmt.Errorf("line %d: unexpected string", n)
+			}
+			*field += value
+			continue
+		case keyword == "msgctxt" || keyword == "msgid":
+			if entry.translated {
+				flush()
+			}
+			field = &entry.ctxt
+			if keyword == "msgid" {
+				field = &entry.id
+			}
+		case keyword == "msgid_plural":
+			entry.plural = true
+			field = &entry.str
+		case keyword == "msgstr" || strings.HasPrefix(keyword, "msgstr["):
+			entry.translated = true
+			field = &entry.str
+		default:
+			return nil, fmt.Errorf("line %d: unknown keyword %q", n, keyword)
+		}
+		*field = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return cat, nil
+}
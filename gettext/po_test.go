@@ -0,0 +1,89 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package gettext
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"strings"
+)
+
+const po = `# German translations.
+msgid ""
+msgstr ""
+"Project-Id-Version: app\n"
+"Language: de\n"
+
+#. Not an ergo code.
+msgid "Hello"
+msgstr "Hallo"
+
+#: db.go:12
+msgctxt "db:0"
+msgid "The {{.name}} was not found"
+msgstr "{{.name}} wurde "
+"nicht gefunden"
+msgctxt "db:1"
+msgid "Broken"
+msgstr "Kaputt"
+
+#, fuzzy
+msgctxt "db:2"
+msgid "Timed out"
+msgstr "Zeitüberschreitung"
+
+msgctxt "db:3"
+msgid "One file"
+msgid_plural "{{.count}} files"
+msgstr[0] "Eine Datei"
+msgstr[1] "{{.count}} Dateien"
+
+msgctxt "db:4"
+msgid "Untranslated"
+msgstr ""
+
+msgctxt "net:10"
+msgid "Say \"hi\""
+msgstr "Sag \"hallo\""
+`
+
+func (t *TestSuite) TestParsePO(c *gc.C) {
+	cat, err := ParsePO(strings.NewReader(po))
+	c.Assert(err, gc.IsNil)
+	c.Check(cat.Locale, gc.Equals, "de")
+	c.Check(cat.Domains, gc.DeepEquals, map[string]ergo.DomainMap{
+		"db": {
+			0: "{{.name}} wurde nicht gefunden",
+			1: "Kaputt",
+		},
+		"net": {10: `Sag "hallo"`},
+	})
+
+	_, err = ParsePO(strings.NewReader("msgid \"x\"\nmsgfoo \"y\"\n"))
+	c.Check(err, gc.ErrorMatches, `line 2: unknown keyword "msgfoo"`)
+	_, err = ParsePO(strings.NewReader("\"x\"\n"))
+	c.Check(err, gc.ErrorMatches, "line 1: unexpected string")
+	_, err = ParsePO(strings.NewReader("msgid x\n"))
+	c.Check(err, gc.ErrorMatches, "line 1: invalid syntax")
+}
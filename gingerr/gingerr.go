@@ -0,0 +1,79 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package gingerr integrates ergo errors with the Gin web framework.
+//
+// Handlers report errors with Error or Abort, which add them to the
+// error list of the context with their full context for logging.
+// Middleware renders the last ergo error as problem details,
+// see httperr.Problem, so clients only ever see the domain message.
+package gingerr
+
+import (
+	"github.com/flaub/ergo"
+	"github.com/flaub/ergo/httperr"
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware returns a handler which renders the last ergo error
+// in the error list once the remaining handlers have run.
+// The status is the one declared for the error, see ergo.StatusFor.
+// Nothing is rendered if a response has already been written,
+// or if the list holds no ergo errors.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if c.Writer.Written() {
+			return
+		}
+		if err := Last(c); err != nil {
+			problem := httperr.Problem(err)
+			c.Header("Content-Type", httperr.ContentType)
+			c.AbortWithStatusJSON(problem.Status, problem)
+		}
+	}
+}
+
+// Error adds an error to the error list of the context.
+// The wire form of the error is attached as metadata,
+// so that c.Errors.JSON() includes its full context.
+func Error(c *gin.Context, err *ergo.Error) *gin.Error {
+	return c.Error(err).SetType(gin.ErrorTypePrivate).SetMeta(err.ToWire())
+}
+
+// Abort adds an error to the error list and stops the remaining handlers.
+func Abort(c *gin.Context, err *ergo.Error) {
+	Error(c, err)
+	c.Abort()
+}
+
+// Last returns the most recent ergo error in the error list,
+// or nil if there is none.
+func Last(c *gin.Context) *ergo.Error {
+	for i := len(c.Errors) - 1; i >= 0; i-- {
+		if err, ok := ergo.AsError(c.Errors[i].Err); ok {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package gingerr
+
+import (
+	"errors"
+	"github.com/flaub/ergo"
+	"github.com/flaub/ergo/httperr"
+	"github.com/gin-gonic/gin"
+	gc "github.com/motain/gocheck"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+	EConflict
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	gin.SetMode(gin.TestMode)
+	ergo.Domain("gingerr", ergo.DomainMap{
+		ENotFound: "The {{.name}} was not found",
+		EConflict: "Conflict",
+	})
+	ergo.HTTPStatus("gingerr", ENotFound, http.StatusNotFound)
+}
+
+func serve(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+	return rec
+}
+
+func (t *TestSuite) TestMiddleware(c *gc.C) {
+	var errs []*gin.Error
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		ctx.Next()
+		errs = ctx.Errors
+	})
+	router.Use(Middleware())
+	router.GET("/missing", func(ctx *gin.Context) {
+		Abort(ctx, ergo.New(0, "gingerr", ENotFound, "name", "page", "_secret", "x"))
+	}, func(ctx *gin.Context) {
+		c.Error("not reached")
+	})
+	router.GET("/plain", func(ctx *gin.Context) {
+		ctx.Error(errors.New("plain"))
+		ctx.String(http.StatusTeapot, "written")
+	})
+	router.GET("/last", func(ctx *gin.Context) {
+		Error(ctx, ergo.New(0, "gingerr", ENotFound, "name", "page"))
+		ctx.Error(errors.New("plain"))
+		Error(ctx, ergo.New(0, "gingerr", EConflict))
+	})
+
+	rec := serve(router, "/missing")
+	c.Check(rec.Code, gc.Equals, http.StatusNotFound)
+	c.Check(rec.Header().Get("Content-Type"), gc.Equals, httperr.ContentType)
+	c.Check(rec.Body.String(), gc.Equals, `{"code":0,"detail":"The page was not found",`+
+		`"domain":"gingerr","info":{"name":"page"},"status":404,`+
+		`"title":"Not Found","type":"urn:ergo:gingerr:0"}`)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Check(errs[0].Type, gc.Equals, gin.ErrorTypePrivate)
+	c.Check(errs[0].Error(), gc.Matches, "(?s)\\[gingerr:0\\] The page was not found\n.*TestMiddleware.*")
+	wire, ok := errs[0].Meta.(*ergo.Wire)
+	c.Assert(ok, gc.Equals, true)
+	c.Check(wire.Info["_secret"], gc.Equals, "x")
+
+	rec = serve(router, "/plain")
+	c.Check(rec.Code, gc.Equals, http.StatusTeapot)
+	c.Check(rec.Body.String(), gc.Equals, "written")
+
+	rec = serve(router, "/last")
+	c.Check(rec.Code, gc.Equals, http.StatusInternalServerError)
+	c.Check(rec.Body.String(), gc.Matches, `.*"detail":"Conflict".*`)
+	c.Check(errs, gc.HasLen, 3)
+}
@@ -0,0 +1,81 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io"
+	"time"
+)
+
+func init() {
+	// Register the types commonly found in Info,
+	// so that they can be transmitted as interface values.
+	gob.Register(ErrInfo{})
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+	gob.Register(ErrCode(0))
+	gob.Register(time.Time{})
+	gob.Register(time.Duration(0))
+}
+
+// GobEncode implements gob.GobEncoder.
+// Errors are transmitted in the layout of Wire.
+// Info values of custom types must be registered with gob.Register.
+func (err *Error) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if gerr := gob.NewEncoder(&buf).Encode(err.ToWire()); gerr != nil {
+		return nil, gerr
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder, see Wire.ToError.
+func (err *Error) GobDecode(data []byte) error {
+	var wire Wire
+	if gerr := gob.NewDecoder(bytes.NewReader(data)).Decode(&wire); gerr != nil {
+		return gerr
+	}
+	decoded, derr := wire.ToError()
+	if derr != nil {
+		return derr
+	}
+	*err = *decoded
+	return nil
+}
+
+// EncodeGob writes an error to "w" using encoding/gob.
+func EncodeGob(w io.Writer, err *Error) error {
+	return gob.NewEncoder(w).Encode(err)
+}
+
+// DecodeGob reads an error written by EncodeGob.
+func DecodeGob(r io.Reader) (*Error, error) {
+	var err Error
+	if gerr := gob.NewDecoder(r).Decode(&err); gerr != nil {
+		return nil, gerr
+	}
+	return &err, nil
+}
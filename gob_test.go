@@ -0,0 +1,74 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"bytes"
+	"encoding/gob"
+	gc "github.com/motain/gocheck"
+	"io"
+	"time"
+)
+
+func (t *TestSuite) TestGobRoundTrip(c *gc.C) {
+	info := []interface{}{
+		"name", "x",
+		"count", 3,
+		"ratio", 0.5,
+		"ok", true,
+		"timeout", time.Second,
+		"list", []interface{}{"a", 1},
+		"nested", map[string]interface{}{"k": "v"},
+	}
+	err := Chain(Wrap(io.EOF), NewError(EMyErrorArgs, info...)).(*Error)
+	err.Causes = []*Error{NewError(EMyError1)}
+
+	var buf bytes.Buffer
+	c.Assert(EncodeGob(&buf, err), gc.IsNil)
+	decoded, gerr := DecodeGob(&buf)
+	c.Assert(gerr, gc.IsNil)
+	c.Check(decoded.Error(), gc.Equals, err.Error())
+	c.Check(decoded.Info, gc.DeepEquals, err.Info)
+	c.Check(decoded.Inner.Info, gc.DeepEquals, ErrInfo{"_err": "EOF"})
+	c.Check(decoded.Causes, gc.HasLen, 1)
+	c.Check(decoded.Causes[0].Message(), gc.Equals, "My error 1")
+
+	// Errors embedded in other values, as with net/rpc replies.
+	type reply struct {
+		Value int
+		Err   *Error
+	}
+	buf.Reset()
+	c.Assert(gob.NewEncoder(&buf).Encode(reply{Value: 1, Err: err}), gc.IsNil)
+	var r reply
+	c.Assert(gob.NewDecoder(&buf).Decode(&r), gc.IsNil)
+	c.Check(r.Err.Error(), gc.Equals, err.Error())
+}
+
+func (t *TestSuite) TestGobValidation(c *gc.C) {
+	var buf bytes.Buffer
+	c.Assert(EncodeGob(&buf, &Error{Domain: "ergo", Code: 99}), gc.IsNil)
+	_, err := DecodeGob(&buf)
+	c.Check(err, gc.ErrorMatches, "ergo: \\[ergo:99\\] is not defined")
+}
@@ -0,0 +1,91 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package gqlerr surfaces ergo errors to GraphQL clients
+// as structured errors.
+package gqlerr
+
+import (
+	"context"
+	"errors"
+	"github.com/flaub/ergo"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"strings"
+)
+
+// ToGQL converts an error into a GraphQL error.
+// The message is Message(), and the domain, code and Info are carried
+// in the extensions. Context and stack traces are omitted.
+func ToGQL(err *ergo.Error) *gqlerror.Error {
+	return &gqlerror.Error{
+		Err:        err,
+		Message:    err.Message(),
+		Extensions: Extensions(err),
+	}
+}
+
+// Extensions returns the GraphQL error extensions of an error:
+// "domain", "code" and, if there is any, "info".
+// Info keys starting with an underscore are reserved and omitted.
+func Extensions(err *ergo.Error) map[string]interface{} {
+	extensions := map[string]interface{}{
+		"domain": err.Domain,
+		"code":   err.Code,
+	}
+	info := make(map[string]interface{})
+	for key, value := range err.Info {
+		if !strings.HasPrefix(key, "_") {
+			info[key] = ergo.Redact(key, value)
+		}
+	}
+	if len(info) != 0 {
+		extensions["info"] = info
+	}
+	return extensions
+}
+
+// Presenter is a gqlgen error presenter (graphql.ErrorPresenterFunc):
+//
+//	srv.SetErrorPresenter(gqlerr.Presenter)
+//
+// Errors whose chain contains an ergo error are presented with its
+// message and extensions, keeping the path and locations already set
+// by gqlgen. Other errors are presented as they would be by default.
+func Presenter(ctx context.Context, err error) *gqlerror.Error {
+	var gqlErr *gqlerror.Error
+	if !errors.As(err, &gqlErr) {
+		gqlErr = gqlerror.Wrap(err)
+	}
+	ergoErr, ok := ergo.AsError(err)
+	if !ok {
+		return gqlErr
+	}
+	gqlErr.Message = ergoErr.Message()
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = make(map[string]interface{})
+	}
+	for key, value := range Extensions(ergoErr) {
+		gqlErr.Extensions[key] = value
+	}
+	return gqlErr
+}
@@ -0,0 +1,80 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package gqlerr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"io"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("gqlerr", ergo.DomainMap{
+		0: "The {{.name}} was not found",
+	})
+}
+
+func (t *TestSuite) TestToGQL(c *gc.C) {
+	err := ergo.New(0, "gqlerr", 0, "name", "user", "_msg_hint", 1)
+	data, jerr := json.Marshal(ToGQL(err))
+	c.Assert(jerr, gc.IsNil)
+	c.Check(string(data), gc.Equals, `{"message":"The user was not found",`+
+		`"extensions":{"code":0,"domain":"gqlerr","info":{"name":"user"}}}`)
+}
+
+func (t *TestSuite) TestPresenter(c *gc.C) {
+	ctx := context.Background()
+	err := ergo.New(0, "gqlerr", 0, "name", "user")
+	path := ast.Path{ast.PathName("user")}
+
+	// gqlgen wraps resolver errors with their path before presenting them.
+	gqlErr := Presenter(ctx, gqlerror.WrapPath(path, fmt.Errorf("resolving: %w", err)))
+	c.Check(gqlErr.Message, gc.Equals, "The user was not found")
+	c.Check(gqlErr.Path, gc.DeepEquals, path)
+	c.Check(gqlErr.Extensions["domain"], gc.Equals, "gqlerr")
+	c.Check(gqlErr.Extensions["code"], gc.Equals, ergo.ErrCode(0))
+
+	gqlErr = Presenter(ctx, err)
+	c.Check(gqlErr.Message, gc.Equals, "The user was not found")
+	c.Check(gqlErr.Err, gc.Equals, err)
+
+	gqlErr = Presenter(ctx, io.EOF)
+	c.Check(gqlErr.Message, gc.Equals, "EOF")
+	c.Check(gqlErr.Extensions, gc.IsNil)
+}
@@ -0,0 +1,125 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"io"
+	"net"
+)
+
+// failService fails every call with an ergo error.
+var failService = grpc.ServiceDesc{
+	ServiceName: "grpcerr.Fail",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Unary",
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error,
+			interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := new(emptypb.Empty)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, ergo.New(0, "grpcerr", ENotFound, "name", "user")
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcerr.Fail/Unary"}
+			return interceptor(ctx, in, info, handler)
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Stream",
+		ServerStreams: true,
+		Handler: func(srv interface{}, stream grpc.ServerStream) error {
+			if err := stream.SendMsg(new(emptypb.Empty)); err != nil {
+				return err
+			}
+			return ergo.New(0, "grpcerr", EBroken)
+		},
+	}},
+}
+
+// dial serves failService in process and returns a connection to it.
+func dial(c *gc.C) (*grpc.ClientConn, func()) {
+	listener := bufconn.Listen(1 << 16)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor),
+		grpc.StreamInterceptor(StreamServerInterceptor),
+	)
+	server.RegisterService(&failService, struct{}{})
+	go server.Serve(listener)
+
+	cc, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(StreamClientInterceptor),
+	)
+	c.Assert(err, gc.IsNil)
+	return cc, func() {
+		cc.Close()
+		server.Stop()
+	}
+}
+
+func (t *TestSuite) TestUnaryInterceptors(c *gc.C) {
+	cc, stop := dial(c)
+	defer stop()
+
+	err := cc.Invoke(context.Background(), "/grpcerr.Fail/Unary", new(emptypb.Empty), new(emptypb.Empty))
+	c.Assert(err, gc.NotNil)
+	ergoErr, ok := ergo.AsError(err)
+	c.Assert(ok, gc.Equals, true)
+	c.Check(ergoErr.Message(), gc.Equals, "The user was not found")
+	c.Check(errors.Is(err, ergo.Code("grpcerr", ENotFound)), gc.Equals, true)
+	c.Check(Code(ergoErr), gc.Equals, codes.NotFound)
+	c.Check(status.Code(err), gc.Equals, codes.NotFound)
+}
+
+func (t *TestSuite) TestStreamInterceptors(c *gc.C) {
+	cc, stop := dial(c)
+	defer stop()
+
+	desc := &failService.Streams[0]
+	stream, err := cc.NewStream(context.Background(), desc, "/grpcerr.Fail/Stream")
+	c.Assert(err, gc.IsNil)
+	c.Assert(stream.SendMsg(new(emptypb.Empty)), gc.IsNil)
+	c.Assert(stream.CloseSend(), gc.IsNil)
+	c.Assert(stream.RecvMsg(new(emptypb.Empty)), gc.IsNil)
+	err = stream.RecvMsg(new(emptypb.Empty))
+	c.Check(errors.Is(err, ergo.Code("grpcerr", EBroken)), gc.Equals, true)
+	c.Check(err, gc.Not(gc.Equals), io.EOF)
+	c.Check(status.Code(err), gc.Equals, codes.Unknown)
+}
@@ -0,0 +1,123 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package grpcerr carries ergo errors across gRPC.
+//
+// An error is converted into a status with a canonical gRPC code and the
+// full serialized error attached as an ergopb.Error detail, so that
+// clients can reconstruct the original domain, code and Info.
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"github.com/flaub/ergo"
+	"github.com/flaub/ergo/ergopb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+)
+
+// Domain is the domain of errors reconstructed from statuses
+// which carry no ergo error. Their code is the gRPC code.
+const Domain = "grpc"
+
+var (
+	codeMu sync.RWMutex
+	table  = make(map[ergo.Target]codes.Code)
+)
+
+// SetCode maps errors with a domain and code to a canonical gRPC code.
+func SetCode(domain string, code ergo.ErrCode, c codes.Code) {
+	codeMu.Lock()
+	defer codeMu.Unlock()
+	table[ergo.Code(domain, code)] = c
+}
+
+// Code returns the gRPC code of an error: the mapping of the outermost
+// link of the chain which has one. Chains containing context.Canceled or
+// context.DeadlineExceeded map to the matching codes. Otherwise the code
+// is codes.Unknown.
+func Code(err *ergo.Error) codes.Code {
+	codeMu.RLock()
+	for link := err; link != nil; link = link.Inner {
+		if c, ok := table[ergo.Code(link.Domain, link.Code)]; ok {
+			codeMu.RUnlock()
+			return c
+		}
+	}
+	codeMu.RUnlock()
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+	return codes.Unknown
+}
+
+// ToStatus converts an error into a gRPC status.
+// The message of the status is Message(),
+// and the serialized error is attached as a detail.
+func ToStatus(err *ergo.Error) *status.Status {
+	st := status.New(Code(err), err.Message())
+	if detailed, derr := st.WithDetails(ergopb.ToProto(err)); derr == nil {
+		return detailed
+	}
+	return st
+}
+
+// FromStatus reconstructs an error from a gRPC status.
+// If the status carries a valid serialized error, it is returned.
+// Otherwise the error is in the "grpc" domain, with the gRPC code as its
+// code and the message of the status as its message.
+// Either way the status error is kept as the original error, so that
+// status.Code and status.FromError still see the status.
+// A nil or OK status yields nil.
+func FromStatus(st *status.Status) *ergo.Error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	wrapped := ergo.WithWrapped(st.Err())
+	for _, detail := range st.Details() {
+		if pb, ok := detail.(*ergopb.Error); ok {
+			if err, perr := ergopb.FromProto(pb, wrapped); perr == nil {
+				return err
+			}
+		}
+	}
+	return ergo.New(1, Domain, ergo.ErrCode(st.Code()), "_msg", st.Message(), wrapped)
+}
+
+// FromError reconstructs an error returned by a gRPC call,
+// see FromStatus. Errors which are not statuses are wrapped.
+func FromError(err error) *ergo.Error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return ergo.Wrap(err)
+	}
+	return FromStatus(st)
+}
@@ -0,0 +1,94 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package grpcerr
+
+import (
+	"context"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+	EBroken
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("grpcerr", ergo.DomainMap{
+		ENotFound: "The {{.name}} was not found",
+		EBroken:   "Broken",
+	})
+	SetCode("grpcerr", ENotFound, codes.NotFound)
+}
+
+func (t *TestSuite) TestCode(c *gc.C) {
+	c.Check(Code(ergo.New(0, "grpcerr", ENotFound)), gc.Equals, codes.NotFound)
+	c.Check(Code(ergo.New(0, "grpcerr", EBroken)), gc.Equals, codes.Unknown)
+
+	chained := ergo.Chain(ergo.New(0, "grpcerr", ENotFound), ergo.New(0, "grpcerr", EBroken))
+	c.Check(Code(chained.(*ergo.Error)), gc.Equals, codes.NotFound)
+
+	c.Check(Code(ergo.Wrap(context.Canceled)), gc.Equals, codes.Canceled)
+	c.Check(Code(ergo.Wrap(context.DeadlineExceeded)), gc.Equals, codes.DeadlineExceeded)
+}
+
+func (t *TestSuite) TestRoundTrip(c *gc.C) {
+	err := ergo.Chain(io.EOF, ergo.New(0, "grpcerr", ENotFound, "name", "user")).(*ergo.Error)
+	st := ToStatus(err)
+	c.Check(st.Code(), gc.Equals, codes.NotFound)
+	c.Check(st.Message(), gc.Equals, "The user was not found")
+
+	// Statuses travel as their protobuf form.
+	restored := FromError(status.ErrorProto(st.Proto()))
+	c.Assert(restored, gc.NotNil)
+	c.Check(restored.Error(), gc.Equals, err.Error())
+	c.Check(restored.Info["name"], gc.Equals, "user")
+	c.Check(status.Code(restored), gc.Equals, codes.NotFound)
+}
+
+func (t *TestSuite) TestFromPlainStatus(c *gc.C) {
+	err := FromError(status.Error(codes.Unavailable, "try again"))
+	c.Check(err.Domain, gc.Equals, Domain)
+	c.Check(err.Code, gc.Equals, ergo.ErrCode(codes.Unavailable))
+	c.Check(err.Message(), gc.Equals, "try again")
+	c.Check(err.Inner, gc.IsNil)
+	c.Check(status.Code(err), gc.Equals, codes.Unavailable)
+
+	c.Check(FromError(nil), gc.IsNil)
+	c.Check(FromStatus(status.New(codes.OK, "")), gc.IsNil)
+	c.Check(FromError(io.EOF).Message(), gc.Equals, "Error: EOF")
+}
@@ -0,0 +1,84 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DomainHandle creates errors of a domain, so that packages need not
+// repeat the name of their domain and a skip count at every call:
+//
+//	var billing = ergo.NewDomain("billing", ergo.DomainMap{
+//		ENotFound: "The invoice {{.id}} was not found",
+//	})
+//
+//	return billing.New(ENotFound, "id", id)
+type DomainHandle struct {
+	name string
+}
+
+// NewDomain defines a domain, see Domain, and returns its handle.
+func NewDomain(name string, domain DomainMap, opts ...DomainOption) *DomainHandle {
+	Domain(name, domain, opts...)
+	return &DomainHandle{name: name}
+}
+
+// Name returns the name of the domain.
+func (d *DomainHandle) Name() string {
+	return d.name
+}
+
+// Code returns a Target matching errors with a code of the domain.
+func (d *DomainHandle) Code(code ErrCode) Target {
+	return Target{Domain: d.name, Code: code}
+}
+
+// New creates an error of the domain, see New.
+// The stack starts at the caller.
+func (d *DomainHandle) New(code ErrCode, args ...interface{}) *Error {
+	return New(1, d.name, code, args...)
+}
+
+// Wrap creates an error of the domain with "err" as its inner error,
+// see Chain. Only the outer error has a stack, as with WrapAs.
+// If "err" is nil, nil is returned.
+func (d *DomainHandle) Wrap(err error, code ErrCode, args ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+	return New(1, d.name, code, append(args[:len(args):len(args)], WithInner(unstacked(err)))...)
+}
+
+// Errorf creates an error of the domain whose message is formatted
+// with fmt.Errorf instead of the message format of its code.
+// An error wrapped with %w becomes the inner error.
+func (d *DomainHandle) Errorf(code ErrCode, format string, a ...interface{}) *Error {
+	msg := fmt.Errorf(format, a...)
+	if inner := errors.Unwrap(msg); inner != nil {
+		return New(1, d.name, code, "_msg", msg.Error(), WithInner(unstacked(inner)))
+	}
+	return New(1, d.name, code, "_msg", msg.Error())
+}
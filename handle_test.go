@@ -0,0 +1,63 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"errors"
+	gc "github.com/motain/gocheck"
+	"io"
+)
+
+func (t *TestSuite) TestDomainHandle(c *gc.C) {
+	d := NewDomain("handle", DomainMap{
+		0: "Failed",
+		1: "The {{.name}} was not found",
+	})
+	defer RemoveDomain("handle")
+	c.Check(d.Name(), gc.Equals, "handle")
+
+	err := d.New(1, "name", "x")
+	c.Check(err.Domain, gc.Equals, "handle")
+	c.Check(err.Message(), gc.Equals, "The x was not found")
+	c.Check(err.Stack.Frames()[0].Function, gc.Matches, ".*TestDomainHandle$")
+	c.Check(errors.Is(err, d.Code(1)), gc.Equals, true)
+
+	c.Check(d.Wrap(nil, 0), gc.IsNil)
+	err = d.Wrap(io.EOF, 0)
+	c.Check(err.Message(), gc.Equals, "Failed")
+	c.Check(err.Stack.Frames()[0].Function, gc.Matches, ".*TestDomainHandle$")
+	c.Check(err.Inner.Message(), gc.Equals, "Error: EOF")
+	c.Check(err.Inner.Stack, gc.IsNil)
+	c.Check(errors.Is(err, io.EOF), gc.Equals, true)
+	inner := d.New(0)
+	c.Check(d.Wrap(inner, 1).Inner, gc.Equals, inner)
+
+	err = d.Errorf(0, "reading %v: %w", "config", io.EOF)
+	c.Check(err.Message(), gc.Equals, "reading config: EOF")
+	c.Check(errors.Is(err, io.EOF), gc.Equals, true)
+	c.Check(err.Inner.Stack, gc.IsNil)
+	err = d.Errorf(1, "no %v", "luck")
+	c.Check(err.Message(), gc.Equals, "no luck")
+	c.Check(err.Inner, gc.IsNil)
+}
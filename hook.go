@@ -0,0 +1,70 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	hooksMu sync.Mutex
+	hooks   atomic.Value // []func(*Error)
+)
+
+// AddHook registers a function which is called with every error
+// created by New or Wrap, for example to log, count or mutate errors.
+//
+// Hooks run synchronously on the goroutine creating the error,
+// before it is returned, in the order they were added;
+// each hook sees the changes made by the previous ones.
+// A hook which panics is logged and skipped, without affecting
+// the caller or the remaining hooks.
+// Hooks must be safe for concurrent use, and since errors they create
+// run the hooks as well, they must not create errors unconditionally.
+func AddHook(fn func(*Error)) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	old, _ := hooks.Load().([]func(*Error))
+	fns := make([]func(*Error), len(old), len(old)+1)
+	copy(fns, old)
+	hooks.Store(append(fns, fn))
+}
+
+func runHooks(err *Error) {
+	fns, _ := hooks.Load().([]func(*Error))
+	for _, fn := range fns {
+		runHook(fn, err)
+	}
+}
+
+func runHook(fn func(*Error), err *Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("ergo: hook panicked on [%v:%d]: %v", err.Domain, err.Code, r)
+		}
+	}()
+	fn(err)
+}
@@ -0,0 +1,126 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"bytes"
+	gc "github.com/motain/gocheck"
+	"io"
+	"log"
+	"os"
+)
+
+func resetHooks() {
+	var none []func(*Error)
+	hooks.Store(none)
+}
+
+func (t *TestSuite) TestAddHook(c *gc.C) {
+	defer resetHooks()
+
+	var seen []Target
+	AddHook(func(err *Error) {
+		seen = append(seen, Target{err.Domain, err.Code})
+	})
+	New(0, "ergo", EMyError1)
+	Wrap(io.EOF)
+	c.Check(seen, gc.DeepEquals, []Target{{"ergo", EMyError1}, {"go", 0}})
+}
+
+// unwrapped returns, for every error created by "fn",
+// whether hooks could unwrap it.
+func unwrapped(fn func()) []bool {
+	defer resetHooks()
+	var seen []bool
+	AddHook(func(err *Error) {
+		seen = append(seen, err.Unwrap() != nil)
+	})
+	fn()
+	return seen
+}
+
+func (t *TestSuite) TestHookSeesWrapped(c *gc.C) {
+	c.Check(unwrapped(func() { Wrap(io.EOF) }), gc.DeepEquals, []bool{true})
+	c.Check(unwrapped(func() { Wrapf(&Error{Domain: "x"}, "note") }), gc.DeepEquals, []bool{true})
+	c.Check(unwrapped(func() { WrapAs(io.EOF, "x", 1) }), gc.DeepEquals, []bool{true, true})
+	c.Check(unwrapped(func() { deferWrap(&Error{Domain: "x"}) }), gc.DeepEquals, []bool{true})
+	c.Check(unwrapped(func() { (&DomainHandle{name: "x"}).Wrap(io.EOF, 1) }), gc.DeepEquals, []bool{true, true})
+	c.Check(unwrapped(func() { (&DomainHandle{name: "x"}).Errorf(1, "x: %w", io.EOF) }), gc.DeepEquals, []bool{true, true})
+	c.Check(unwrapped(func() { recovered(func() { Check(&Error{Domain: "x"}, "x", 1) }) }),
+		gc.DeepEquals, []bool{true})
+}
+
+func (t *TestSuite) TestHookSeesCauses(c *gc.C) {
+	defer resetHooks()
+	var causes []*Error
+	AddHook(func(err *Error) {
+		causes = err.Causes
+	})
+	first, second := &Error{Domain: "x"}, &Error{Domain: "y"}
+	Join(first, second)
+	c.Check(causes, gc.DeepEquals, []*Error{first, second})
+}
+
+func (t *TestSuite) TestHookSeesPanic(c *gc.C) {
+	defer resetHooks()
+	var inner error
+	var value interface{}
+	AddHook(func(err *Error) {
+		if err.Code == EMyErrorArgs {
+			inner = err.Unwrap()
+			value, _ = err.PanicValue()
+		}
+	})
+	recoverPanic("boom")
+	c.Assert(inner, gc.NotNil)
+	c.Check(inner.Error(), gc.Matches, "(?s).*boom.*")
+	c.Check(value, gc.Equals, "boom")
+}
+
+func (t *TestSuite) TestHookPipeline(c *gc.C) {
+	defer resetHooks()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	var order []string
+	AddHook(func(err *Error) {
+		order = append(order, "first")
+		err.Info["tag"] = "x"
+	})
+	AddHook(func(err *Error) {
+		order = append(order, "panic")
+		panic("broken hook")
+	})
+	AddHook(func(err *Error) {
+		order = append(order, "last:"+err.Info["tag"].(string))
+		err.Severity = SeverityWarn
+	})
+
+	err := New(0, "ergo", EMyError0)
+	c.Check(order, gc.DeepEquals, []string{"first", "panic", "last:x"})
+	c.Check(err.Info, gc.DeepEquals, ErrInfo{"tag": "x"})
+	c.Check(err.Severity, gc.Equals, SeverityWarn)
+	c.Check(buf.String(), gc.Matches, ".*ergo: hook panicked on \\[ergo:0\\]: broken hook\n")
+}
@@ -0,0 +1,78 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"sync"
+)
+
+const (
+	// DefaultHTTPStatus is the HTTP status of errors
+	// without a status of their own (500 Internal Server Error).
+	DefaultHTTPStatus = 500
+
+	statusOK = 200
+)
+
+var httpStatuses sync.Map
+
+// HTTPStatus declares the HTTP status of errors with a domain and code.
+// A status of 0 removes the declaration.
+func HTTPStatus(domain string, code ErrCode, status int) {
+	key := Target{Domain: domain, Code: code}
+	if status == 0 {
+		httpStatuses.Delete(key)
+	} else {
+		httpStatuses.Store(key, status)
+	}
+}
+
+// DeclaredHTTPStatus returns the HTTP status declared for a domain and
+// code with HTTPStatus, reporting false if there is none.
+func DeclaredHTTPStatus(domain string, code ErrCode) (int, bool) {
+	status, ok := httpStatuses.Load(Target{Domain: domain, Code: code})
+	if !ok {
+		return 0, false
+	}
+	return status.(int), true
+}
+
+// StatusFor returns the HTTP status of "err".
+// The chain is walked from the outermost error inwards,
+// and the first declared status is returned.
+// If there is none, DefaultHTTPStatus is returned; a nil error is 200 OK.
+func StatusFor(err error) int {
+	if err == nil {
+		return statusOK
+	}
+	status := DefaultHTTPStatus
+	walkLinks(err, func(link *Error) bool {
+		declared, ok := httpStatuses.Load(Target{Domain: link.Domain, Code: link.Code})
+		if ok {
+			status = declared.(int)
+		}
+		return !ok
+	})
+	return status
+}
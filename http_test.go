@@ -0,0 +1,57 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	gc "github.com/motain/gocheck"
+)
+
+func (t *TestSuite) TestStatusFor(c *gc.C) {
+	HTTPStatus("ergo", EMyError0, 404)
+	HTTPStatus("ergo", EMyError1, 409)
+	defer HTTPStatus("ergo", EMyError0, 0)
+	defer HTTPStatus("ergo", EMyError1, 0)
+
+	c.Check(StatusFor(nil), gc.Equals, 200)
+	c.Check(StatusFor(New(0, "ergo", EMyError0)), gc.Equals, 404)
+	c.Check(StatusFor(New(0, "ergo", EMyErrorArgs)), gc.Equals, DefaultHTTPStatus)
+	c.Check(StatusFor(fmt.Errorf("plain")), gc.Equals, DefaultHTTPStatus)
+
+	// The outermost declared status wins.
+	chain := Chain(New(0, "ergo", EMyError0), New(0, "ergo", EMyError1))
+	c.Check(StatusFor(chain), gc.Equals, 409)
+	chain = Chain(New(0, "ergo", EMyError0), New(0, "ergo", EMyErrorArgs))
+	c.Check(StatusFor(chain), gc.Equals, 404)
+	c.Check(StatusFor(fmt.Errorf("context: %w", chain)), gc.Equals, 404)
+
+	status, ok := DeclaredHTTPStatus("ergo", EMyError0)
+	c.Check(ok, gc.Equals, true)
+	c.Check(status, gc.Equals, 404)
+
+	HTTPStatus("ergo", EMyError0, 0)
+	c.Check(StatusFor(New(0, "ergo", EMyError0)), gc.Equals, DefaultHTTPStatus)
+	_, ok = DeclaredHTTPStatus("ergo", EMyError0)
+	c.Check(ok, gc.Equals, false)
+}
@@ -0,0 +1,280 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"bufio"
+	"github.com/flaub/ergo"
+	"go/scanner"
+	"go/token"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// DebugContext is the number of source lines shown around each frame
+// of the debug page.
+const DebugContext = 3
+
+// DebugHandler is like Handler, but renders errors as an HTML page
+// with their full context, see WriteDebug.
+// It exposes internals and must only be enabled during development.
+func DebugHandler(fn func(w http.ResponseWriter, r *http.Request) *ergo.Error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			WriteDebug(w, r, err)
+		}
+	})
+}
+
+// WriteDebug logs an error and writes it as an HTML debug page,
+// with the HTTP status declared for it, see ergo.StatusFor.
+func WriteDebug(w http.ResponseWriter, r *http.Request, err *ergo.Error) {
+	LogError(r, err)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(Status(err))
+	RenderDebug(w, err)
+}
+
+// RenderDebug writes an HTML page describing every link of an error chain,
+// outermost first: its message, an Info table and its stack frames,
+// which are collapsible and show the surrounding source lines when
+// the source files are readable.
+func RenderDebug(w io.Writer, err *ergo.Error) error {
+	page := debugPage{
+		Status: Status(err),
+		Title:  err.Message(),
+		Links:  newDebugLinks(err, make(sourceCache)),
+	}
+	page.StatusText = http.StatusText(page.Status)
+	return debugTemplate.Execute(w, page)
+}
+
+type debugPage struct {
+	Status     int
+	StatusText string
+	Title      string
+	Links      []debugLink
+}
+
+type debugLink struct {
+	Domain  string
+	Code    ergo.ErrCode
+	Message string
+	Info    []debugInfo
+	Context string
+	Frames  []debugFrame
+	Causes  [][]debugLink
+}
+
+type debugInfo struct {
+	Key   string
+	Value interface{}
+}
+
+type debugFrame struct {
+	ergo.Frame
+	Source []sourceLine
+}
+
+type sourceLine struct {
+	Number  int
+	HTML    template.HTML
+	Current bool
+}
+
+func newDebugLinks(err *ergo.Error, cache sourceCache) []debugLink {
+	var links []debugLink
+	for ; err != nil; err = err.Inner {
+		link := debugLink{
+			Domain:  err.Domain,
+			Code:    err.Code,
+			Message: err.Message(),
+			Context: err.Context,
+		}
+		for _, key := range err.Info.Keys() {
+			link.Info = append(link.Info, debugInfo{key, ergo.Redact(key, err.Info[key])})
+		}
+		if err.Stack != nil {
+			for _, frame := range err.Stack.Frames() {
+				source := cache.lines(frame.File, frame.Line)
+				link.Frames = append(link.Frames, debugFrame{frame, source})
+			}
+		}
+		for _, cause := range err.Causes {
+			link.Causes = append(link.Causes, newDebugLinks(cause, cache))
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// sourceCache holds the lines of the source files read for one page.
+type sourceCache map[string][]string
+
+// lines returns the source lines around "line" of "file", highlighted.
+func (cache sourceCache) lines(file string, line int) []sourceLine {
+	src, ok := cache[file]
+	if !ok {
+		src = readLines(file)
+		cache[file] = src
+	}
+	if line < 1 || line > len(src) {
+		return nil
+	}
+	first := line - DebugContext
+	if first < 1 {
+		first = 1
+	}
+	last := line + DebugContext
+	if last > len(src) {
+		last = len(src)
+	}
+	var lines []sourceLine
+	for n := first; n <= last; n++ {
+		lines = append(lines, sourceLine{n, highlight(src[n-1]), n == line})
+	}
+	return lines
+}
+
+func readLines(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var lines []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		lines = append(lines, s.Text())
+	}
+	return lines
+}
+
+// highlight marks the Go tokens of a single source line with CSS classes.
+// Lines within block comments or raw strings are highlighted as code.
+func highlight(line string) template.HTML {
+	src := []byte(line)
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, func(token.Position, string) {}, scanner.ScanComments)
+
+	var b strings.Builder
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		offset := file.Offset(pos)
+		if tok == token.SEMICOLON && lit == "\n" || offset < last {
+			continue
+		}
+		text := lit
+		if text == "" {
+			text = tok.String()
+		}
+		if end := offset + len(text); end <= len(src) {
+			b.WriteString(template.HTMLEscapeString(line[last:offset]))
+			writeToken(&b, tok, line[offset:end])
+			last = end
+		}
+	}
+	b.WriteString(template.HTMLEscapeString(line[last:]))
+	return template.HTML(b.String())
+}
+
+func writeToken(b *strings.Builder, tok token.Token, text string) {
+	var class string
+	switch {
+	case tok.IsKeyword():
+		class = "kw"
+	case tok == token.STRING || tok == token.CHAR:
+		class = "str"
+	case tok == token.COMMENT:
+		class = "cm"
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		class = "num"
+	}
+	if class == "" {
+		b.WriteString(template.HTMLEscapeString(text))
+		return
+	}
+	b.WriteString(`<span class="`)
+	b.WriteString(class)
+	b.WriteString(`">`)
+	b.WriteString(template.HTMLEscapeString(text))
+	b.WriteString("</span>")
+}
+
+var debugTemplate = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Status}} {{.StatusText}}: {{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.4em; }
+h1 small { color: #888; font-weight: normal; }
+.link { border-left: 4px solid #c33; padding: 0 1em; margin: 1em 0; }
+.causes { margin-left: 2em; }
+.code { color: #c33; font-family: monospace; }
+table { border-collapse: collapse; margin: 0.5em 0; }
+th, td { border: 1px solid #ddd; padding: 0.2em 0.6em; text-align: left; font-family: monospace; }
+summary { cursor: pointer; font-family: monospace; }
+summary .file { color: #888; }
+pre { background: #f6f6f6; padding: 0.5em; overflow-x: auto; }
+pre .current { background: #fdd; display: block; }
+pre .num { color: #099; }
+pre .kw { color: #00a; font-weight: bold; }
+pre .str { color: #a31; }
+pre .cm { color: #888; font-style: italic; }
+pre .ln { color: #aaa; user-select: none; }
+</style>
+</head>
+<body>
+<h1>{{.Title}} <small>{{.Status}} {{.StatusText}}</small></h1>
+{{template "links" .Links}}
+</body>
+</html>
+{{define "links"}}{{range .}}<div class="link">
+<h2><span class="code">[{{.Domain}}:{{.Code}}]</span> {{.Message}}</h2>
+{{if .Info}}<table>
+<tr><th>Key</th><th>Value</th></tr>
+{{range .Info}}<tr><td>{{.Key}}</td><td>{{printf "%v" .Value}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Context}}<pre>{{.Context}}</pre>
+{{end}}{{range $i, $frame := .Frames}}<details{{if eq $i 0}} open{{end}}>
+<summary>{{.Function}} <span class="file">{{.File}}:{{.Line}}</span></summary>
+{{if .Source}}<pre>{{range .Source}}<span{{if .Current}} class="current"{{end}}><span class="ln">{{printf "%5d" .Number}}</span>  {{.HTML}}</span>
+{{end}}</pre>{{end}}
+</details>
+{{end}}{{if .Causes}}<div class="causes">
+{{range .Causes}}{{template "links" .}}{{end}}</div>
+{{end}}</div>
+{{end}}{{end}}
+`))
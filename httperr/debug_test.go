@@ -0,0 +1,67 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (t *TestSuite) TestDebugHandler(c *gc.C) {
+	defer func(fn func(*http.Request, *ergo.Error)) { LogError = fn }(LogError)
+	LogError = func(*http.Request, *ergo.Error) {}
+
+	handler := DebugHandler(func(w http.ResponseWriter, r *http.Request) *ergo.Error {
+		inner := ergo.Join(
+			ergo.New(0, "httperr", EConflict, "<id>", 7),
+			ergo.New(0, "httperr", EConflict, ergo.WithStackDepth(0)),
+		)
+		return ergo.Chain(inner, ergo.New(0, "httperr", ENotFound, "name", "<page>")).(*ergo.Error)
+	})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	c.Check(rec.Code, gc.Equals, http.StatusNotFound)
+	c.Check(rec.Header().Get("Content-Type"), gc.Equals, "text/html; charset=utf-8")
+
+	page := rec.Body.String()
+	c.Check(page, gc.Matches, `(?s)<!DOCTYPE html>.*<title>404 Not Found: The &lt;page&gt; was not found</title>.*`)
+	c.Check(strings.Index(page, "[httperr:0]") < strings.Index(page, "[go:0]"), gc.Equals, true)
+	c.Check(strings.Count(page, "[httperr:1]"), gc.Equals, 2)
+	c.Check(page, gc.Matches, `(?s).*<td>name</td><td>&lt;page&gt;</td>.*`)
+	c.Check(page, gc.Matches, `(?s).*<td>&lt;id&gt;</td><td>7</td>.*`)
+	c.Check(page, gc.Matches, `(?s).*<details open>\s*<summary>.*TestDebugHandler.func\d+ .*debug_test.go:\d+</span></summary>.*`)
+	c.Check(page, gc.Matches, `(?s).*<span class="current">.*ergo.New\(<span class="num">0</span>, <span class="str">&#34;httperr&#34;</span>.*`)
+}
+
+func (t *TestSuite) TestHighlight(c *gc.C) {
+	c.Check(string(highlight(`	if x := "a<b"; x != '\n' { // done`)), gc.Equals,
+		`	<span class="kw">if</span> x := <span class="str">&#34;a&lt;b&#34;</span>; `+
+			`x != <span class="str">&#39;\n&#39;</span> { <span class="cm">// done</span>`)
+	c.Check(string(highlight("return 42")), gc.Equals, `<span class="kw">return</span> <span class="num">42</span>`)
+	c.Check(string(highlight("`unterminated")), gc.Equals, `<span class="str">`+"`unterminated</span>")
+	c.Check(string(highlight("")), gc.Equals, "")
+}
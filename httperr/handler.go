@@ -0,0 +1,79 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"encoding/json"
+	"github.com/flaub/ergo"
+	"log"
+	"net/http"
+)
+
+// HandlerFunc is an HTTP handler which may fail with an error.
+// A returned error is rendered by WriteError.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) *ergo.Error
+
+// ServeHTTP implements http.Handler.
+func (fn HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := fn(w, r); err != nil {
+		WriteError(w, r, err)
+	}
+}
+
+// Handler adapts an error-returning function to http.Handler.
+func Handler(fn func(w http.ResponseWriter, r *http.Request) *ergo.Error) http.Handler {
+	return HandlerFunc(fn)
+}
+
+// LogError is called by WriteError to record errors server-side,
+// including the context which is never sent to clients.
+// It may be replaced to use another logger.
+var LogError = func(r *http.Request, err *ergo.Error) {
+	log.Printf("%v %v: %+v", r.Method, r.URL.Path, err)
+}
+
+// WriteError logs an error and writes it as problem details,
+// with the HTTP status declared for it, see ergo.StatusFor.
+// Stack traces and reserved Info entries are not sent.
+func WriteError(w http.ResponseWriter, r *http.Request, err *ergo.Error) {
+	writeError(w, r, err, Status(err))
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err *ergo.Error, status int) {
+	LogError(r, err)
+	problem := Problem(err)
+	problem.Status = status
+	problem.Title = http.StatusText(status)
+	body, jerr := json.Marshal(problem)
+	if jerr != nil {
+		// Info values which cannot be encoded are dropped.
+		delete(problem.Extensions, "info")
+		body, _ = json.Marshal(problem)
+	}
+	header := w.Header()
+	header.Set("Content-Type", ContentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	w.Write(body)
+}
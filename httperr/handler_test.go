@@ -0,0 +1,79 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (t *TestSuite) TestHandler(c *gc.C) {
+	var logged []string
+	defer func(fn func(*http.Request, *ergo.Error)) { LogError = fn }(LogError)
+	LogError = func(r *http.Request, err *ergo.Error) {
+		logged = append(logged, r.URL.Path+" "+err.Error())
+	}
+
+	handler := Handler(func(w http.ResponseWriter, r *http.Request) *ergo.Error {
+		if r.URL.Path == "/ok" {
+			w.Write([]byte("ok"))
+			return nil
+		}
+		return ergo.New(0, "httperr", ENotFound, "name", "page", "_secret", "x")
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/ok", nil))
+	c.Check(rec.Code, gc.Equals, http.StatusOK)
+	c.Check(rec.Body.String(), gc.Equals, "ok")
+	c.Check(logged, gc.HasLen, 0)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/missing", nil))
+	c.Check(rec.Code, gc.Equals, http.StatusNotFound)
+	c.Check(rec.Header().Get("Content-Type"), gc.Equals, ContentType)
+	c.Check(rec.Body.String(), gc.Equals, `{"code":0,"detail":"The page was not found",`+
+		`"domain":"httperr","info":{"name":"page"},"status":404,`+
+		`"title":"Not Found","type":"urn:ergo:httperr:0"}`)
+
+	c.Assert(logged, gc.HasLen, 1)
+	c.Check(strings.HasPrefix(logged[0], "/missing [httperr:0] The page was not found\n"), gc.Equals, true)
+	c.Check(logged[0], gc.Matches, "(?s).*TestHandler.*")
+}
+
+func (t *TestSuite) TestWriteErrorUnencodable(c *gc.C) {
+	defer func(fn func(*http.Request, *ergo.Error)) { LogError = fn }(LogError)
+	LogError = func(*http.Request, *ergo.Error) {}
+
+	rec := httptest.NewRecorder()
+	err := ergo.New(0, "httperr", EConflict, "ch", make(chan int))
+	WriteError(rec, httptest.NewRequest("POST", "/", nil), err)
+	c.Check(rec.Code, gc.Equals, http.StatusInternalServerError)
+	c.Check(rec.Body.String(), gc.Equals, `{"code":1,"detail":"Conflict",`+
+		`"domain":"httperr","status":500,`+
+		`"title":"Internal Server Error","type":"urn:ergo:httperr:1"}`)
+}
@@ -0,0 +1,131 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"fmt"
+	"github.com/flaub/ergo"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// JSONAPIContentType is the media type of JSON:API documents.
+const JSONAPIContentType = "application/vnd.api+json"
+
+// Info keys which JSON:API maps onto members of error objects
+// rather than onto their meta member.
+const (
+	// IDKey identifies this particular occurrence of an error.
+	IDKey = "id"
+
+	// PointerKey is a JSON pointer to the offending part of the request
+	// document, e.g. "/data/attributes/title".
+	PointerKey = "pointer"
+
+	// ParameterKey names the offending query parameter.
+	ParameterKey = "parameter"
+
+	// HeaderKey names the offending request header.
+	HeaderKey = "header"
+)
+
+// JSONAPIDocument is a JSON:API top-level document carrying errors.
+type JSONAPIDocument struct {
+	Errors []JSONAPIError `json:"errors"`
+}
+
+// JSONAPIError is a JSON:API error object.
+type JSONAPIError struct {
+	ID     string                 `json:"id,omitempty"`
+	Status string                 `json:"status,omitempty"`
+	Code   string                 `json:"code,omitempty"`
+	Title  string                 `json:"title,omitempty"`
+	Detail string                 `json:"detail,omitempty"`
+	Source *JSONAPISource         `json:"source,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// JSONAPISource identifies the part of the request which caused an error.
+type JSONAPISource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+	Header    string `json:"header,omitempty"`
+}
+
+// JSONAPI converts an error into a JSON:API document.
+// An error created by ergo.Join yields one error object per cause,
+// for example one per invalid field; any other error yields one object.
+func JSONAPI(err *ergo.Error) JSONAPIDocument {
+	var doc JSONAPIDocument
+	if len(err.Causes) == 0 {
+		doc.Errors = []JSONAPIError{JSONAPIObject(err)}
+		return doc
+	}
+	for _, cause := range err.Causes {
+		doc.Errors = append(doc.Errors, JSONAPIObject(cause))
+	}
+	return doc
+}
+
+// JSONAPIObject converts a single error into a JSON:API error object.
+// The code is "domain:code" and the remaining Info is kept in meta.
+func JSONAPIObject(err *ergo.Error) JSONAPIError {
+	status := Status(err)
+	obj := JSONAPIError{
+		Status: strconv.Itoa(status),
+		Code:   fmt.Sprintf("%v:%d", err.Domain, err.Code),
+		Title:  http.StatusText(status),
+		Detail: err.Message(),
+	}
+	var source JSONAPISource
+	info := make(ergo.ErrInfo)
+	for key, value := range err.Info {
+		value = ergo.Redact(key, value)
+		str, isString := value.(string)
+		switch {
+		case key == IDKey && isString:
+			obj.ID = str
+		case key == PointerKey && isString:
+			source.Pointer = str
+		case key == ParameterKey && isString:
+			source.Parameter = str
+		case key == HeaderKey && isString:
+			source.Header = str
+		case !strings.HasPrefix(key, "_"):
+			info[key] = value
+		}
+	}
+	if source != (JSONAPISource{}) {
+		obj.Source = &source
+	}
+	obj.Meta = map[string]interface{}{
+		"domain": err.Domain,
+		"code":   err.Code,
+	}
+	if len(info) != 0 {
+		obj.Meta["info"] = info
+	}
+	return obj
+}
@@ -0,0 +1,58 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"encoding/json"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+)
+
+func (t *TestSuite) TestJSONAPI(c *gc.C) {
+	err := ergo.New(0, "httperr", ENotFound,
+		"name", "invoice",
+		"id", "42",
+		"parameter", "invoice_id",
+	)
+	data, jerr := json.Marshal(JSONAPI(err))
+	c.Assert(jerr, gc.IsNil)
+	c.Check(string(data), gc.Equals, `{"errors":[{"id":"42","status":"404","code":"httperr:0",`+
+		`"title":"Not Found","detail":"The invoice was not found",`+
+		`"source":{"parameter":"invoice_id"},`+
+		`"meta":{"code":0,"domain":"httperr","info":{"name":"invoice"}}}]}`)
+}
+
+func (t *TestSuite) TestJSONAPIJoin(c *gc.C) {
+	err := ergo.Join(
+		ergo.New(0, "httperr", EConflict, "pointer", "/data/attributes/title"),
+		ergo.New(0, "httperr", ENotFound, "name", "author", "pointer", "/data/relationships/author"),
+	)
+	doc := JSONAPI(err)
+	c.Assert(doc.Errors, gc.HasLen, 2)
+	c.Check(doc.Errors[0].Status, gc.Equals, "500")
+	c.Check(doc.Errors[0].Source, gc.DeepEquals, &JSONAPISource{Pointer: "/data/attributes/title"})
+	c.Check(doc.Errors[0].Meta["info"], gc.IsNil)
+	c.Check(doc.Errors[1].Detail, gc.Equals, "The author was not found")
+	c.Check(doc.Errors[1].Source.Pointer, gc.Equals, "/data/relationships/author")
+}
@@ -0,0 +1,84 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"github.com/flaub/ergo"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Negotiate returns the message of an error in the language preferred
+// by the client, among the translations available for its domain,
+// according to the Accept-Language header of the request.
+// Without an acceptable translation, Message() is returned.
+func Negotiate(r *http.Request, err *ergo.Error) string {
+	for _, locale := range AcceptLanguage(r) {
+		if msg, ok := err.Translation(locale); ok {
+			return msg
+		}
+	}
+	return err.Message()
+}
+
+// AcceptLanguage returns the languages of the Accept-Language header
+// of a request, most preferred first. Wildcards and languages with
+// a quality of 0 are left out.
+func AcceptLanguage(r *http.Request) []string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+	var languages []language
+	for _, header := range r.Header.Values("Accept-Language") {
+		for _, part := range strings.Split(header, ",") {
+			tag, params, _ := strings.Cut(part, ";")
+			tag = strings.TrimSpace(tag)
+			quality := 1.0
+			for _, param := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(param, "=")
+				if strings.TrimSpace(key) == "q" {
+					q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+					if err != nil {
+						q = 0
+					}
+					quality = q
+				}
+			}
+			if tag != "" && tag != "*" && quality > 0 {
+				languages = append(languages, language{tag, quality})
+			}
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+	tags := make([]string, len(languages))
+	for i, l := range languages {
+		tags[i] = l.tag
+	}
+	return tags
+}
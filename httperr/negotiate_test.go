@@ -0,0 +1,55 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"net/http/httptest"
+)
+
+func (t *TestSuite) TestAcceptLanguage(c *gc.C) {
+	r := httptest.NewRequest("GET", "/", nil)
+	c.Check(AcceptLanguage(r), gc.HasLen, 0)
+	r.Header.Set("Accept-Language", "en;q=0.5, fr-CH, *;q=0.1, de;q=0.8, it;q=0, es;q=x")
+	r.Header.Add("Accept-Language", "pt ; q=0.8")
+	c.Check(AcceptLanguage(r), gc.DeepEquals, []string{"fr-CH", "de", "pt", "en"})
+}
+
+func (t *TestSuite) TestNegotiate(c *gc.C) {
+	ergo.Domain("negotiate", ergo.DomainMap{0: "The {{.name}} was not found"})
+	ergo.DomainLocale("negotiate", "de", ergo.DomainMap{0: "{{.name}} wurde nicht gefunden"})
+	ergo.DomainLocale("negotiate", "fr", ergo.DomainMap{0: "{{.name}} introuvable"})
+	err := ergo.New(0, "negotiate", 0, "name", "x")
+
+	negotiate := func(header string) string {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", header)
+		return Negotiate(r, err)
+	}
+	c.Check(negotiate("it, fr-CH;q=0.9, de;q=0.8"), gc.Equals, "x introuvable")
+	c.Check(negotiate("de-AT"), gc.Equals, "x wurde nicht gefunden")
+	c.Check(negotiate("it, en;q=0.1"), gc.Equals, "The x was not found")
+	c.Check(negotiate(""), gc.Equals, "The x was not found")
+}
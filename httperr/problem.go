@@ -0,0 +1,240 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package httperr adapts ergo errors to HTTP APIs.
+package httperr
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/flaub/ergo"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ContentType is the media type of RFC 7807 problem details.
+	ContentType = "application/problem+json"
+
+	// DefaultStatus is the status of errors without an override.
+	DefaultStatus = ergo.DefaultHTTPStatus
+
+	// Domain is the domain of errors reconstructed from responses
+	// which carry no ergo error, with the status as their code.
+	Domain = "http"
+)
+
+// TypePrefix is prepended to "domain:code" to build the type URI
+// of a problem.
+var TypePrefix = "urn:ergo:"
+
+// ProblemDetails is an RFC 7807 problem details object.
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Extension members, serialized alongside the standard members.
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// SetStatus overrides the HTTP status of errors with a domain and code.
+// It is equivalent to ergo.HTTPStatus.
+func SetStatus(domain string, code ergo.ErrCode, status int) {
+	ergo.HTTPStatus(domain, code, status)
+}
+
+// Status returns the HTTP status of an error, see ergo.StatusFor.
+func Status(err *ergo.Error) int {
+	return ergo.StatusFor(err)
+}
+
+// TypeURI returns the problem type URI of a domain and code.
+func TypeURI(domain string, code ergo.ErrCode) string {
+	return fmt.Sprintf("%v%v:%d", TypePrefix, domain, code)
+}
+
+// Problem maps an error onto problem details:
+// the type is derived from the domain and code, the detail is Message(),
+// and the domain, code and Info are carried as extension members.
+// Context and stack traces are omitted, as they are meant for developers.
+// Info keys starting with an underscore are reserved and omitted.
+func Problem(err *ergo.Error) ProblemDetails {
+	status := Status(err)
+	problem := ProblemDetails{
+		Type:   TypeURI(err.Domain, err.Code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Message(),
+		Extensions: map[string]interface{}{
+			"domain": err.Domain,
+			"code":   err.Code,
+		},
+	}
+	info := make(ergo.ErrInfo)
+	for key, value := range err.Info {
+		if !strings.HasPrefix(key, "_") {
+			info[key] = ergo.Redact(key, value)
+		}
+	}
+	if len(info) != 0 {
+		problem.Extensions["info"] = info
+	}
+	return problem
+}
+
+// MarshalJSON implements json.Marshaler.
+// Extension members never replace standard members.
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+	members["type"] = p.Type
+	if p.Title != "" {
+		members["title"] = p.Title
+	}
+	if p.Status != 0 {
+		members["status"] = p.Status
+	}
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Members other than the standard ones are collected in Extensions.
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	*p = ProblemDetails{Extensions: make(map[string]interface{})}
+	for key, raw := range members {
+		var err error
+		switch key {
+		case "type":
+			err = json.Unmarshal(raw, &p.Type)
+		case "title":
+			err = json.Unmarshal(raw, &p.Title)
+		case "status":
+			err = json.Unmarshal(raw, &p.Status)
+		case "detail":
+			err = json.Unmarshal(raw, &p.Detail)
+		case "instance":
+			err = json.Unmarshal(raw, &p.Instance)
+		default:
+			var value interface{}
+			err = json.Unmarshal(raw, &value)
+			p.Extensions[key] = value
+		}
+		if err != nil {
+			return fmt.Errorf("httperr: invalid problem member %q: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// FromProblem reconstructs an error from problem details.
+// The domain and code are restored from the type URI, and Info from the
+// "info" extension member. Since the detail was rendered by the server,
+// it is kept as the message of the error.
+// Problems whose type was not produced by Problem are restored in
+// Domain, with the status as their code.
+func FromProblem(problem ProblemDetails) (*ergo.Error, error) {
+	wire := &ergo.Wire{
+		Domain: Domain,
+		Code:   ergo.ErrCode(problem.Status),
+		Info:   make(ergo.ErrInfo),
+	}
+	if domain, code, ok := parseType(problem.Type); ok {
+		wire.Domain = domain
+		wire.Code = code
+	} else {
+		wire.Info["type"] = problem.Type
+		wire.Info["title"] = problem.Title
+	}
+	if info, ok := problem.Extensions["info"].(map[string]interface{}); ok {
+		for key, value := range info {
+			wire.Info[key] = value
+		}
+	}
+	if wire.Domain == "go" {
+		wire.Info["_err"] = strings.TrimPrefix(problem.Detail, "Error: ")
+	} else {
+		wire.Info["_msg"] = problem.Detail
+	}
+	return wire.ToError()
+}
+
+func parseType(uri string) (string, ergo.ErrCode, bool) {
+	if !strings.HasPrefix(uri, TypePrefix) {
+		return "", 0, false
+	}
+	rest := uri[len(TypePrefix):]
+	sep := strings.LastIndex(rest, ":")
+	if sep <= 0 {
+		return "", 0, false
+	}
+	code, err := strconv.Atoi(rest[sep+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return rest[:sep], ergo.ErrCode(code), true
+}
+
+// ParseProblem reconstructs an error from a problem+json document,
+// see FromProblem.
+func ParseProblem(data []byte) (*ergo.Error, error) {
+	var problem ProblemDetails
+	if err := json.Unmarshal(data, &problem); err != nil {
+		return nil, err
+	}
+	return FromProblem(problem)
+}
+
+// MaxProblemSize limits the size of problem documents read from responses.
+const MaxProblemSize = 1 << 20
+
+// ReadProblem reconstructs an error from a response with a problem+json
+// body, see FromProblem. The body is consumed but not closed.
+func ReadProblem(resp *http.Response) (*ergo.Error, error) {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != ContentType {
+		return nil, fmt.Errorf("httperr: response is not %v", ContentType)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxProblemSize))
+	if err != nil {
+		return nil, err
+	}
+	return ParseProblem(data)
+}
@@ -0,0 +1,137 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+	EConflict
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("httperr", ergo.DomainMap{
+		ENotFound: "The {{.name}} was not found",
+		EConflict: "Conflict",
+	})
+	SetStatus("httperr", ENotFound, http.StatusNotFound)
+}
+
+func (t *TestSuite) TestProblem(c *gc.C) {
+	err := ergo.New(0, "httperr", ENotFound, "name", "invoice", "_secret", "x")
+	problem := Problem(err)
+	c.Check(problem.Type, gc.Equals, "urn:ergo:httperr:0")
+	c.Check(problem.Title, gc.Equals, "Not Found")
+	c.Check(problem.Status, gc.Equals, http.StatusNotFound)
+	c.Check(problem.Detail, gc.Equals, "The invoice was not found")
+
+	data, jerr := json.Marshal(problem)
+	c.Assert(jerr, gc.IsNil)
+	c.Check(string(data), gc.Equals, `{"code":0,"detail":"The invoice was not found",`+
+		`"domain":"httperr","info":{"name":"invoice"},"status":404,`+
+		`"title":"Not Found","type":"urn:ergo:httperr:0"}`)
+}
+
+func (t *TestSuite) TestProblemDefaults(c *gc.C) {
+	problem := Problem(ergo.Wrap(io.EOF))
+	c.Check(problem.Type, gc.Equals, "urn:ergo:go:0")
+	c.Check(problem.Status, gc.Equals, http.StatusInternalServerError)
+	c.Check(problem.Detail, gc.Equals, "Error: EOF")
+	_, ok := problem.Extensions["info"]
+	c.Check(ok, gc.Equals, false)
+
+	problem.Extensions["type"] = "bogus"
+	data, err := json.Marshal(problem)
+	c.Assert(err, gc.IsNil)
+	var members map[string]interface{}
+	c.Assert(json.Unmarshal(data, &members), gc.IsNil)
+	c.Check(members["type"], gc.Equals, "urn:ergo:go:0")
+}
+
+func (t *TestSuite) TestFromProblem(c *gc.C) {
+	original := ergo.New(0, "httperr", ENotFound, "name", "invoice")
+	data, jerr := json.Marshal(Problem(original))
+	c.Assert(jerr, gc.IsNil)
+
+	err, perr := ParseProblem(data)
+	c.Assert(perr, gc.IsNil)
+	c.Check(err.Domain, gc.Equals, "httperr")
+	c.Check(err.Code, gc.Equals, ENotFound)
+	c.Check(err.Info["name"], gc.Equals, "invoice")
+	c.Check(err.Message(), gc.Equals, "The invoice was not found")
+	c.Check(errors.Is(err, ergo.Code("httperr", ENotFound)), gc.Equals, true)
+
+	err, perr = FromProblem(Problem(ergo.Wrap(io.EOF)))
+	c.Assert(perr, gc.IsNil)
+	c.Check(err.Info, gc.DeepEquals, ergo.ErrInfo{"_err": "EOF"})
+	c.Check(err.Message(), gc.Equals, "Error: EOF")
+}
+
+func (t *TestSuite) TestFromForeignProblem(c *gc.C) {
+	err, perr := ParseProblem([]byte(`{"type":"https://example.com/probs/out-of-credit",` +
+		`"title":"You do not have enough credit.","status":403,` +
+		`"detail":"Your current balance is 30, but that costs 50.","balance":30}`))
+	c.Assert(perr, gc.IsNil)
+	c.Check(err.Domain, gc.Equals, "http")
+	c.Check(err.Code, gc.Equals, ergo.ErrCode(403))
+	c.Check(err.Info["type"], gc.Equals, "https://example.com/probs/out-of-credit")
+	c.Check(err.Message(), gc.Equals, "Your current balance is 30, but that costs 50.")
+
+	_, perr = ParseProblem([]byte(`{"type":"urn:ergo:httperr:9"}`))
+	c.Check(perr, gc.ErrorMatches, "ergo: \\[httperr:9\\] is not defined")
+	_, perr = ParseProblem([]byte(`{"status":"x"}`))
+	c.Check(perr, gc.ErrorMatches, "httperr: invalid problem member \"status\": .*")
+}
+
+func (t *TestSuite) TestReadProblem(c *gc.C) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", ContentType+"; charset=utf-8")
+	json.NewEncoder(rec).Encode(Problem(ergo.New(0, "httperr", EConflict)))
+	err, perr := ReadProblem(rec.Result())
+	c.Assert(perr, gc.IsNil)
+	c.Check(err.Code, gc.Equals, EConflict)
+
+	rec = httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "text/plain")
+	_, perr = ReadProblem(rec.Result())
+	c.Check(perr, gc.ErrorMatches, "httperr: response is not application/problem\\+json")
+}
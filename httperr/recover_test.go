@@ -0,0 +1,100 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"errors"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"io"
+	"net/http"
+	"net/http/httptest"
+)
+
+func panicky(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/error":
+		panic(io.ErrUnexpectedEOF)
+	case "/ergo":
+		panic(ergo.New(0, "httperr", ENotFound, "name", "page"))
+	case "/abort":
+		panic(http.ErrAbortHandler)
+	case "/nil":
+		var info ergo.ErrInfo
+		info["x"] = 1
+	case "/started":
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("partial"))
+	}
+	panic("boom")
+}
+
+func (t *TestSuite) TestRecover(c *gc.C) {
+	var logged *ergo.Error
+	defer func(fn func(*http.Request, *ergo.Error)) { LogError = fn }(LogError)
+	LogError = func(r *http.Request, err *ergo.Error) { logged = err }
+
+	handler := Recover(http.HandlerFunc(panicky))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	c.Check(rec.Code, gc.Equals, http.StatusInternalServerError)
+	c.Check(rec.Header().Get("Content-Type"), gc.Equals, ContentType)
+	c.Check(rec.Body.String(), gc.Equals, `{"code":0,"detail":"Error: panic: boom",`+
+		`"domain":"go","status":500,"title":"Internal Server Error","type":"urn:ergo:go:0"}`)
+	c.Assert(logged, gc.NotNil)
+	c.Assert(logged.Stack, gc.NotNil)
+	c.Check(logged.Stack.Frames()[0].Function, gc.Matches, ".*httperr.panicky$")
+
+	// A declared status of the panic value is ignored.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/ergo", nil))
+	c.Check(rec.Code, gc.Equals, http.StatusInternalServerError)
+	c.Check(errors.Is(logged, ergo.Code("httperr", ENotFound)), gc.Equals, true)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/error", nil))
+	c.Check(rec.Code, gc.Equals, http.StatusInternalServerError)
+	c.Check(errors.Is(logged, io.ErrUnexpectedEOF), gc.Equals, true)
+
+	// A runtime panic starts the stack at the handler, not the runtime.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/nil", nil))
+	c.Check(rec.Code, gc.Equals, http.StatusInternalServerError)
+	c.Check(logged.Stack.Frames()[0].Function, gc.Matches, ".*httperr.panicky$")
+
+	// A started response is left alone, the error is only logged.
+	logged = nil
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/started", nil))
+	c.Check(rec.Code, gc.Equals, http.StatusAccepted)
+	c.Check(rec.Header().Get("Content-Type"), gc.Not(gc.Equals), ContentType)
+	c.Check(rec.Body.String(), gc.Equals, "partial")
+	c.Assert(logged, gc.NotNil)
+	c.Check(logged.Message(), gc.Equals, "Error: panic: boom")
+
+	c.Check(func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/abort", nil))
+	}, gc.PanicMatches, "net/http: abort Handler")
+}
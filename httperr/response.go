@@ -0,0 +1,81 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"fmt"
+	"github.com/flaub/ergo"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// FromResponse converts a response with a non-2xx status into an error,
+// or returns nil for a 2xx status.
+// The error is in Domain, with the status as its code, and records the
+// "method", "url" and "status" of the request in Info.
+// A problem+json or ergo JSON body is restored as the Inner error.
+// The body is consumed but not closed.
+func FromResponse(resp *http.Response) *ergo.Error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	msg := fmt.Sprintf("HTTP %v", resp.Status)
+	args := []interface{}{"status", resp.StatusCode}
+	if req := resp.Request; req != nil {
+		url := req.URL.Redacted()
+		msg = fmt.Sprintf("%v %v: %v", req.Method, url, resp.Status)
+		args = append(args, "method", req.Method, "url", url)
+	}
+	args = append(args, "_msg", msg)
+	if inner := readBody(resp); inner != nil {
+		args = append(args, ergo.WithInner(inner))
+	}
+	return ergo.New(1, Domain, ergo.ErrCode(resp.StatusCode), args...)
+}
+
+// readBody restores the error carried by a response body, if any.
+func readBody(resp *http.Response) *ergo.Error {
+	mediaType, _, perr := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if perr != nil {
+		return nil
+	}
+	if mediaType != ContentType && mediaType != "application/json" {
+		return nil
+	}
+	data, rerr := io.ReadAll(io.LimitReader(resp.Body, MaxProblemSize))
+	if rerr != nil {
+		return nil
+	}
+	var err *ergo.Error
+	if mediaType == ContentType {
+		err, rerr = ParseProblem(data)
+	} else {
+		err, rerr = ergo.FromJSON(data)
+	}
+	if rerr != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package httperr
+
+import (
+	"errors"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (t *TestSuite) TestFromResponse(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+		case "/problem":
+			data, _ := Problem(ergo.New(0, "httperr", ENotFound, "name", "page")).MarshalJSON()
+			w.Header().Set("Content-Type", ContentType)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write(data)
+		case "/ergo":
+			data, _ := ergo.New(0, "httperr", EConflict).MarshalJSON()
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusConflict)
+			w.Write(data)
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"bad"}`))
+		default:
+			http.Error(w, "oops", http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	get := func(path string) *ergo.Error {
+		resp, err := http.Get(server.URL + path)
+		c.Assert(err, gc.IsNil)
+		defer resp.Body.Close()
+		return FromResponse(resp)
+	}
+
+	c.Check(get("/ok"), gc.IsNil)
+
+	err := get("/problem")
+	c.Assert(err, gc.NotNil)
+	c.Check(err.Domain, gc.Equals, Domain)
+	c.Check(err.Code, gc.Equals, ergo.ErrCode(http.StatusNotFound))
+	c.Check(err.Message(), gc.Equals, "GET "+server.URL+"/problem: 404 Not Found")
+	c.Check(err.Info["method"], gc.Equals, "GET")
+	c.Check(err.Info["url"], gc.Equals, server.URL+"/problem")
+	c.Check(err.Info["status"], gc.Equals, http.StatusNotFound)
+	c.Assert(err.Inner, gc.NotNil)
+	c.Check(err.Inner.Message(), gc.Equals, "The page was not found")
+	c.Check(errors.Is(err, ergo.Code("httperr", ENotFound)), gc.Equals, true)
+
+	err = get("/ergo")
+	c.Check(err.Code, gc.Equals, ergo.ErrCode(http.StatusConflict))
+	c.Check(errors.Is(err, ergo.Code("httperr", EConflict)), gc.Equals, true)
+
+	err = get("/json")
+	c.Check(err.Code, gc.Equals, ergo.ErrCode(http.StatusBadRequest))
+	c.Check(err.Inner, gc.IsNil)
+
+	err = get("/other")
+	c.Check(err.Message(), gc.Equals, "GET "+server.URL+"/other: 502 Bad Gateway")
+	c.Check(err.Inner, gc.IsNil)
+
+	err = FromResponse(&http.Response{StatusCode: 503, Status: "503 Service Unavailable"})
+	c.Check(err.Message(), gc.Equals, "HTTP 503 Service Unavailable")
+}
@@ -0,0 +1,212 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package icu provides ICU MessageFormat as an alternative to text/template
+// for the message formats of ergo domains. Unlike templates, messages can
+// express plural, ordinal and select rules correctly for every language:
+//
+//	icu.Domain("files", ergo.DomainMap{
+//		EFailed: "{count, plural, =0 {No file} one {# file} other {# files}} failed",
+//	})
+//
+// Arguments are Info values: "{name}" is replaced by a value,
+// "{name, number}" by a number formatted for the locale, and "plural",
+// "selectordinal" and "select" arguments choose between sub-messages.
+// Date, time and other argument types are not supported.
+package icu
+
+import (
+	"fmt"
+	"github.com/flaub/ergo"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Message is a parsed message.
+type Message struct {
+	nodes []node
+}
+
+// context holds the state of a message being formatted.
+type context struct {
+	tag     language.Tag
+	printer *message.Printer
+	args    map[string]interface{}
+	hash    interface{}
+}
+
+// Format formats a message with the rules of "locale", a BCP 47 tag.
+// Unknown locales use the rules of English.
+func (m *Message) Format(locale string, args map[string]interface{}) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		tag = language.English
+	}
+	ctx := &context{tag: tag, printer: message.NewPrinter(tag), args: args}
+	var b strings.Builder
+	formatNodes(&b, ctx, m.nodes)
+	return b.String()
+}
+
+func formatNodes(b *strings.Builder, ctx *context, nodes []node) {
+	for _, n := range nodes {
+		n.format(b, ctx)
+	}
+}
+
+func (t text) format(b *strings.Builder, ctx *context) {
+	b.WriteString(string(t))
+}
+
+func (hash) format(b *strings.Builder, ctx *context) {
+	b.WriteString(ctx.printer.Sprint(ctx.hash))
+}
+
+func (a arg) format(b *strings.Builder, ctx *context) {
+	value, ok := ctx.args[a.name]
+	switch {
+	case !ok:
+		b.WriteString("<no value>")
+	case a.number:
+		b.WriteString(ctx.printer.Sprint(value))
+	default:
+		fmt.Fprint(b, value)
+	}
+}
+
+func (c *choice) format(b *strings.Builder, ctx *context) {
+	value := ctx.args[c.name]
+	if c.kind == "select" {
+		if nodes, ok := c.cases[fmt.Sprint(value)]; ok {
+			formatNodes(b, ctx, nodes)
+		} else {
+			formatNodes(b, ctx, c.cases["other"])
+		}
+		return
+	}
+	outer := ctx.hash
+	defer func() { ctx.hash = outer }()
+	n, ok := number(value)
+	if !ok {
+		ctx.hash = value
+		formatNodes(b, ctx, c.cases["other"])
+		return
+	}
+	ctx.hash = n - c.offset
+	if nodes, ok := c.cases["="+strconv.FormatFloat(n, 'f', -1, 64)]; ok {
+		formatNodes(b, ctx, nodes)
+		return
+	}
+	rules := plural.Cardinal
+	if c.kind == "selectordinal" {
+		rules = plural.Ordinal
+	}
+	if nodes, ok := c.cases[category(rules, ctx.tag, n-c.offset)]; ok {
+		formatNodes(b, ctx, nodes)
+	} else {
+		formatNodes(b, ctx, c.cases["other"])
+	}
+}
+
+// number converts a numeric value, or a string holding one, to float64.
+func number(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		n, err := strconv.ParseFloat(v.String(), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// category returns the plural category of a number in a language.
+func category(rules *plural.Rules, tag language.Tag, n float64) string {
+	digits := strconv.FormatFloat(math.Abs(n), 'f', -1, 64)
+	integer, fraction, _ := strings.Cut(digits, ".")
+	trimmed := strings.TrimRight(fraction, "0")
+	i, _ := strconv.Atoi(integer)
+	f, _ := strconv.Atoi("0" + fraction)
+	t, _ := strconv.Atoi("0" + trimmed)
+	switch rules.MatchPlural(tag, i, len(fraction), len(trimmed), f, t) {
+	case plural.Zero:
+		return "zero"
+	case plural.One:
+		return "one"
+	case plural.Two:
+		return "two"
+	case plural.Few:
+		return "few"
+	case plural.Many:
+		return "many"
+	}
+	return "other"
+}
+
+// compile parses the message formats of a domain,
+// panicking if one cannot be parsed, as ergo.Domain does.
+func compile(domain ergo.DomainMap) map[ergo.ErrCode]*Message {
+	msgs := make(map[ergo.ErrCode]*Message, len(domain))
+	for code, text := range domain {
+		msgs[code] = MustParse(text)
+	}
+	return msgs
+}
+
+// Domain defines a domain whose message formats are ICU messages,
+// formatted with the rules of English, see ergo.DomainFunc.
+func Domain(name string, domain ergo.DomainMap) {
+	msgs := compile(domain)
+	ergo.DomainFunc(name, func(err *ergo.Error) string {
+		msg, ok := msgs[err.Code]
+		if !ok {
+			return "Unknown error"
+		}
+		return msg.Format("en", ergo.RedactInfo(err.Info))
+	})
+}
+
+// DomainLocale defines translated message formats of a domain as
+// ICU messages, formatted with the rules of "locale",
+// see ergo.DomainLocaleFunc.
+func DomainLocale(name, locale string, domain ergo.DomainMap) {
+	msgs := compile(domain)
+	locale = strings.ToLower(strings.Replace(locale, "_", "-", -1))
+	ergo.DomainLocaleFunc(name, func(err *ergo.Error, target string) (string, bool) {
+		msg, ok := msgs[err.Code]
+		if !ok || target != locale {
+			return "", false
+		}
+		return msg.Format(locale, ergo.RedactInfo(err.Info)), true
+	})
+}
@@ -0,0 +1,94 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package icu
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	EFiles = ergo.ErrCode(iota)
+	EAttempt
+	EShared
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	Domain("icu", ergo.DomainMap{
+		EFiles:   "{count, plural, =0 {No file} one {# file} other {# files}} failed",
+		EAttempt: "The {n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}} attempt failed",
+		EShared: "{gender, select, female {She} male {He} other {They}} shared " +
+			"{count, plural, offset:1 =0 {nothing} =1 {a file} one {a file and # other} other {a file and # others}}",
+	})
+	DomainLocale("icu", "pl", ergo.DomainMap{
+		EFiles: "{count, plural, one {# plik} few {# pliki} many {# plików} other {# pliku}}",
+	})
+}
+
+func (t *TestSuite) TestDomain(c *gc.C) {
+	files := func(count interface{}) *ergo.Error {
+		return ergo.New(0, "icu", EFiles, "count", count)
+	}
+	c.Check(files(0).Message(), gc.Equals, "No file failed")
+	c.Check(files(1).Message(), gc.Equals, "1 file failed")
+	c.Check(files(uint8(2)).Message(), gc.Equals, "2 files failed")
+	c.Check(files(1.5).Message(), gc.Equals, "1.5 files failed")
+	c.Check(files(1234).Message(), gc.Equals, "1,234 files failed")
+	c.Check(files("many").Message(), gc.Equals, "many files failed")
+
+	for n, out := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 22: "22nd"} {
+		c.Check(ergo.New(0, "icu", EAttempt, "n", n).Message(), gc.Equals,
+			"The "+out+" attempt failed")
+	}
+
+	shared := func(gender string, count int) string {
+		return ergo.New(0, "icu", EShared, "gender", gender, "count", count).Message()
+	}
+	c.Check(shared("female", 0), gc.Equals, "She shared nothing")
+	c.Check(shared("male", 1), gc.Equals, "He shared a file")
+	c.Check(shared("", 2), gc.Equals, "They shared a file and 1 other")
+	c.Check(shared("", 5), gc.Equals, "They shared a file and 4 others")
+}
+
+func (t *TestSuite) TestDomainLocale(c *gc.C) {
+	files := func(count int) string {
+		return ergo.New(0, "icu", EFiles, "count", count).MessageIn("pl-PL")
+	}
+	c.Check(files(1), gc.Equals, "1 plik")
+	c.Check(files(3), gc.Equals, "3 pliki")
+	c.Check(files(5), gc.Equals, "5 plików")
+	c.Check(files(22), gc.Equals, "22 pliki")
+	c.Check(ergo.New(0, "icu", EAttempt, "n", 2).MessageIn("pl"), gc.Equals,
+		"The 2nd attempt failed")
+}
@@ -0,0 +1,258 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package icu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// node is a part of a message.
+type node interface {
+	format(b *strings.Builder, ctx *context)
+}
+
+// text is literal text.
+type text string
+
+// hash is the number of the innermost plural, written "#".
+type hash struct{}
+
+// arg is a value of Info, written "{name}" or "{name, number}".
+type arg struct {
+	name   string
+	number bool
+}
+
+// choice selects a sub-message by the value of an Info key.
+type choice struct {
+	name   string
+	kind   string
+	offset float64
+	cases  map[string][]node
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+// Parse parses a message in ICU MessageFormat syntax.
+func Parse(s string) (*Message, error) {
+	p := &parser{s: s}
+	nodes, err := p.message(false, false)
+	if err != nil {
+		return nil, fmt.Errorf("icu: %v at offset %d of %q", err, p.pos, s)
+	}
+	return &Message{nodes: nodes}, nil
+}
+
+// MustParse is like Parse but panics if the message cannot be parsed.
+func MustParse(s string) *Message {
+	msg, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return msg
+}
+
+// message parses nodes up to the end of the string or,
+// if nested, up to the closing brace of the enclosing argument.
+func (p *parser) message(plural, nested bool) ([]node, error) {
+	var nodes []node
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() != 0 {
+			nodes = append(nodes, text(buf.String()))
+			buf.Reset()
+		}
+	}
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '\'':
+			p.quoted(&buf, plural)
+			continue
+		case c == '{':
+			flush()
+			n, err := p.argument(plural)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+			continue
+		case c == '}':
+			if !nested {
+				return nil, fmt.Errorf("unmatched }")
+			}
+			flush()
+			return nodes, nil
+		case c == '#' && plural:
+			flush()
+			nodes = append(nodes, hash{})
+		default:
+			buf.WriteByte(c)
+		}
+		p.pos++
+	}
+	if nested {
+		return nil, fmt.Errorf("unclosed {")
+	}
+	flush()
+	return nodes, nil
+}
+
+// quoted parses an apostrophe. A doubled apostrophe is a literal one,
+// while an apostrophe before a special character quotes the text up to
+// the next single apostrophe. Other apostrophes are literal.
+func (p *parser) quoted(buf *strings.Builder, plural bool) {
+	p.pos++
+	if p.pos == len(p.s) {
+		buf.WriteByte('\'')
+		return
+	}
+	switch c := p.s[p.pos]; {
+	case c == '\'':
+		buf.WriteByte('\'')
+		p.pos++
+		return
+	case c == '{' || c == '}' || c == '|' || (c == '#' && plural):
+	default:
+		buf.WriteByte('\'')
+		return
+	}
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		if c != '\'' {
+			buf.WriteByte(c)
+		} else if p.pos < len(p.s) && p.s[p.pos] == '\'' {
+			buf.WriteByte('\'')
+			p.pos++
+		} else {
+			return
+		}
+	}
+}
+
+// argument parses an argument, starting at its opening brace.
+func (p *parser) argument(plural bool) (node, error) {
+	p.pos++
+	name := p.word()
+	if name == "" {
+		return nil, fmt.Errorf("missing argument name")
+	}
+	if p.consume('}') {
+		return arg{name: name}, nil
+	}
+	if !p.consume(',') {
+		return nil, fmt.Errorf("expected , or }")
+	}
+	switch kind := p.word(); kind {
+	case "number":
+		// Styles are accepted, but numbers always use the default style.
+		if p.consume(',') {
+			p.word()
+		}
+		if !p.consume('}') {
+			return nil, fmt.Errorf("expected }")
+		}
+		return arg{name: name, number: true}, nil
+	case "plural", "selectordinal", "select":
+		if !p.consume(',') {
+			return nil, fmt.Errorf("expected ,")
+		}
+		return p.choice(name, kind, plural)
+	case "":
+		return nil, fmt.Errorf("missing argument type")
+	default:
+		return nil, fmt.Errorf("unsupported argument type %q", kind)
+	}
+}
+
+// choice parses the cases of a plural, selectordinal or select argument.
+func (p *parser) choice(name, kind string, plural bool) (node, error) {
+	n := &choice{name: name, kind: kind, cases: make(map[string][]node)}
+	if kind != "select" {
+		plural = true
+		p.space()
+		if strings.HasPrefix(p.s[p.pos:], "offset:") {
+			p.pos += len("offset:")
+			offset, err := strconv.ParseFloat(p.word(), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid offset")
+			}
+			n.offset = offset
+		}
+	}
+	for !p.consume('}') {
+		if p.pos == len(p.s) {
+			return nil, fmt.Errorf("unclosed {")
+		}
+		selector := p.word()
+		if selector == "" {
+			return nil, fmt.Errorf("missing selector")
+		}
+		if !p.consume('{') {
+			return nil, fmt.Errorf("expected { after %v", selector)
+		}
+		nodes, err := p.message(plural, true)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++
+		n.cases[selector] = nodes
+	}
+	if _, ok := n.cases["other"]; !ok {
+		return nil, fmt.Errorf("%v of %v has no other case", kind, name)
+	}
+	return n, nil
+}
+
+// word parses a run of characters up to a space or syntax character.
+func (p *parser) word() string {
+	p.space()
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune(" \t\r\n{},", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// consume skips spaces and the character "c", reporting whether it was found.
+func (p *parser) consume(c byte) bool {
+	p.space()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) space() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
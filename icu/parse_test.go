@@ -0,0 +1,60 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package icu
+
+import (
+	gc "github.com/motain/gocheck"
+)
+
+func (t *TestSuite) TestParse(c *gc.C) {
+	for _, test := range []struct{ text, out string }{
+		{"Hello", "Hello"},
+		{"It''s {name}", "It's x"},
+		{"Don't '{name}' me", "Don't {name} me"},
+		{"'{'{name}'}'", "{x}"},
+		{"{n, plural, other {'#' is #}}", "# is 3"},
+		{"{ name }", "x"},
+		{"{missing}", "<no value>"},
+	} {
+		msg, err := Parse(test.text)
+		c.Assert(err, gc.IsNil, gc.Commentf(test.text))
+		c.Check(msg.Format("en", map[string]interface{}{"name": "x", "n": 3}),
+			gc.Equals, test.out, gc.Commentf(test.text))
+	}
+
+	for _, test := range []struct{ text, err string }{
+		{"a } b", "unmatched }"},
+		{"{name", "expected , or }"},
+		{"{}", "missing argument name"},
+		{"{n, date}", `unsupported argument type "date"`},
+		{"{n, plural, one {x}}", "plural of n has no other case"},
+		{"{n, plural, other {x}", "unclosed {"},
+		{"{n, select, other x}", "expected { after other"},
+		{"{n, plural, offset:x other {}}", "invalid offset"},
+	} {
+		_, err := Parse(test.text)
+		c.Check(err, gc.ErrorMatches, "icu: "+test.err+" at offset .*", gc.Commentf(test.text))
+	}
+	c.Check(func() { MustParse("{") }, gc.PanicMatches, "icu: .*")
+}
@@ -0,0 +1,187 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IsReserved reports whether an Info key is reserved by ergo:
+// "_err" holds the message of a wrapped go error, "_msg" a preformatted
+// message and "_note" the message given to Wrapf. These are already
+// part of the message, so consumers listing Info may leave them out.
+func IsReserved(key string) bool {
+	return key == "_err" || key == "_msg" || key == "_note"
+}
+
+// Keys returns the keys of this collection in sorted order.
+func (info ErrInfo) Keys() []string {
+	keys := make([]string, 0, len(info))
+	for key := range info {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// String formats this collection as fmt does for maps,
+// always with keys in sorted order. Values are redacted, see Redact.
+func (info ErrInfo) String() string {
+	var b strings.Builder
+	b.WriteString("map[")
+	for i, key := range info.Keys() {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%v:%v", key, Redact(key, info[key]))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// With sets an Info value of this error and returns the error,
+// so that callers can enrich an error as it is returned:
+//
+//	return err.With("retry", 3)
+//
+// Like Info itself, this is not safe to call concurrently.
+func (err *Error) With(key string, value interface{}) *Error {
+	if err.Info == nil {
+		err.Info = make(ErrInfo)
+	}
+	err.Info[key] = value
+	return err
+}
+
+// WithInfo sets several Info values of this error and returns the error.
+func (err *Error) WithInfo(info ErrInfo) *Error {
+	for key, value := range info {
+		err.With(key, value)
+	}
+	return err
+}
+
+// InfoValue returns the value of an Info key of "err", searching every
+// link of its chain, outermost first, see Walk.
+func InfoValue(err error, key string) (interface{}, bool) {
+	var value interface{}
+	found := false
+	Walk(err, func(link *Error) bool {
+		value, found = link.Info[key]
+		return !found
+	})
+	return value, found
+}
+
+// Info returns the value of an Info key of "err" as a T, searching its
+// chain as InfoValue does. It reports false if the key is missing or its
+// value is not a T, instead of panicking as a type assertion would:
+//
+//	id, ok := ergo.Info[uuid.UUID](err, "id")
+//
+// The value of a Secret is returned as well.
+func Info[T any](err error, key string) (T, bool) {
+	value, _ := InfoValue(err, key)
+	v, ok := value.(T)
+	if r, secret := value.(Redacted); !ok && secret {
+		v, ok = r.value.(T)
+	}
+	return v, ok
+}
+
+// InfoString returns the value of an Info key of "err" as a string,
+// see Info.
+func InfoString(err error, key string) (string, bool) {
+	return Info[string](err, key)
+}
+
+// InfoInt returns the value of an Info key of "err" as an int, see Info.
+// Any integer type is converted, as are whole float64 values,
+// such as numbers decoded from JSON. Values which overflow an int
+// report false.
+func InfoInt(err error, key string) (int, bool) {
+	switch v := infoValue(err, key).(type) {
+	case int:
+		return v, true
+	case int8:
+		return int(v), true
+	case int16:
+		return int(v), true
+	case int32:
+		return int(v), true
+	case int64:
+		if v >= math.MinInt && v <= math.MaxInt {
+			return int(v), true
+		}
+	case uint:
+		if v <= math.MaxInt {
+			return int(v), true
+		}
+	case uint8:
+		return int(v), true
+	case uint16:
+		return int(v), true
+	case uint32:
+		if uint64(v) <= math.MaxInt {
+			return int(v), true
+		}
+	case uint64:
+		if v <= math.MaxInt {
+			return int(v), true
+		}
+	case float64:
+		// float64(math.MaxInt) rounds up, so compare against -math.MinInt.
+		if v == math.Trunc(v) && v >= math.MinInt && v < -math.MinInt {
+			return int(v), true
+		}
+	}
+	return 0, false
+}
+
+// InfoDuration returns the value of an Info key of "err" as a
+// time.Duration, see Info. Strings such as "1.5s" are parsed as well.
+func InfoDuration(err error, key string) (time.Duration, bool) {
+	switch v := infoValue(err, key).(type) {
+	case time.Duration:
+		return v, true
+	case string:
+		d, perr := time.ParseDuration(v)
+		return d, perr == nil
+	}
+	return 0, false
+}
+
+// infoValue returns the value of an Info key of "err",
+// unwrapping a Secret.
+func infoValue(err error, key string) interface{} {
+	value, _ := InfoValue(err, key)
+	if r, secret := value.(Redacted); secret {
+		return r.value
+	}
+	return value
+}
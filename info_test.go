@@ -0,0 +1,150 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"encoding/json"
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"io"
+	"math"
+	"time"
+)
+
+func (t *TestSuite) TestInfoOrder(c *gc.C) {
+	info := ErrInfo{"b": 2, "a": "x", "d": nil, "c": []int{1}}
+	c.Check(info.Keys(), gc.DeepEquals, []string{"a", "b", "c", "d"})
+	c.Check(info.String(), gc.Equals, "map[a:x b:2 c:[1] d:<nil>]")
+	c.Check(ErrInfo{}.String(), gc.Equals, "map[]")
+
+	err := New(0, "x", 1, "z", 1, "y", 2, "x", 3, WithStackDepth(0))
+	for i := 0; i < 10; i++ {
+		c.Check(err.Message(), gc.Equals, "Domain missing: [x:1] map[x:3 y:2 z:1]")
+		data, jerr := json.Marshal(err)
+		c.Assert(jerr, gc.IsNil)
+		c.Check(string(data), gc.Equals,
+			`{"Version":1,"Domain":"x","Code":1,"Info":{"x":3,"y":2,"z":1}}`)
+	}
+}
+
+func (t *TestSuite) TestIsReserved(c *gc.C) {
+	for _, key := range []string{"_err", "_msg", "_note"} {
+		c.Check(IsReserved(key), gc.Equals, true, gc.Commentf(key))
+	}
+	c.Check(IsReserved("name"), gc.Equals, false)
+	c.Check(IsReserved("_other"), gc.Equals, false)
+}
+
+func (t *TestSuite) TestWith(c *gc.C) {
+	err := NewError(EMyErrorArgs, "name", "read")
+	c.Check(err.With("retry", 3), gc.Equals, err)
+	c.Check(err.WithInfo(map[string]interface{}{"name": "write", "id": 7}), gc.Equals, err)
+	c.Check(err.Info, gc.DeepEquals, ErrInfo{"name": "write", "retry": 3, "id": 7})
+	c.Check(err.Message(), gc.Equals, "The write failed")
+
+	err = &Error{Domain: "x", Code: 1}
+	c.Check(err.With("id", 7).Info, gc.DeepEquals, ErrInfo{"id": 7})
+}
+
+func (t *TestSuite) TestInfoValue(c *gc.C) {
+	inner := NewError(EMyErrorArgs, "name", "inner", "request_id", "r-1")
+	outer := NewError(EMyErrorArgs, "name", "outer")
+	Chain(inner, outer)
+
+	value, ok := InfoValue(fmt.Errorf("handler: %w", outer), "request_id")
+	c.Check(ok, gc.Equals, true)
+	c.Check(value, gc.Equals, "r-1")
+
+	value, ok = InfoValue(outer, "name")
+	c.Check(ok, gc.Equals, true)
+	c.Check(value, gc.Equals, "outer")
+
+	_, ok = InfoValue(outer, "missing")
+	c.Check(ok, gc.Equals, false)
+	_, ok = InfoValue(io.EOF, "name")
+	c.Check(ok, gc.Equals, false)
+}
+
+func (t *TestSuite) TestInfoTyped(c *gc.C) {
+	inner := NewError(EMyErrorArgs, "name", "inner", "timeout", 1500*time.Millisecond)
+	outer := NewError(EMyErrorArgs, "name", "outer", "retry", int64(3), "size", 2.5, "wait", "2s")
+	Chain(inner, outer)
+
+	name, ok := Info[string](outer, "name")
+	c.Check(ok, gc.Equals, true)
+	c.Check(name, gc.Equals, "outer")
+	_, ok = Info[int](outer, "name")
+	c.Check(ok, gc.Equals, false)
+
+	name, ok = InfoString(outer, "name")
+	c.Check(name, gc.Equals, "outer")
+	_, ok = InfoString(outer, "missing")
+	c.Check(ok, gc.Equals, false)
+
+	n, ok := InfoInt(outer, "retry")
+	c.Check(ok, gc.Equals, true)
+	c.Check(n, gc.Equals, 3)
+	_, ok = InfoInt(outer, "size")
+	c.Check(ok, gc.Equals, false)
+
+	d, ok := InfoDuration(outer, "timeout")
+	c.Check(ok, gc.Equals, true)
+	c.Check(d, gc.Equals, 1500*time.Millisecond)
+	d, ok = InfoDuration(outer, "wait")
+	c.Check(ok, gc.Equals, true)
+	c.Check(d, gc.Equals, 2*time.Second)
+	_, ok = InfoDuration(outer, "name")
+	c.Check(ok, gc.Equals, false)
+
+	secret := NewError(EMyErrorArgs, "retry", Secret(uint8(2)), "wait", Secret("1s"))
+	n, ok = InfoInt(secret, "retry")
+	c.Check(ok, gc.Equals, true)
+	c.Check(n, gc.Equals, 2)
+	d, ok = InfoDuration(secret, "wait")
+	c.Check(ok, gc.Equals, true)
+	c.Check(d, gc.Equals, time.Second)
+
+	huge := NewError(EMyErrorArgs, "u", uint64(math.MaxUint64), "f", 1e300, "max", -float64(math.MinInt))
+	_, ok = InfoInt(huge, "u")
+	c.Check(ok, gc.Equals, false)
+	_, ok = InfoInt(huge, "f")
+	c.Check(ok, gc.Equals, false)
+	_, ok = InfoInt(huge, "max")
+	c.Check(ok, gc.Equals, false)
+
+	var decoded Error
+	data, err := json.Marshal(outer)
+	c.Assert(err, gc.IsNil)
+	c.Assert(json.Unmarshal(data, &decoded), gc.IsNil)
+	n, ok = InfoInt(&decoded, "retry")
+	c.Check(ok, gc.Equals, true)
+	c.Check(n, gc.Equals, 3)
+	d, ok = InfoDuration(&decoded, "timeout")
+	c.Check(ok, gc.Equals, false)
+}
+
+func (t *TestSuite) TestGoDomainWithoutErr(c *gc.C) {
+	err := &Error{Domain: "go", Info: ErrInfo{}}
+	c.Check(err.Message(), gc.Equals, "Error: ")
+}
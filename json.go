@@ -0,0 +1,54 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"encoding/json"
+)
+
+// FromJSON reconstructs an error serialized with encoding/json,
+// see Wire.ToError.
+func FromJSON(data []byte) (*Error, error) {
+	var wire Wire
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return nil, err
+	}
+	return wire.ToError()
+}
+
+// MarshalJSON implements json.Marshaler.
+// Errors are serialized in the versioned layout of Wire.
+func (err *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(err.ToWire())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, see FromJSON.
+func (err *Error) UnmarshalJSON(data []byte) error {
+	decoded, derr := FromJSON(data)
+	if derr != nil {
+		return derr
+	}
+	*err = *decoded
+	return nil
+}
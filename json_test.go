@@ -0,0 +1,125 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"encoding/json"
+	gc "github.com/motain/gocheck"
+	"io"
+	"strings"
+)
+
+func (t *TestSuite) TestJSONRoundTrip(c *gc.C) {
+	inner := Wrap(io.EOF)
+	err := Chain(inner, NewError(EMyErrorArgs, "name", "x", "count", 3)).(*Error)
+	err.Causes = []*Error{NewError(EMyError1)}
+	data, jerr := json.Marshal(err)
+	c.Assert(jerr, gc.IsNil)
+
+	decoded, jerr := FromJSON(data)
+	c.Assert(jerr, gc.IsNil)
+	c.Check(decoded.Domain, gc.Equals, "ergo")
+	c.Check(decoded.Code, gc.Equals, EMyErrorArgs)
+	c.Check(decoded.Info, gc.DeepEquals, ErrInfo{"name": "x", "count": 3.0})
+	c.Check(decoded.Message(), gc.Equals, "The x failed")
+	c.Check(decoded.Stack.Frames(), gc.HasLen, len(err.Stack.Frames()))
+	c.Check(decoded.Inner.Message(), gc.Equals, "Error: EOF")
+	c.Check(decoded.Causes, gc.HasLen, 1)
+	c.Check(decoded.Error(), gc.Equals, err.Error())
+
+	var target Error
+	c.Assert(json.Unmarshal(data, &target), gc.IsNil)
+	c.Check(target.Error(), gc.Equals, err.Error())
+
+	var wrapper struct{ Err *Error }
+	c.Assert(json.Unmarshal([]byte(`{"Err":`+string(data)+`}`), &wrapper), gc.IsNil)
+	c.Check(wrapper.Err.Error(), gc.Equals, err.Error())
+}
+
+func (t *TestSuite) TestJSONValidation(c *gc.C) {
+	_, err := FromJSON([]byte(`{"Code":1}`))
+	c.Check(err, gc.ErrorMatches, "ergo: error has no domain")
+
+	_, err = FromJSON([]byte(`{"Domain":"go","Info":{"_err":1}}`))
+	c.Check(err, gc.ErrorMatches, "ergo: \\[go:0\\] has no message")
+
+	_, err = FromJSON([]byte(`{"Domain":"ergo","Code":99}`))
+	c.Check(err, gc.ErrorMatches, "ergo: \\[ergo:99\\] is not defined")
+
+	_, err = FromJSON([]byte(`{"Domain":"ergo","Inner":{"Domain":""}}`))
+	c.Check(err, gc.ErrorMatches, "ergo: error has no domain")
+
+	_, err = FromJSON([]byte(`{"Domain":"ergo","Causes":[null]}`))
+	c.Check(err, gc.ErrorMatches, "ergo: \\[ergo:0\\] has a null cause")
+
+	_, err = FromJSON([]byte(`{"Domain":`))
+	c.Check(err, gc.NotNil)
+
+	deep := strings.Repeat(`{"Domain":"x","Inner":`, MaxDecodeDepth+1) + `{"Domain":"x"}` +
+		strings.Repeat("}", MaxDecodeDepth+1)
+	_, err = FromJSON([]byte(deep))
+	c.Check(err, gc.ErrorMatches, "ergo: chain exceeds 100 links")
+
+	cyclic := &Error{Domain: "x"}
+	cyclic.Causes = []*Error{cyclic}
+	encoded, err := json.Marshal(&Error{Domain: "x", Inner: cyclic})
+	c.Assert(err, gc.IsNil)
+	_, err = FromJSON(encoded)
+	c.Check(err, gc.IsNil)
+
+	decoded, err := FromJSON([]byte(`{"Domain":"remote","Code":7}`))
+	c.Assert(err, gc.IsNil)
+	c.Check(decoded.Info, gc.NotNil)
+	c.Check(decoded.Message(), gc.Equals, "Domain missing: [remote:7] map[]")
+}
+
+func (t *TestSuite) TestJSONSchema(c *gc.C) {
+	inner := &Error{Domain: "go", Info: ErrInfo{"_err": "EOF"}, Context: "main.cpp:42"}
+	err := &Error{
+		Domain: "ergo",
+		Code:   EMyErrorArgs,
+		Info: ErrInfo{
+			"name":  "x",
+			"cause": io.EOF,
+			"fn":    func() {},
+		},
+		Stack:  NewStack([]Frame{{PC: 1, Function: "main.main", File: "main.go", Line: 7}}),
+		Inner:  inner,
+		Causes: []*Error{{Domain: "ergo", Code: EMyError0}},
+	}
+	data, jerr := json.Marshal(err)
+	c.Assert(jerr, gc.IsNil)
+	c.Check(string(data), gc.Matches, `{"Version":1,"Domain":"ergo","Code":2,`+
+		`"Info":{"cause":"EOF","fn":"0x[0-9a-f]+","name":"x"},`+
+		`"Stack":\[{"Function":"main.main","File":"main.go","Line":7}\],`+
+		`"Inner":{"Domain":"go","Code":0,"Info":{"_err":"EOF"},"Context":"main.cpp:42"},`+
+		`"Causes":\[{"Domain":"ergo","Code":0}\]}`)
+
+	_, jerr = FromJSON([]byte(`{"Version":2,"Domain":"ergo"}`))
+	c.Check(jerr, gc.ErrorMatches, "ergo: unsupported schema version 2")
+
+	decoded, jerr := FromJSON([]byte(`{"Domain":"ergo","Code":1}`))
+	c.Assert(jerr, gc.IsNil)
+	c.Check(decoded.Message(), gc.Equals, "My error 1")
+}
@@ -0,0 +1,169 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template/parse"
+)
+
+// The Info keys given to Domain with WithKeys, by domain.
+var domainKeys sync.Map
+
+// WithKeys declares the Info keys used by the message format of each
+// code. Domain then panics if a format refers to an undeclared key or
+// leaves a declared key unused, catching typos such as {{.nmae}} at
+// startup rather than rendering "<no value>" in production.
+// Translations are checked for undeclared keys as well.
+// Codes without a declaration are not checked.
+func WithKeys(keys map[ErrCode][]string) DomainOption {
+	return func(opts *domainOptions) {
+		opts.keys = keys
+	}
+}
+
+// declaredKeys returns the keys declared for a domain, if any.
+func declaredKeys(name string) map[ErrCode][]string {
+	keys, _ := domainKeys.Load(name)
+	declared, _ := keys.(map[ErrCode][]string)
+	return declared
+}
+
+// MessageKeys returns the Info keys a message format refers to, sorted.
+// Functions are not checked, so that tools can inspect formats
+// using functions added by the application.
+func MessageKeys(format string) ([]string, error) {
+	tree := parse.New("format")
+	tree.Mode = parse.SkipFuncCheck
+	if _, err := tree.Parse(format, "", "", make(map[string]*parse.Tree)); err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	collectKeys(tree.Root, used, true)
+	keys := make([]string, 0, len(used))
+	for key := range used {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// checkKeys checks message formats against declared keys.
+// Unused keys are only reported if "strict".
+func checkKeys(name string, tmpls templates, keys map[ErrCode][]string, strict bool) error {
+	codes := make([]int, 0, len(keys))
+	for code := range keys {
+		codes = append(codes, int(code))
+	}
+	sort.Ints(codes)
+	for _, c := range codes {
+		code := ErrCode(c)
+		tmpl, ok := tmpls[code]
+		if !ok {
+			if strict {
+				return fmt.Errorf("ergo: [%v:%d] has keys but no message", name, code)
+			}
+			continue
+		}
+		used := make(map[string]bool)
+		collectKeys(tmpl.Tree.Root, used, true)
+		declared := make(map[string]bool)
+		for _, key := range keys[code] {
+			declared[key] = true
+		}
+		var undeclared, unused []string
+		for key := range used {
+			if !declared[key] {
+				undeclared = append(undeclared, key)
+			}
+		}
+		for key := range declared {
+			if !used[key] && strict {
+				unused = append(unused, key)
+			}
+		}
+		sort.Strings(undeclared)
+		sort.Strings(unused)
+		if len(undeclared) != 0 {
+			return fmt.Errorf("ergo: [%v:%d] refers to undeclared keys: %v",
+				name, code, strings.Join(undeclared, ", "))
+		}
+		if len(unused) != 0 {
+			return fmt.Errorf("ergo: [%v:%d] does not use declared keys: %v",
+				name, code, strings.Join(unused, ", "))
+		}
+	}
+	return nil
+}
+
+// collectKeys collects the Info keys a template refers to: the fields
+// of $ and, where "dot" is Info, the fields of dot.
+// Within range and with, dot is not Info.
+func collectKeys(node parse.Node, keys map[string]bool, dot bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			for _, child := range n.Nodes {
+				collectKeys(child, keys, dot)
+			}
+		}
+	case *parse.ActionNode:
+		collectKeys(n.Pipe, keys, dot)
+	case *parse.IfNode:
+		collectKeys(n.Pipe, keys, dot)
+		collectKeys(n.List, keys, dot)
+		collectKeys(n.ElseList, keys, dot)
+	case *parse.RangeNode:
+		collectKeys(n.Pipe, keys, dot)
+		collectKeys(n.List, keys, false)
+		collectKeys(n.ElseList, keys, dot)
+	case *parse.WithNode:
+		collectKeys(n.Pipe, keys, dot)
+		collectKeys(n.List, keys, false)
+		collectKeys(n.ElseList, keys, dot)
+	case *parse.PipeNode:
+		if n != nil {
+			for _, cmd := range n.Cmds {
+				collectKeys(cmd, keys, dot)
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectKeys(arg, keys, dot)
+		}
+	case *parse.ChainNode:
+		collectKeys(n.Node, keys, dot)
+	case *parse.FieldNode:
+		if dot {
+			keys[n.Ident[0]] = true
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			keys[n.Ident[1]] = true
+		}
+	}
+}
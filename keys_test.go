@@ -0,0 +1,77 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	gc "github.com/motain/gocheck"
+	"strings"
+)
+
+func (t *TestSuite) TestWithKeys(c *gc.C) {
+	Domain("keys", DomainMap{
+		0: "The {{.name}} failed after {{.count}} {{plural .count \"try\" \"tries\"}}",
+		1: "{{with .user}}{{.Name}} cannot access {{$.path}}{{end}}",
+		2: "{{range .items}}{{.}}, {{end}}{{if .more}}and more{{end}}",
+		3: "Unchecked {{.anything}}",
+	}, WithKeys(map[ErrCode][]string{
+		0: {"name", "count"},
+		1: {"user", "path"},
+		2: {"items", "more"},
+	}))
+	c.Check(New(0, "keys", 0, "name", "x", "count", 2).Message(), gc.Equals, "The x failed after 2 tries")
+
+	for _, test := range []struct {
+		domain DomainMap
+		err    string
+	}{
+		{DomainMap{0: "The {{.nmae}} failed"}, `ergo: \[keys2:0\] refers to undeclared keys: nmae`},
+		{DomainMap{0: "Failed"}, `ergo: \[keys2:0\] does not use declared keys: name`},
+		{DomainMap{1: "Failed"}, `ergo: \[keys2:0\] has keys but no message`},
+	} {
+		c.Check(func() {
+			Domain("keys2", test.domain, WithKeys(map[ErrCode][]string{0: {"name"}}))
+		}, gc.PanicMatches, test.err)
+	}
+
+	// Translations may leave keys out, but not refer to undeclared ones.
+	DomainLocale("keys", "de", DomainMap{0: "{{.name}} ist fehlgeschlagen"})
+	c.Check(func() { DomainLocale("keys", "fr", DomainMap{0: "{{.nom}} a échoué"}) },
+		gc.PanicMatches, `ergo: \[keys:0\] refers to undeclared keys: nom`)
+	_, err := LoadDomainReader(strings.NewReader(
+		`{"domain": "keys", "locale": "fr", "codes": [{"code": 2, "message": "{{.item}}"}]}`), "json")
+	c.Check(err, gc.ErrorMatches, `ergo: \[keys:2\] refers to undeclared keys: item`)
+	c.Check(UpdateDomain("keys", DomainMap{0: "{{.name}}"}), gc.ErrorMatches,
+		`ergo: \[keys:0\] does not use declared keys: count`)
+}
+
+func (t *TestSuite) TestMessageKeys(c *gc.C) {
+	keys, err := MessageKeys(`{{.user}} {{custom .size}} {{range .items}}{{.ignored}}{{$.total}}{{end}}`)
+	c.Check(err, gc.IsNil)
+	c.Check(keys, gc.DeepEquals, []string{"items", "size", "total", "user"})
+	keys, err = MessageKeys("Plain")
+	c.Check(err, gc.IsNil)
+	c.Check(keys, gc.HasLen, 0)
+	_, err = MessageKeys("{{.x")
+	c.Check(err, gc.ErrorMatches, ".*unclosed action")
+}
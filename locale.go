@@ -0,0 +1,151 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LocaleFunc formats the message of an error in a locale,
+// reporting false if it has no translation for it.
+// The locale is lowercase, with hyphens between subtags, such as "de-ch".
+type LocaleFunc func(err *Error, locale string) (string, bool)
+
+// localeKey identifies the translations of a domain to a locale.
+type localeKey struct {
+	domain string
+	locale string
+}
+
+var (
+	defaultLocale atomic.Value
+
+	// The translations of DomainLocale, by localeKey.
+	translations sync.Map
+
+	// The LocaleFuncs of each domain, replaced on every change.
+	localizersMu sync.Mutex
+	localizers   sync.Map
+)
+
+// SetDefaultLocale sets the locale of Message().
+// Use "" to revert to the message formats given to Domain.
+func SetDefaultLocale(locale string) {
+	defaultLocale.Store(normalizeLocale(locale))
+}
+
+// DefaultLocale returns the locale set with SetDefaultLocale.
+func DefaultLocale() string {
+	locale, _ := defaultLocale.Load().(string)
+	return locale
+}
+
+// DomainLocale defines translated message formats of a domain,
+// whose plural and ordinal functions follow the rules of "locale".
+// Codes missing from "domain" fall back as described by MessageIn.
+// It panics if a format refers to keys not declared with WithKeys,
+// or if a code lies outside of the range given to WithCodeRange.
+func DomainLocale(name, locale string, domain DomainMap) {
+	if err := checkRange(name, domain, reservedRange(name)); err != nil {
+		panic(err)
+	}
+	key := localeKey{domain: name, locale: normalizeLocale(locale)}
+	tmpls := compile(name, funcsFor(name, key.locale), domain)
+	if err := checkKeys(name, tmpls, declaredKeys(name), false); err != nil {
+		panic(err)
+	}
+	translations.Store(key, tmpls)
+}
+
+// DomainLocaleFunc adds a backend translating the messages of a domain,
+// consulted after the translations of DomainLocale.
+func DomainLocaleFunc(name string, fn LocaleFunc) {
+	localizersMu.Lock()
+	defer localizersMu.Unlock()
+	fns, _ := localizers.Load(name)
+	list, _ := fns.([]LocaleFunc)
+	localizers.Store(name, append(list[:len(list):len(list)], fn))
+}
+
+// Translation returns the message translated to "locale" or its parents,
+// reporting false if there is none. Unlike MessageIn, it neither falls
+// back to the default locale nor to the message formats given to Domain,
+// though it does fall back to parent domains.
+func (err *Error) Translation(locale string) (string, bool) {
+	if _, ok := err.Info["_msg"].(string); ok {
+		return "", false
+	}
+	for name := err.Domain; name != ""; name = parentDomain(name) {
+		if msg, ok := err.localize(name, locale); ok {
+			return msg, true
+		}
+		if def, ok := defined.Load(name); ok {
+			if _, ok := def.(*definition).tmpls[err.Code]; ok {
+				break
+			}
+		} else if _, ok := domains.Load(name); ok {
+			break
+		}
+	}
+	return "", false
+}
+
+// localize formats the message of an error with the translations
+// of a domain in "locale" or its parents.
+func (err *Error) localize(domain, locale string) (string, bool) {
+	locale = normalizeLocale(locale)
+	fns, _ := localizers.Load(domain)
+	list, _ := fns.([]LocaleFunc)
+	for locale != "" {
+		key := localeKey{domain: domain, locale: locale}
+		if tmpls, ok := translations.Load(key); ok {
+			if msg, ok := tmpls.(templates).format(err); ok {
+				return msg, true
+			}
+		}
+		for _, fn := range list {
+			if msg, ok := fn(err, locale); ok {
+				return msg, true
+			}
+		}
+		locale = parentLocale(locale)
+	}
+	return "", false
+}
+
+// normalizeLocale lowercases a language tag and separates its subtags
+// with hyphens, so that "de_DE" and "de-de" both become "de-de".
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(strings.TrimSpace(locale), "_", "-", -1))
+}
+
+// parentLocale removes the last subtag of a normalized locale.
+func parentLocale(locale string) string {
+	if sep := strings.LastIndexByte(locale, '-'); sep >= 0 {
+		return locale[:sep]
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	gc "github.com/motain/gocheck"
+)
+
+func (t *TestSuite) TestMessageIn(c *gc.C) {
+	Domain("locale", DomainMap{
+		0: "The {{.name}} was not found",
+		1: "Access denied",
+	})
+	DomainLocale("locale", "de", DomainMap{
+		0: "{{.name}} wurde nicht gefunden",
+		1: "Zugriff verweigert",
+	})
+	DomainLocale("locale", "de-CH", DomainMap{
+		1: "Zugriff verweigert (CH)",
+	})
+	DomainLocale("locale", "fr", DomainMap{
+		0: "{{.name}} introuvable",
+	})
+	DomainLocaleFunc("locale", func(err *Error, locale string) (string, bool) {
+		if locale == "x-pirate" {
+			return "Arr", true
+		}
+		return "", false
+	})
+
+	err := New(0, "locale", 0, "name", "file")
+	c.Check(err.MessageIn("de"), gc.Equals, "file wurde nicht gefunden")
+	c.Check(err.MessageIn("de_CH"), gc.Equals, "file wurde nicht gefunden")
+	c.Check(err.MessageIn("fr-FR"), gc.Equals, "file introuvable")
+	c.Check(err.MessageIn("ja"), gc.Equals, "The file was not found")
+	c.Check(err.MessageIn("x-pirate"), gc.Equals, "Arr")
+	c.Check(err.Message(), gc.Equals, "The file was not found")
+
+	denied := New(0, "locale", 1)
+	c.Check(denied.MessageIn("DE-ch"), gc.Equals, "Zugriff verweigert (CH)")
+	c.Check(denied.MessageIn("fr"), gc.Equals, "Access denied")
+
+	SetDefaultLocale("de_DE")
+	defer SetDefaultLocale("")
+	c.Check(DefaultLocale(), gc.Equals, "de-de")
+	c.Check(err.Message(), gc.Equals, "file wurde nicht gefunden")
+	c.Check(denied.MessageIn("fr"), gc.Equals, "Zugriff verweigert")
+	c.Check(err.MessageIn("fr"), gc.Equals, "file introuvable")
+
+	msg, ok := denied.Translation("de-AT")
+	c.Check(msg, gc.Equals, "Zugriff verweigert")
+	c.Check(ok, gc.Equals, true)
+	_, ok = denied.Translation("fr")
+	c.Check(ok, gc.Equals, false)
+
+	restored := &Error{Domain: "locale", Info: ErrInfo{"_msg": "Preformatted"}}
+	c.Check(restored.MessageIn("de"), gc.Equals, "Preformatted")
+	_, ok = restored.Translation("de")
+	c.Check(ok, gc.Equals, false)
+}
@@ -0,0 +1,93 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package logrusergo provides a logrus hook for ergo errors.
+package logrusergo
+
+import (
+	"github.com/flaub/ergo"
+	"github.com/sirupsen/logrus"
+)
+
+// Hook replaces an ergo error attached with WithError by its fields:
+// "error" becomes Message(), and "error.domain", "error.code",
+// "error.info.*" and "error.context" are added.
+// The context holds the stack trace or context of the error.
+type Hook struct {
+	// ContextLevel is the least severe level whose entries
+	// include the context. Use logrus.PanicLevel to include it
+	// only when panicking.
+	ContextLevel logrus.Level
+
+	// MaxContext truncates the context to a number of bytes.
+	// A value of 0 means no limit.
+	MaxContext int
+
+	// NoContext omits the context at every level.
+	NoContext bool
+}
+
+// NewHook creates a hook which includes the context
+// for entries at the error level or more severe.
+func NewHook() *Hook {
+	return &Hook{ContextLevel: logrus.ErrorLevel}
+}
+
+// Levels implements logrus.Hook.
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	value, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok {
+		return nil
+	}
+	err, ok := ergo.AsError(value)
+	if !ok {
+		return nil
+	}
+	prefix := logrus.ErrorKey + "."
+	entry.Data[logrus.ErrorKey] = err.Message()
+	entry.Data[prefix+"domain"] = err.Domain
+	entry.Data[prefix+"code"] = int(err.Code)
+	for key, value := range err.Info {
+		if !ergo.IsReserved(key) {
+			entry.Data[prefix+"info."+key] = ergo.Redact(key, value)
+		}
+	}
+	if !h.NoContext && entry.Level <= h.ContextLevel {
+		context := err.Context
+		if err.Stack != nil {
+			context = err.Stack.String()
+		}
+		if h.MaxContext > 0 && len(context) > h.MaxContext {
+			context = context[:h.MaxContext] + "..."
+		}
+		if context != "" {
+			entry.Data[prefix+"context"] = context
+		}
+	}
+	return nil
+}
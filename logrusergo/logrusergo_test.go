@@ -0,0 +1,111 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package logrusergo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"github.com/sirupsen/logrus"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("logrusergo", ergo.DomainMap{
+		ENotFound: "The {{.name}} was not found",
+	})
+}
+
+func logJSON(c *gc.C, hook *Hook, fn func(*logrus.Logger)) map[string]interface{} {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.Out = &buf
+	logger.Formatter = &logrus.JSONFormatter{DisableTimestamp: true}
+	logger.AddHook(hook)
+	fn(logger)
+	var entry map[string]interface{}
+	c.Assert(json.Unmarshal(buf.Bytes(), &entry), gc.IsNil)
+	return entry
+}
+
+func (t *TestSuite) TestHook(c *gc.C) {
+	err := &ergo.Error{
+		Domain:  "logrusergo",
+		Code:    ENotFound,
+		Info:    ergo.ErrInfo{"name": "user"},
+		Context: "main.cpp:42",
+	}
+
+	entry := logJSON(c, NewHook(), func(l *logrus.Logger) {
+		l.WithError(fmt.Errorf("lookup: %w", err)).Error("failed")
+	})
+	c.Check(entry, gc.DeepEquals, map[string]interface{}{
+		"level":           "error",
+		"msg":             "failed",
+		"error":           "The user was not found",
+		"error.domain":    "logrusergo",
+		"error.code":      float64(ENotFound),
+		"error.info.name": "user",
+		"error.context":   "main.cpp:42",
+	})
+
+	entry = logJSON(c, NewHook(), func(l *logrus.Logger) {
+		l.WithError(err).Warn("failed")
+	})
+	_, ok := entry["error.context"]
+	c.Check(ok, gc.Equals, false)
+
+	entry = logJSON(c, &Hook{ContextLevel: logrus.DebugLevel, MaxContext: 4}, func(l *logrus.Logger) {
+		l.WithError(err).Warn("failed")
+	})
+	c.Check(entry["error.context"], gc.Equals, "main...")
+
+	entry = logJSON(c, &Hook{ContextLevel: logrus.DebugLevel, NoContext: true}, func(l *logrus.Logger) {
+		l.WithError(err).Error("failed")
+	})
+	_, ok = entry["error.context"]
+	c.Check(ok, gc.Equals, false)
+
+	entry = logJSON(c, NewHook(), func(l *logrus.Logger) {
+		l.WithError(fmt.Errorf("plain")).Error("failed")
+	})
+	c.Check(entry["error"], gc.Equals, "plain")
+	_, ok = entry["error.domain"]
+	c.Check(ok, gc.Equals, false)
+}
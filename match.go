@@ -0,0 +1,136 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"errors"
+)
+
+// Match reports whether the first Error found by unwrapping "err"
+// has a domain and code. Unlike errors.Is with a Target, which matches
+// any link of the chain, only that outermost Error is compared:
+//
+//	if ergo.Match(err, "billing", ENotFound) {
+//		w.WriteHeader(http.StatusNotFound)
+//	}
+func Match(err error, domain string, code ErrCode) bool {
+	var ergo *Error
+	return errors.As(err, &ergo) && ergo.Domain == domain && ergo.Code == code
+}
+
+// Matcher is a predicate on the links of a chain, see Any and First.
+// Matchers compose with AllOf, AnyOf and Not:
+//
+//	if ergo.Any(err, ergo.AllOf(ergo.InDomain("db"), ergo.WithCode(ETimeout))) {
+//		...
+//	}
+type Matcher func(*Error) bool
+
+// InDomain matches errors of a domain.
+func InDomain(domain string) Matcher {
+	return func(err *Error) bool {
+		return err.Domain == domain
+	}
+}
+
+// WithCode matches errors with a code, in any domain.
+func WithCode(code ErrCode) Matcher {
+	return func(err *Error) bool {
+		return err.Code == code
+	}
+}
+
+// HasInfo matches errors with an Info key.
+func HasInfo(key string) Matcher {
+	return func(err *Error) bool {
+		_, ok := err.Info[key]
+		return ok
+	}
+}
+
+// AllOf matches errors matched by every one of "matchers".
+func AllOf(matchers ...Matcher) Matcher {
+	return func(err *Error) bool {
+		for _, m := range matchers {
+			if !m(err) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyOf matches errors matched by at least one of "matchers".
+func AnyOf(matchers ...Matcher) Matcher {
+	return func(err *Error) bool {
+		for _, m := range matchers {
+			if m(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches errors not matched by "m".
+func Not(m Matcher) Matcher {
+	return func(err *Error) bool {
+		return !m(err)
+	}
+}
+
+// Any reports whether any link of the chain of "err" matches,
+// see Walk.
+func Any(err error, m Matcher) bool {
+	return First(err, m) != nil
+}
+
+// First returns the first link of the chain of "err" which matches,
+// outermost first, or nil. See Walk.
+func First(err error, m Matcher) *Error {
+	var found *Error
+	Walk(err, func(link *Error) bool {
+		if m(link) {
+			found = link
+		}
+		return found == nil
+	})
+	return found
+}
+
+// CodesIn returns the domain and code of every link
+// of the chain of "err", outermost first, without duplicates.
+func CodesIn(err error) []Target {
+	var codes []Target
+	seen := make(map[Target]bool)
+	Walk(err, func(link *Error) bool {
+		code := Target{Domain: link.Domain, Code: link.Code}
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+		return true
+	})
+	return codes
+}
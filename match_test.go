@@ -0,0 +1,71 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"io"
+)
+
+func (t *TestSuite) TestMatchers(c *gc.C) {
+	root := Wrap(io.EOF)
+	middle := NewError(EMyErrorArgs, "name", "read", "retry", 2)
+	outer := NewError(EMyError1)
+	Chain(root, middle)
+	Chain(middle, outer)
+	err := fmt.Errorf("handler: %w", outer)
+
+	c.Check(Any(err, InDomain("go")), gc.Equals, true)
+	c.Check(Any(err, InDomain("db")), gc.Equals, false)
+	c.Check(First(err, WithCode(EMyErrorArgs)), gc.Equals, middle)
+	c.Check(First(err, HasInfo("retry")), gc.Equals, middle)
+	c.Check(First(err, AllOf(InDomain("ergo"), Not(WithCode(EMyError1)))), gc.Equals, middle)
+	c.Check(First(err, AnyOf(InDomain("db"), WithCode(0))), gc.Equals, root)
+	c.Check(First(err, AllOf(InDomain("go"), HasInfo("retry"))), gc.IsNil)
+	c.Check(First(io.EOF, InDomain("go")), gc.IsNil)
+	c.Check(Any(nil, InDomain("go")), gc.Equals, false)
+}
+
+func (t *TestSuite) TestCodesIn(c *gc.C) {
+	outer := NewError(EMyError1)
+	Chain(Join(NewError(EMyError0), NewError(EMyError1)), outer)
+	c.Check(CodesIn(outer), gc.DeepEquals, []Target{
+		{"ergo", EMyError1}, {"go", 0}, {"ergo", EMyError0},
+	})
+	c.Check(CodesIn(io.EOF), gc.IsNil)
+}
+
+func (t *TestSuite) TestMatch(c *gc.C) {
+	inner := NewError(EMyError0)
+	outer := NewError(EMyError1)
+	Chain(inner, outer)
+	err := fmt.Errorf("handler: %w", outer)
+	c.Check(Match(err, "ergo", EMyError1), gc.Equals, true)
+	c.Check(Match(outer, "ergo", EMyError1), gc.Equals, true)
+	c.Check(Match(err, "ergo", EMyError0), gc.Equals, false)
+	c.Check(Match(err, "go", EMyError1), gc.Equals, false)
+	c.Check(Match(io.EOF, "go", 0), gc.Equals, false)
+	c.Check(Match(nil, "go", 0), gc.Equals, false)
+}
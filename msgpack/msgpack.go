@@ -0,0 +1,77 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package msgpack transports ergo errors as MessagePack,
+// for example over msgpack-RPC.
+package msgpack
+
+import (
+	"bytes"
+	"github.com/flaub/ergo"
+	"github.com/ugorji/go/codec"
+	"io"
+	"reflect"
+)
+
+// NewHandle returns a codec handle configured for ergo errors:
+// strings and byte slices keep distinct types, and nested maps in Info
+// are decoded as map[string]interface{}.
+func NewHandle() *codec.MsgpackHandle {
+	h := new(codec.MsgpackHandle)
+	h.WriteExt = true
+	h.SignedInteger = true
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	return h
+}
+
+var (
+	handle = NewHandle()
+)
+
+// Encode writes an error to "w" in the layout of ergo.Wire.
+func Encode(w io.Writer, err *ergo.Error) error {
+	return codec.NewEncoder(w, handle).Encode(err.ToWire())
+}
+
+// Decode reads an error written by Encode, see ergo.Wire.ToError.
+func Decode(r io.Reader) (*ergo.Error, error) {
+	var wire ergo.Wire
+	if err := codec.NewDecoder(r, handle).Decode(&wire); err != nil {
+		return nil, err
+	}
+	return wire.ToError()
+}
+
+// Marshal returns the MessagePack encoding of an error.
+func Marshal(err *ergo.Error) ([]byte, error) {
+	var buf bytes.Buffer
+	if merr := Encode(&buf, err); merr != nil {
+		return nil, merr
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes an error from its MessagePack encoding.
+func Unmarshal(data []byte) (*ergo.Error, error) {
+	return Decode(bytes.NewReader(data))
+}
@@ -0,0 +1,91 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package msgpack
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"io"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("msgpack", ergo.DomainMap{
+		0: "Outer {{.name}}",
+		1: "Inner {{.count}}",
+	})
+}
+
+func (t *TestSuite) TestRoundTrip(c *gc.C) {
+	inner := ergo.New(0, "msgpack", 1, "count", 3, "ratio", 0.5, "ok", true)
+	middle := ergo.Chain(inner, ergo.Wrap(io.EOF))
+	err := ergo.Chain(middle, ergo.New(0, "msgpack", 0,
+		"name", "x",
+		"list", []interface{}{"a", int64(-1)},
+		"nested", map[string]interface{}{"k": "v"},
+		"raw", []byte{1, 2},
+	)).(*ergo.Error)
+	err.Causes = []*ergo.Error{ergo.New(0, "msgpack", 1, "count", 4)}
+
+	data, merr := Marshal(err)
+	c.Assert(merr, gc.IsNil)
+	decoded, merr := Unmarshal(data)
+	c.Assert(merr, gc.IsNil)
+
+	c.Check(decoded.Error(), gc.Equals, err.Error())
+	c.Check(decoded.Info, gc.DeepEquals, ergo.ErrInfo{
+		"name":   "x",
+		"list":   []interface{}{"a", int64(-1)},
+		"nested": map[string]interface{}{"k": "v"},
+		"raw":    []byte{1, 2},
+	})
+	c.Check(decoded.Inner.Message(), gc.Equals, "Error: EOF")
+	c.Check(decoded.Inner.Inner.Info, gc.DeepEquals, ergo.ErrInfo{
+		"count": int64(3),
+		"ratio": 0.5,
+		"ok":    true,
+	})
+	c.Check(decoded.Causes, gc.HasLen, 1)
+	c.Check(decoded.Causes[0].Message(), gc.Equals, "Inner 4")
+	c.Check(decoded.Stack.Frames()[0].Function, gc.Matches, ".*TestRoundTrip$")
+}
+
+func (t *TestSuite) TestValidation(c *gc.C) {
+	data, err := Marshal(&ergo.Error{Domain: "msgpack", Code: 9})
+	c.Assert(err, gc.IsNil)
+	_, err = Unmarshal(data)
+	c.Check(err, gc.ErrorMatches, "ergo: \\[msgpack:9\\] is not defined")
+
+	_, err = Unmarshal([]byte{0xc1})
+	c.Check(err, gc.NotNil)
+}
@@ -0,0 +1,229 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// DefaultStackDepth is the default maximum number of stack frames
+	// captured for each error.
+	DefaultStackDepth = 50
+
+	// Unbounded can be used as a stack depth to capture complete stacks.
+	Unbounded = -1
+)
+
+// StackMode selects the context recorded when an error is created.
+type StackMode int32
+
+const (
+	// StackDefault defers to the global mode when used for a domain.
+	StackDefault StackMode = iota
+
+	// StackFull records a structured stack trace in Stack.
+	StackFull
+
+	// StackCaller records only the immediate caller in Context,
+	// as "file:line function". This is far cheaper than a full trace.
+	StackCaller
+)
+
+var (
+	maxStackDepth int32 = DefaultStackDepth
+	trimPaths     int32
+	oneline       int32
+	globalMode    = int32(StackFull)
+	domainModes   sync.Map
+	sampleRate    int64
+	sampleCounts  sync.Map
+)
+
+// Option customizes the creation of a single error, see NewE.
+// Options may also be passed to New and Wrap along with the "args" pairs.
+type Option func(*options)
+
+type options struct {
+	depth    int
+	severity Severity
+	skip     int
+	info     []interface{}
+	inner    error
+	wrapped  error
+	causes   []*Error
+	panicked interface{}
+	pcs      []uintptr
+}
+
+func defaultOptions() options {
+	return options{
+		depth: int(atomic.LoadInt32(&maxStackDepth)),
+	}
+}
+
+// SetMaxStackDepth sets the maximum number of stack frames
+// captured for each error. A depth of 0 disables stack capture,
+// while Unbounded captures complete stacks.
+func SetMaxStackDepth(n int) {
+	atomic.StoreInt32(&maxStackDepth, int32(n))
+}
+
+// WithStackDepth overrides the maximum stack depth for a single error.
+func WithStackDepth(n int) Option {
+	return func(opts *options) {
+		opts.depth = n
+	}
+}
+
+// WithoutStack disables stack capture for a single error.
+func WithoutStack() Option {
+	return WithStackDepth(0)
+}
+
+// WithSkip skips "n" more stack frames, so that helpers creating errors
+// can attribute them to their caller.
+func WithSkip(n int) Option {
+	return func(opts *options) {
+		opts.skip += n
+	}
+}
+
+// WithInfo adds a key and value to the Info of the error.
+func WithInfo(key string, value interface{}) Option {
+	return func(opts *options) {
+		opts.info = append(opts.info, key, value)
+	}
+}
+
+// WithInner sets the inner error, see Chain.
+func WithInner(err error) Option {
+	return func(opts *options) {
+		opts.inner = err
+	}
+}
+
+// WithWrapped keeps "err" as the original error, as Wrap does, without
+// adding a link to the chain. Unwrap returns it if there is no inner error;
+// Is and As search it either way.
+func WithWrapped(err error) Option {
+	return func(opts *options) {
+		opts.wrapped = err
+	}
+}
+
+// withCauses sets the additional causes, see Join.
+func withCauses(causes []*Error) Option {
+	return func(opts *options) {
+		opts.causes = causes
+	}
+}
+
+// panicking records a value recovered from a panic,
+// along with the stack of the panicking goroutine.
+func panicking(x interface{}, pcs []uintptr) Option {
+	return func(opts *options) {
+		opts.panicked = x
+		opts.pcs = pcs
+	}
+}
+
+// SetTrimPaths controls whether stack frames are rendered and serialized
+// with package-relative paths (e.g. "mypkg/server.go") instead of
+// the absolute paths of the build machine.
+func SetTrimPaths(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&trimPaths, v)
+}
+
+func trimPathsEnabled() bool {
+	return atomic.LoadInt32(&trimPaths) != 0
+}
+
+// SetOneline controls whether Error() renders the chain
+// on a single line, see Oneline().
+func SetOneline(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&oneline, v)
+}
+
+func onelineEnabled() bool {
+	return atomic.LoadInt32(&oneline) != 0
+}
+
+// SetStackMode sets the context recorded for errors of every domain
+// which has no mode of its own.
+func SetStackMode(mode StackMode) {
+	if mode == StackDefault {
+		mode = StackFull
+	}
+	atomic.StoreInt32(&globalMode, int32(mode))
+}
+
+// SetDomainStackMode sets the context recorded for errors of one domain.
+// Use StackDefault to revert to the global mode.
+func SetDomainStackMode(domain string, mode StackMode) {
+	if mode == StackDefault {
+		domainModes.Delete(domain)
+	} else {
+		domainModes.Store(domain, mode)
+	}
+}
+
+func stackMode(domain string) StackMode {
+	if mode, ok := domainModes.Load(domain); ok {
+		return mode.(StackMode)
+	}
+	return StackMode(atomic.LoadInt32(&globalMode))
+}
+
+// SetStackSampling limits full stack capture to 1 in "n" errors
+// of each domain and code. The first occurrence of each is always captured;
+// the others only record their immediate caller, as with StackCaller.
+// A rate of 0 or 1 captures every stack.
+func SetStackSampling(n int) {
+	atomic.StoreInt64(&sampleRate, int64(n))
+}
+
+// sampled reports whether the next error with this domain and code
+// should capture a full stack.
+func sampled(domain string, code ErrCode) bool {
+	n := atomic.LoadInt64(&sampleRate)
+	if n <= 1 {
+		return true
+	}
+	key := Target{Domain: domain, Code: code}
+	count, ok := sampleCounts.Load(key)
+	if !ok {
+		count, _ = sampleCounts.LoadOrStore(key, new(int64))
+	}
+	return (atomic.AddInt64(count.(*int64), 1)-1)%n == 0
+}
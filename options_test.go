@@ -0,0 +1,183 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"errors"
+	gc "github.com/motain/gocheck"
+	"io"
+	"strings"
+)
+
+func recurse(n int, fn func() *Error) *Error {
+	if n == 0 {
+		return fn()
+	}
+	return recurse(n-1, fn)
+}
+
+func (t *TestSuite) TestMaxStackDepth(c *gc.C) {
+	defer SetMaxStackDepth(DefaultStackDepth)
+
+	err := recurse(100, func() *Error { return New(0, "ergo", EMyError0) })
+	c.Check(err.Stack.pcs, gc.HasLen, DefaultStackDepth)
+
+	SetMaxStackDepth(2)
+	err = New(0, "ergo", EMyError0)
+	c.Check(err.Stack.pcs, gc.HasLen, 2)
+	c.Check(err.Stack.Frames()[0].Function, gc.Matches, ".*TestMaxStackDepth$")
+
+	SetMaxStackDepth(0)
+	err = New(0, "ergo", EMyError0)
+	c.Check(err.Stack, gc.IsNil)
+	c.Check(err.Error(), gc.Equals, "[ergo:0] My error 0\n")
+
+	SetMaxStackDepth(Unbounded)
+	err = recurse(200, func() *Error { return New(0, "ergo", EMyError0) })
+	c.Check(len(err.Stack.pcs) > 200, gc.Equals, true)
+}
+
+func (t *TestSuite) TestWithStackDepth(c *gc.C) {
+	err := New(0, "ergo", EMyErrorArgs, "name", "x", WithStackDepth(1))
+	c.Check(err.Stack.pcs, gc.HasLen, 1)
+	c.Check(err.Info, gc.DeepEquals, ErrInfo{"name": "x"})
+
+	err = New(0, "ergo", EMyError0, WithStackDepth(0))
+	c.Check(err.Stack, gc.IsNil)
+
+	err = recurse(100, func() *Error {
+		return New(0, "ergo", EMyError0, WithStackDepth(Unbounded))
+	})
+	c.Check(len(err.Stack.pcs) > 100, gc.Equals, true)
+
+	err = recurse(100, func() *Error {
+		return New(0, "ergo", EMyError0, WithStackDepth(70))
+	})
+	c.Check(err.Stack.pcs, gc.HasLen, 70)
+
+	wrapped := Wrap(io.EOF, WithStackDepth(3))
+	c.Check(wrapped.Stack.pcs, gc.HasLen, 3)
+}
+
+func (t *TestSuite) TestStackCaller(c *gc.C) {
+	SetStackMode(StackCaller)
+	defer SetStackMode(StackFull)
+
+	err := New(0, "ergo", EMyError0)
+	c.Check(err.Stack, gc.IsNil)
+	c.Check(err.Context, gc.Matches, ".*options_test.go:[0-9]+ .*TestStackCaller$")
+	lines := strings.Split(err.Error(), "\n")
+	c.Check(lines, gc.HasLen, 3)
+	c.Check(lines[1], gc.Equals, err.Context)
+
+	outer := Chain(err, NewError(EMyError1))
+	chains := strings.Split(outer.Error(), "\n\n")
+	c.Check(chains, gc.HasLen, 2)
+
+	err = New(0, "ergo", EMyError0, WithStackDepth(0))
+	c.Check(err.Context, gc.Equals, "")
+}
+
+func (t *TestSuite) TestDomainStackMode(c *gc.C) {
+	SetDomainStackMode("ergo", StackCaller)
+	defer SetDomainStackMode("ergo", StackDefault)
+
+	err := New(0, "ergo", EMyError0)
+	c.Check(err.Stack, gc.IsNil)
+	c.Check(err.Context, gc.Not(gc.Equals), "")
+
+	err = New(0, "other", EMyError0)
+	c.Check(err.Stack, gc.NotNil)
+	c.Check(err.Context, gc.Equals, "")
+
+	SetStackMode(StackCaller)
+	defer SetStackMode(StackFull)
+	SetDomainStackMode("other", StackFull)
+	defer SetDomainStackMode("other", StackDefault)
+	err = New(0, "other", EMyError0)
+	c.Check(err.Stack, gc.NotNil)
+}
+
+func (t *TestSuite) TestStackSampling(c *gc.C) {
+	SetStackSampling(3)
+	defer SetStackSampling(0)
+
+	var full []int
+	for i := 0; i < 7; i++ {
+		err := New(0, "sampled", EMyError0)
+		if err.Stack != nil {
+			full = append(full, i)
+		} else {
+			c.Check(err.Context, gc.Matches, ".*TestStackSampling$")
+		}
+	}
+	c.Check(full, gc.DeepEquals, []int{0, 3, 6})
+
+	// Each code is sampled independently.
+	err := New(0, "sampled", EMyError1)
+	c.Check(err.Stack, gc.NotNil)
+
+	SetStackSampling(0)
+	err = New(0, "sampled", EMyError0)
+	c.Check(err.Stack, gc.NotNil)
+}
+
+func (t *TestSuite) TestNewE(c *gc.C) {
+	err := NewE("ergo", EMyErrorArgs, WithInfo("arg1", "a"), WithInfo("arg2", 2))
+	c.Check(err.Message(), gc.Equals, NewError(EMyErrorArgs, "arg1", "a", "arg2", 2).Message())
+	c.Check(err.Stack.Frames()[0].Function, gc.Matches, ".*TestNewE$")
+
+	err = newHelper(WithSkip(1), WithInner(io.EOF), WithSeverity(SeverityWarn))
+	c.Check(err.Stack.Frames()[0].Function, gc.Matches, ".*TestNewE$")
+	c.Check(err.Inner.Message(), gc.Equals, "Error: EOF")
+	c.Check(err.Inner.Stack.Frames()[0].Function, gc.Matches, ".*TestNewE$")
+	c.Check(err.Severity, gc.Equals, SeverityWarn)
+	c.Check(errors.Is(err, io.EOF), gc.Equals, true)
+
+	inner := NewE("ergo", EMyError0)
+	err = NewE("ergo", EMyError1, WithInner(inner), WithoutStack())
+	c.Check(err.Inner, gc.Equals, inner)
+	c.Check(err.Stack, gc.IsNil)
+	c.Check(err.Context, gc.Equals, "")
+}
+
+func (t *TestSuite) TestWithWrapped(c *gc.C) {
+	err := NewE("ergo", EMyError0, WithWrapped(io.EOF))
+	c.Check(err.Unwrap(), gc.Equals, io.EOF)
+	c.Check(err.Error(), gc.Not(gc.Matches), "(?s).*EOF.*")
+
+	err = NewE("ergo", EMyError0, WithInner(NewE("ergo", EMyError1)), WithWrapped(io.EOF))
+	c.Check(err.Unwrap(), gc.Equals, err.Inner)
+	c.Check(errors.Is(err, io.EOF), gc.Equals, true)
+
+	wire := err.ToWire()
+	decoded, derr := wire.ToError(WithWrapped(io.ErrUnexpectedEOF))
+	c.Assert(derr, gc.IsNil)
+	c.Check(errors.Is(decoded, io.ErrUnexpectedEOF), gc.Equals, true)
+	c.Check(errors.Is(decoded.Inner, io.ErrUnexpectedEOF), gc.Equals, false)
+}
+
+func newHelper(opts ...Option) *Error {
+	return NewE("ergo", EMyError0, opts...)
+}
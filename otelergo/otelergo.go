@@ -0,0 +1,152 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package otelergo exports ergo errors as OpenTelemetry (OTLP) log records,
+// so that they can be shipped straight into OTel collectors.
+package otelergo
+
+import (
+	"fmt"
+	"github.com/flaub/ergo"
+	common "go.opentelemetry.io/proto/otlp/common/v1"
+	logs "go.opentelemetry.io/proto/otlp/logs/v1"
+	"sort"
+	"time"
+)
+
+// Attribute keys of log records.
+const (
+	DomainKey     = "error.domain"
+	CodeKey       = "error.code"
+	InfoPrefix    = "error.info."
+	StacktraceKey = "exception.stacktrace"
+)
+
+var severities = map[ergo.Severity]logs.SeverityNumber{
+	ergo.SeverityDebug: logs.SeverityNumber_SEVERITY_NUMBER_DEBUG,
+	ergo.SeverityInfo:  logs.SeverityNumber_SEVERITY_NUMBER_INFO,
+	ergo.SeverityWarn:  logs.SeverityNumber_SEVERITY_NUMBER_WARN,
+	ergo.SeverityError: logs.SeverityNumber_SEVERITY_NUMBER_ERROR,
+	ergo.SeverityFatal: logs.SeverityNumber_SEVERITY_NUMBER_FATAL,
+}
+
+// LogRecord converts an error into an OTLP log record observed now.
+// The severity is the Level() of the error, the body is Message(),
+// and the attributes hold the domain, code and Info of the error,
+// along with the whole chain formatted with "%+v" as the stack trace.
+// Reserved Info keys (see ergo.IsReserved) are left out,
+// since they are already part of the message.
+func LogRecord(err *ergo.Error) *logs.LogRecord {
+	now := uint64(time.Now().UnixNano())
+	level := err.Level()
+	record := &logs.LogRecord{
+		TimeUnixNano:         now,
+		ObservedTimeUnixNano: now,
+		SeverityNumber:       severities[level],
+		SeverityText:         level.String(),
+		Body:                 stringValue(err.Message()),
+		Attributes: []*common.KeyValue{
+			{Key: DomainKey, Value: stringValue(err.Domain)},
+			{Key: CodeKey, Value: intValue(int64(err.Code))},
+		},
+	}
+	for _, key := range err.Info.Keys() {
+		if ergo.IsReserved(key) {
+			continue
+		}
+		record.Attributes = append(record.Attributes, &common.KeyValue{
+			Key:   InfoPrefix + key,
+			Value: anyValue(ergo.Redact(key, err.Info[key])),
+		})
+	}
+	record.Attributes = append(record.Attributes, &common.KeyValue{
+		Key:   StacktraceKey,
+		Value: stringValue(fmt.Sprintf("%+v", err)),
+	})
+	return record
+}
+
+func stringValue(s string) *common.AnyValue {
+	return &common.AnyValue{Value: &common.AnyValue_StringValue{StringValue: s}}
+}
+
+func intValue(i int64) *common.AnyValue {
+	return &common.AnyValue{Value: &common.AnyValue_IntValue{IntValue: i}}
+}
+
+// anyValue converts an Info value, using its string form for
+// types which have no counterpart in OTLP.
+func anyValue(value interface{}) *common.AnyValue {
+	switch v := value.(type) {
+	case string:
+		return stringValue(v)
+	case bool:
+		return &common.AnyValue{Value: &common.AnyValue_BoolValue{BoolValue: v}}
+	case int:
+		return intValue(int64(v))
+	case int32:
+		return intValue(int64(v))
+	case int64:
+		return intValue(v)
+	case uint32:
+		return intValue(int64(v))
+	case ergo.ErrCode:
+		return intValue(int64(v))
+	case time.Duration:
+		return intValue(int64(v))
+	case float32:
+		return &common.AnyValue{Value: &common.AnyValue_DoubleValue{DoubleValue: float64(v)}}
+	case float64:
+		return &common.AnyValue{Value: &common.AnyValue_DoubleValue{DoubleValue: v}}
+	case []byte:
+		return &common.AnyValue{Value: &common.AnyValue_BytesValue{BytesValue: v}}
+	case []interface{}:
+		array := &common.ArrayValue{}
+		for _, item := range v {
+			array.Values = append(array.Values, anyValue(item))
+		}
+		return &common.AnyValue{Value: &common.AnyValue_ArrayValue{ArrayValue: array}}
+	case map[string]interface{}:
+		return kvlistValue(v)
+	case ergo.ErrInfo:
+		return kvlistValue(v)
+	case error:
+		return stringValue(v.Error())
+	case nil:
+		return &common.AnyValue{}
+	}
+	return stringValue(fmt.Sprintf("%v", value))
+}
+
+func kvlistValue(m map[string]interface{}) *common.AnyValue {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	list := &common.KeyValueList{}
+	for _, key := range keys {
+		list.Values = append(list.Values, &common.KeyValue{Key: key, Value: anyValue(m[key])})
+	}
+	return &common.AnyValue{Value: &common.AnyValue_KvlistValue{KvlistValue: list}}
+}
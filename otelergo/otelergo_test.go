@@ -0,0 +1,108 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package otelergo
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	common "go.opentelemetry.io/proto/otlp/common/v1"
+	logs "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"testing"
+	"time"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("otelergo", ergo.DomainMap{
+		ENotFound: "The {{.name}} was not found",
+	})
+}
+
+func attributes(record *logs.LogRecord) map[string]*common.AnyValue {
+	attrs := make(map[string]*common.AnyValue)
+	for _, kv := range record.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func (t *TestSuite) TestLogRecord(c *gc.C) {
+	err := ergo.New(0, "otelergo", ENotFound,
+		"name", "user",
+		"id", 7,
+		"ratio", 0.5,
+		"tags", []interface{}{"a", true},
+		"cause", io.EOF,
+		ergo.WithSeverity(ergo.SeverityWarn),
+	)
+	record := LogRecord(err)
+	c.Check(record.TimeUnixNano > 0, gc.Equals, true)
+	c.Check(record.SeverityNumber, gc.Equals, logs.SeverityNumber_SEVERITY_NUMBER_WARN)
+	c.Check(record.SeverityText, gc.Equals, "WARN")
+	c.Check(record.Body.GetStringValue(), gc.Equals, "The user was not found")
+
+	attrs := attributes(record)
+	c.Check(record.Attributes, gc.HasLen, 8)
+	c.Check(attrs[DomainKey].GetStringValue(), gc.Equals, "otelergo")
+	c.Check(attrs[CodeKey].GetIntValue(), gc.Equals, int64(ENotFound))
+	c.Check(attrs["error.info.name"].GetStringValue(), gc.Equals, "user")
+	c.Check(attrs["error.info.id"].GetIntValue(), gc.Equals, int64(7))
+	c.Check(attrs["error.info.ratio"].GetDoubleValue(), gc.Equals, 0.5)
+	c.Check(attrs["error.info.cause"].GetStringValue(), gc.Equals, "EOF")
+	tags := attrs["error.info.tags"].GetArrayValue().GetValues()
+	c.Assert(tags, gc.HasLen, 2)
+	c.Check(tags[1].GetBoolValue(), gc.Equals, true)
+	c.Check(attrs[StacktraceKey].GetStringValue(), gc.Matches,
+		"(?s)\\[otelergo:0\\] The user was not found\n.*TestLogRecord.*")
+
+	_, merr := proto.Marshal(record)
+	c.Check(merr, gc.IsNil)
+}
+
+func (t *TestSuite) TestDefaultSeverity(c *gc.C) {
+	record := LogRecord(ergo.Wrap(io.EOF, ergo.WithStackDepth(0)))
+	c.Check(record.SeverityNumber, gc.Equals, logs.SeverityNumber_SEVERITY_NUMBER_ERROR)
+	c.Check(record.SeverityText, gc.Equals, "ERROR")
+	c.Check(record.Body.GetStringValue(), gc.Equals, "Error: EOF")
+	_, ok := attributes(record)["error.info._err"]
+	c.Check(ok, gc.Equals, false)
+
+	record = LogRecord(ergo.New(0, "otelergo", ENotFound, "d", time.Second, ergo.WithSeverity(ergo.SeverityFatal)))
+	c.Check(record.SeverityNumber, gc.Equals, logs.SeverityNumber_SEVERITY_NUMBER_FATAL)
+	c.Check(attributes(record)["error.info.d"].GetIntValue(), gc.Equals, int64(time.Second))
+}
@@ -0,0 +1,87 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"sync"
+	"text/template"
+)
+
+// override is a message format set with Override.
+type override struct {
+	text string
+	tmpl *template.Template
+}
+
+// The overrides in effect, by Target.
+var overrides sync.Map
+
+// Override replaces the message format of a domain and code at runtime,
+// in every locale, for instance to add an incident notice or to clarify
+// a confusing message without redeploying. The format is processed as
+// by Domain but, since it may come from operators, an invalid format
+// is reported instead of panicking.
+func Override(domain string, code ErrCode, format string) error {
+	name := fmt.Sprintf("[%v:%d]", domain, code)
+	tmpl, err := template.New(name).Funcs(funcsFor(domain, "en")).Parse(format)
+	if err != nil {
+		return err
+	}
+	overrides.Store(Target{Domain: domain, Code: code}, &override{text: format, tmpl: tmpl})
+	return nil
+}
+
+// ClearOverride reverts the message format of a domain and code
+// to the one it was defined with.
+func ClearOverride(domain string, code ErrCode) {
+	overrides.Delete(Target{Domain: domain, Code: code})
+}
+
+// ClearOverrides reverts every override.
+func ClearOverrides() {
+	overrides.Range(func(key, _ interface{}) bool {
+		overrides.Delete(key)
+		return true
+	})
+}
+
+// Overrides returns the message formats of the overrides in effect.
+func Overrides() map[Target]string {
+	formats := make(map[Target]string)
+	overrides.Range(func(key, value interface{}) bool {
+		formats[key.(Target)] = value.(*override).text
+		return true
+	})
+	return formats
+}
+
+// overridden formats the message of an error with its override, if any.
+func (err *Error) overridden(domain string) (string, bool) {
+	value, ok := overrides.Load(Target{Domain: domain, Code: err.Code})
+	if !ok {
+		return "", false
+	}
+	return execute(value.(*override).tmpl, err), true
+}
@@ -0,0 +1,51 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	gc "github.com/motain/gocheck"
+)
+
+func (t *TestSuite) TestOverride(c *gc.C) {
+	defer ClearOverrides()
+	err := NewError(EMyErrorArgs, "name", "sync")
+	c.Assert(Override("ergo", EMyErrorArgs, "{{.name}} is degraded, see status page"), gc.IsNil)
+	c.Assert(Override("ergo", EMyError0, "Temporarily unavailable"), gc.IsNil)
+	c.Check(err.Message(), gc.Equals, "sync is degraded, see status page")
+	c.Check(err.MessageIn("de"), gc.Equals, "sync is degraded, see status page")
+	c.Check(Overrides(), gc.DeepEquals, map[Target]string{
+		Code("ergo", EMyErrorArgs): "{{.name}} is degraded, see status page",
+		Code("ergo", EMyError0):    "Temporarily unavailable",
+	})
+
+	c.Check(Override("ergo", EMyError1, "{{.name"), gc.ErrorMatches, ".*unclosed action.*")
+	c.Check(NewError(EMyError1).Message(), gc.Equals, "My error 1")
+
+	ClearOverride("ergo", EMyErrorArgs)
+	c.Check(err.Message(), gc.Equals, "The sync failed")
+	c.Check(Overrides(), gc.HasLen, 1)
+	ClearOverrides()
+	c.Check(Overrides(), gc.HasLen, 0)
+	c.Check(NewError(EMyError0).Message(), gc.Equals, "My error 0")
+}
@@ -0,0 +1,115 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Must returns "v", or panics with "err" wrapped as an Error if it is
+// not nil. It is meant for initialization, where an error is fatal:
+//
+//	var tmpl = ergo.Must(template.ParseFiles("page.html"))
+//
+// The stack starts at the caller.
+func Must[T any](v T, err error) T {
+	if err != nil {
+		if ergo, ok := err.(*Error); ok {
+			panic(ergo)
+		}
+		panic(_Wrap(1, err))
+	}
+	return v
+}
+
+// Check panics with an error of a domain wrapping "err", see WrapAs,
+// if "err" is not nil. The stack starts at the caller.
+func Check(err error, domain string, code ErrCode, args ...interface{}) {
+	if err == nil {
+		return
+	}
+	panic(New(1, domain, code, append(args[:len(args):len(args)], WithInner(unstacked(err)))...))
+}
+
+// Recover converts a panic into an error of a domain, assigned to "*errp".
+// It must be deferred directly, by functions with a named error result:
+//
+//	func (w *worker) run(job Job) (err error) {
+//		defer ergo.Recover(&err, "worker", EPanic)
+//		...
+//	}
+//
+// The panic value becomes the inner error, and the stack starts where
+// the panic occurred. Without a panic, "*errp" is left unchanged.
+func Recover(errp *error, domain string, code ErrCode) {
+	if x := recover(); x != nil {
+		*errp = Recovered(x, domain, code)
+	}
+}
+
+// Recovered converts a value recovered from a panic into an error of
+// a domain, for deferred functions calling recover themselves.
+// It must be called while the panic is being recovered, so that the
+// stack starts where the panic occurred. The panic value becomes the
+// inner error and is kept, see PanicValue.
+func Recovered(x interface{}, domain string, code ErrCode, args ...interface{}) *Error {
+	pcs := panicCallers()
+	inner, ok := x.(error)
+	if !ok {
+		inner = fmt.Errorf("%v", x)
+	}
+	return New(0, domain, code, append(args[:len(args):len(args)],
+		WithInner(unstacked(inner)), panicking(x, pcs))...)
+}
+
+// PanicValue returns the value recovered by Recover,
+// from this error or its inner errors.
+func (err *Error) PanicValue() (interface{}, bool) {
+	var x interface{}
+	walkLinks(err, func(link *Error) bool {
+		x = link.panicked
+		return x == nil
+	})
+	return x, x != nil
+}
+
+// Repanic panics again with the value recovered by Recover, so that
+// supervisors can log a panic and still crash faithfully:
+//
+//	if err := run(job); err != nil {
+//		log.Print(err)
+//		ergo.Repanic(err)
+//	}
+//
+// Other errors are not the result of a panic and are ignored.
+func Repanic(err error) {
+	var ergo *Error
+	if !errors.As(err, &ergo) {
+		return
+	}
+	if x, ok := ergo.PanicValue(); ok {
+		panic(x)
+	}
+}
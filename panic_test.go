@@ -0,0 +1,162 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"errors"
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"io"
+	"runtime"
+	"strconv"
+)
+
+func recovered(fn func()) (x interface{}) {
+	defer func() { x = recover() }()
+	fn()
+	return nil
+}
+
+func (t *TestSuite) TestMust(c *gc.C) {
+	c.Check(Must(strconv.Atoi("42")), gc.Equals, 42)
+
+	x := recovered(func() { Must(strconv.Atoi("x")) })
+	err, ok := x.(*Error)
+	c.Assert(ok, gc.Equals, true)
+	c.Check(err.Domain, gc.Equals, "go")
+	c.Check(err.Message(), gc.Equals, `Error: strconv.Atoi: parsing "x": invalid syntax`)
+	c.Check(err.Stack.Frames()[0].Function, gc.Matches, ".*TestMust.func.*")
+	c.Check(errors.Is(err, strconv.ErrSyntax), gc.Equals, true)
+
+	inner := NewError(EMyError1)
+	c.Check(recovered(func() { Must(0, inner) }), gc.Equals, inner)
+}
+
+func (t *TestSuite) TestCheck(c *gc.C) {
+	c.Check(recovered(func() { Check(nil, "ergo", EMyError1) }), gc.IsNil)
+
+	x := recovered(func() { Check(io.EOF, "ergo", EMyErrorArgs, "name", "read") })
+	err, ok := x.(*Error)
+	c.Assert(ok, gc.Equals, true)
+	c.Check(err.Message(), gc.Equals, "The read failed")
+	c.Check(err.Stack.Frames()[0].Function, gc.Matches, ".*TestCheck.func.*")
+	c.Assert(err.Inner, gc.NotNil)
+	c.Check(err.Inner.Stack, gc.IsNil)
+	c.Check(errors.Is(err, io.EOF), gc.Equals, true)
+}
+
+func recoverPanic(x interface{}) (err error) {
+	defer Recover(&err, "ergo", EMyErrorArgs)
+	if x != nil {
+		panic(x)
+	}
+	return io.EOF
+}
+
+func recoverNil() (err error) {
+	defer Recover(&err, "ergo", EMyError1)
+	var info ErrInfo
+	info["x"] = 1
+	return nil
+}
+
+func (t *TestSuite) TestRecover(c *gc.C) {
+	c.Check(recoverPanic(nil), gc.Equals, io.EOF)
+
+	err, ok := AsError(recoverPanic("boom"))
+	c.Assert(ok, gc.Equals, true)
+	c.Check(err.Code, gc.Equals, EMyErrorArgs)
+	c.Check(err.Stack.Frames()[0].Function, gc.Matches, ".*recoverPanic$")
+	c.Assert(err.Inner, gc.NotNil)
+	c.Check(err.Inner.Message(), gc.Equals, "Error: boom")
+	c.Check(err.Inner.Stack, gc.IsNil)
+
+	err, ok = AsError(recoverPanic(io.ErrUnexpectedEOF))
+	c.Assert(ok, gc.Equals, true)
+	c.Check(errors.Is(err, io.ErrUnexpectedEOF), gc.Equals, true)
+
+	inner := NewError(EMyError1)
+	err, _ = AsError(recoverPanic(inner))
+	c.Check(err.Inner, gc.Equals, inner)
+
+	err, ok = AsError(recoverNil())
+	c.Assert(ok, gc.Equals, true)
+	c.Check(err.Stack.Frames()[0].Function, gc.Matches, ".*recoverNil$")
+	var rerr runtime.Error
+	c.Check(errors.As(err, &rerr), gc.Equals, true)
+}
+
+func recoverManually() (err error) {
+	defer func() {
+		if x := recover(); x != nil {
+			err = Recovered(x, "ergo", EMyError1, "x", 1)
+		}
+	}()
+	var info ErrInfo
+	info["x"] = 1
+	return nil
+}
+
+func (t *TestSuite) TestRecovered(c *gc.C) {
+	err, ok := AsError(recoverManually())
+	c.Assert(ok, gc.Equals, true)
+	c.Check(err.Info["x"], gc.Equals, 1)
+	c.Check(err.Stack.Frames()[0].Function, gc.Matches, ".*recoverManually$")
+	_, ok = err.PanicValue()
+	c.Check(ok, gc.Equals, true)
+}
+
+func recoverMust() (n int, err error) {
+	defer Recover(&err, "ergo", EMyError1)
+	return Must(strconv.Atoi("x")), nil
+}
+
+func (t *TestSuite) TestRecoverMust(c *gc.C) {
+	_, err := recoverMust()
+	c.Check(errors.Is(err, strconv.ErrSyntax), gc.Equals, true)
+	outer, _ := AsError(err)
+	c.Assert(outer.Inner, gc.NotNil)
+	c.Check(outer.Inner.Stack.Frames()[0].Function, gc.Matches, ".*recoverMust$")
+}
+
+func (t *TestSuite) TestRepanic(c *gc.C) {
+	value := []int{1, 2}
+	err := recoverPanic(value)
+	x, ok := err.(*Error).PanicValue()
+	c.Check(ok, gc.Equals, true)
+	c.Check(x, gc.DeepEquals, value)
+	c.Check(recovered(func() { Repanic(err) }), gc.DeepEquals, value)
+
+	wrapped := WrapAs(err, "ergo", EMyError0)
+	x, ok = wrapped.PanicValue()
+	c.Check(ok, gc.Equals, true)
+	c.Check(x, gc.DeepEquals, value)
+	c.Check(recovered(func() { Repanic(fmt.Errorf("job: %w", wrapped)) }), gc.DeepEquals, value)
+
+	_, ok = NewError(EMyError1).PanicValue()
+	c.Check(ok, gc.Equals, false)
+	c.Check(recovered(func() { Repanic(NewError(EMyError1)) }), gc.IsNil)
+	c.Check(recovered(func() { Repanic(io.EOF) }), gc.IsNil)
+	c.Check(recovered(func() { Repanic(nil) }), gc.IsNil)
+}
@@ -0,0 +1,234 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Plural categories, as defined by CLDR.
+const (
+	zero = iota
+	one
+	two
+	few
+	many
+	other
+)
+
+// pluralRule returns the category of a number given its integer digits
+// "i" and its number of visible fraction digits "v".
+type pluralRule func(n float64, i, v int) int
+
+// pluralRules holds the plural rules of a language: the categories it
+// uses, in order, followed by its cardinal and ordinal rules.
+type pluralRules struct {
+	cardinal   []int
+	cardinalFn pluralRule
+	ordinal    []int
+	ordinalFn  pluralRule
+}
+
+func onlyOther(n float64, i, v int) int { return other }
+
+func oneOther(n float64, i, v int) int {
+	if i == 1 && v == 0 {
+		return one
+	}
+	return other
+}
+
+// slavic implements the rules of Russian and Ukrainian.
+func slavic(n float64, i, v int) int {
+	switch {
+	case v != 0:
+		return other
+	case i%10 == 1 && i%100 != 11:
+		return one
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return few
+	}
+	return many
+}
+
+var (
+	germanic = &pluralRules{[]int{one, other}, oneOther, []int{other}, onlyOther}
+	asian    = &pluralRules{[]int{other}, onlyOther, []int{other}, onlyOther}
+	eastern  = &pluralRules{[]int{one, few, many, other}, slavic, []int{other}, onlyOther}
+
+	english = &pluralRules{[]int{one, other}, oneOther, []int{one, two, few, other},
+		func(n float64, i, v int) int {
+			switch {
+			case i%10 == 1 && i%100 != 11:
+				return one
+			case i%10 == 2 && i%100 != 12:
+				return two
+			case i%10 == 3 && i%100 != 13:
+				return few
+			}
+			return other
+		}}
+
+	// The plural rules of languages, by primary language subtag.
+	// Other languages follow the rules of English.
+	plurals = map[string]*pluralRules{
+		"en": english,
+		"de": germanic, "nl": germanic, "da": germanic, "nb": germanic,
+		"no": germanic, "fi": germanic, "et": germanic, "el": germanic,
+		"es": germanic, "it": germanic, "tr": germanic, "hu": germanic,
+		"sv": {[]int{one, other}, oneOther, []int{one, other},
+			func(n float64, i, v int) int {
+				if (i%10 == 1 || i%10 == 2) && i%100 != 11 && i%100 != 12 {
+					return one
+				}
+				return other
+			}},
+		"fr": {[]int{one, other}, func(n float64, i, v int) int {
+			if i == 0 || i == 1 {
+				return one
+			}
+			return other
+		}, []int{one, other}, oneOther},
+		"pt": {[]int{one, other}, func(n float64, i, v int) int {
+			if i == 0 || i == 1 {
+				return one
+			}
+			return other
+		}, []int{other}, onlyOther},
+		"ja": asian, "zh": asian, "ko": asian, "vi": asian, "th": asian, "id": asian,
+		"ru": eastern, "uk": eastern,
+		"pl": {[]int{one, few, many, other}, func(n float64, i, v int) int {
+			switch {
+			case v != 0:
+				return other
+			case i == 1:
+				return one
+			case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+				return few
+			}
+			return many
+		}, []int{other}, onlyOther},
+		"cs": {[]int{one, few, many, other}, czech, []int{other}, onlyOther},
+		"sk": {[]int{one, few, many, other}, czech, []int{other}, onlyOther},
+		"ar": {[]int{zero, one, two, few, many, other}, func(n float64, i, v int) int {
+			switch {
+			case n == 0:
+				return zero
+			case n == 1:
+				return one
+			case n == 2:
+				return two
+			case v == 0 && i%100 >= 3 && i%100 <= 10:
+				return few
+			case v == 0 && i%100 >= 11:
+				return many
+			}
+			return other
+		}, []int{other}, onlyOther},
+		"he": {[]int{one, two, other}, func(n float64, i, v int) int {
+			switch {
+			case i == 1 && v == 0 || i == 0 && v != 0:
+				return one
+			case i == 2 && v == 0:
+				return two
+			}
+			return other
+		}, []int{other}, onlyOther},
+	}
+)
+
+func czech(n float64, i, v int) int {
+	switch {
+	case v != 0:
+		return many
+	case i == 1:
+		return one
+	case i >= 2 && i <= 4:
+		return few
+	}
+	return other
+}
+
+// pluralFuncs returns the plural and ordinal template functions
+// of a locale. The forms are given in the order of the categories the language
+// uses (zero, one, two, few, many, other); if fewer are given,
+// the last one is used for the remaining categories.
+func pluralFuncs(locale string) template.FuncMap {
+	language, _, _ := strings.Cut(locale, "-")
+	rules, ok := plurals[language]
+	if !ok {
+		rules = english
+	}
+	return template.FuncMap{
+		"plural": func(n interface{}, forms ...string) string {
+			return selectForm(n, rules.cardinal, rules.cardinalFn, forms)
+		},
+		"ordinal": func(n interface{}, forms ...string) string {
+			return selectForm(n, rules.ordinal, rules.ordinalFn, forms)
+		},
+	}
+}
+
+// selectForm returns the form of the category of "n".
+// Values which are not numbers use the last form.
+func selectForm(value interface{}, categories []int, rule pluralRule, forms []string) string {
+	if len(forms) == 0 {
+		return ""
+	}
+	n, ok := toFloat(value)
+	if !ok {
+		return forms[len(forms)-1]
+	}
+	digits := strconv.FormatFloat(math.Abs(n), 'f', -1, 64)
+	integer, fraction, _ := strings.Cut(digits, ".")
+	i, _ := strconv.Atoi(integer)
+	category := rule(math.Abs(n), i, len(fraction))
+	for index, c := range categories {
+		if c == category && index < len(forms) {
+			return forms[index]
+		}
+	}
+	return forms[len(forms)-1]
+}
+
+// toFloat converts a numeric value, or a string holding one, to float64.
+func toFloat(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		n, err := strconv.ParseFloat(v.String(), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,95 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	gc "github.com/motain/gocheck"
+)
+
+func (t *TestSuite) TestPlural(c *gc.C) {
+	Domain("plural", DomainMap{
+		0: "{{.count}} {{plural .count \"file\" \"files\"}} failed",
+		1: "The {{.n}}{{ordinal .n \"st\" \"nd\" \"rd\" \"th\"}} attempt failed",
+	})
+	DomainLocale("plural", "pl", DomainMap{
+		0: "{{.count}} {{plural .count \"plik\" \"pliki\" \"plików\" \"pliku\"}}",
+	})
+	DomainLocale("plural", "ja", DomainMap{
+		0: "{{.count}} {{plural .count \"ファイル\"}}",
+	})
+	DomainLocale("plural", "de", DomainMap{
+		1: "Der {{.n}}{{ordinal .n \".\"}} Versuch ist fehlgeschlagen",
+	})
+
+	files := func(count interface{}, locale string) string {
+		return New(0, "plural", 0, "count", count).MessageIn(locale)
+	}
+	c.Check(files(1, ""), gc.Equals, "1 file failed")
+	c.Check(files(0, ""), gc.Equals, "0 files failed")
+	c.Check(files(1.0, ""), gc.Equals, "1 file failed")
+	c.Check(files(1.5, ""), gc.Equals, "1.5 files failed")
+	c.Check(files("2", ""), gc.Equals, "2 files failed")
+	c.Check(files(nil, ""), gc.Equals, "<no value> files failed")
+	c.Check(files(1, "pl"), gc.Equals, "1 plik")
+	c.Check(files(3, "pl"), gc.Equals, "3 pliki")
+	c.Check(files(12, "pl"), gc.Equals, "12 plików")
+	c.Check(files(22, "pl"), gc.Equals, "22 pliki")
+	c.Check(files(1.5, "pl"), gc.Equals, "1.5 pliku")
+	c.Check(files(1, "ja"), gc.Equals, "1 ファイル")
+
+	attempt := func(n int, locale string) string {
+		return New(0, "plural", 1, "n", n).MessageIn(locale)
+	}
+	c.Check(attempt(1, ""), gc.Equals, "The 1st attempt failed")
+	c.Check(attempt(12, ""), gc.Equals, "The 12th attempt failed")
+	c.Check(attempt(23, ""), gc.Equals, "The 23rd attempt failed")
+	c.Check(attempt(3, "de"), gc.Equals, "Der 3. Versuch ist fehlgeschlagen")
+}
+
+func (t *TestSuite) TestPluralRules(c *gc.C) {
+	// The forms are named after the categories of each language.
+	form := func(locale string, n float64, forms ...string) string {
+		rules := pluralFuncs(locale)
+		return rules["plural"].(func(interface{}, ...string) string)(n, forms...)
+	}
+	slavic := []string{"one", "few", "many", "other"}
+	c.Check(form("ru", 21, slavic...), gc.Equals, "one")
+	c.Check(form("ru", 11, slavic...), gc.Equals, "many")
+	c.Check(form("uk-UA", 24, slavic...), gc.Equals, "few")
+	c.Check(form("ru", 1.5, slavic...), gc.Equals, "other")
+	c.Check(form("cs", 3, slavic...), gc.Equals, "few")
+	c.Check(form("cs", 0.5, slavic...), gc.Equals, "many")
+	c.Check(form("fr", 0, "one", "other"), gc.Equals, "one")
+	c.Check(form("fr", 2, "one", "other"), gc.Equals, "other")
+
+	arabic := []string{"zero", "one", "two", "few", "many", "other"}
+	c.Check(form("ar", 0, arabic...), gc.Equals, "zero")
+	c.Check(form("ar", 2, arabic...), gc.Equals, "two")
+	c.Check(form("ar", 105, arabic...), gc.Equals, "few")
+	c.Check(form("ar", 111, arabic...), gc.Equals, "many")
+	c.Check(form("ar", 100, arabic...), gc.Equals, "other")
+
+	c.Check(form("xx", 1, "one", "other"), gc.Equals, "one")
+	c.Check(form("en", 2), gc.Equals, "")
+}
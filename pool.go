@@ -0,0 +1,68 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"sync"
+)
+
+// maxPooledInfo bounds the size of Info maps kept in the pool,
+// since maps never shrink once grown.
+const maxPooledInfo = 32
+
+var (
+	errorPool = sync.Pool{
+		New: func() interface{} {
+			return &Error{Info: make(ErrInfo)}
+		},
+	}
+)
+
+// allocError returns an empty error, reusing a released one if possible.
+func allocError(domain string, code ErrCode) *Error {
+	err := errorPool.Get().(*Error)
+	err.Domain = domain
+	err.Code = code
+	return err
+}
+
+// Release returns this error to an internal pool,
+// so that its memory can be reused by subsequent calls to New.
+//
+// Only the owner of an error may release it, typically the code which
+// created it, once the error has been handled (for example after it has
+// been serialized into a response). Neither the error nor its Info may be
+// used after it is released. Inner errors and Causes are not released,
+// since they may be shared with other chains.
+func (err *Error) Release() {
+	info := err.Info
+	if info == nil || len(info) > maxPooledInfo {
+		info = make(ErrInfo)
+	}
+	for key := range info {
+		delete(info, key)
+	}
+	*err = Error{Info: info}
+	errorPool.Put(err)
+}
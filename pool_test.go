@@ -0,0 +1,73 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"encoding/json"
+	gc "github.com/motain/gocheck"
+	"io"
+)
+
+func (t *TestSuite) TestRelease(c *gc.C) {
+	inner := NewError(EMyError0)
+	err := Chain(inner, NewError(EMyErrorArgs, "name", "x")).(*Error)
+	info := err.Info
+	err.Release()
+	c.Check(err.Domain, gc.Equals, "")
+	c.Check(err.Inner, gc.IsNil)
+	c.Check(err.Stack, gc.IsNil)
+	c.Check(info, gc.HasLen, 0)
+	c.Check(inner.Message(), gc.Equals, messages[EMyError0])
+
+	for i := 0; i < 10; i++ {
+		err = Wrap(io.EOF, "attempt", i)
+		c.Check(err.Domain, gc.Equals, "go")
+		c.Check(err.Info, gc.DeepEquals, ErrInfo{"_err": "EOF", "attempt": i})
+		c.Check(err.Unwrap(), gc.Equals, io.EOF)
+		err.Release()
+	}
+}
+
+func (t *TestSuite) TestReleaseLiteral(c *gc.C) {
+	err := &Error{Domain: "x"}
+	err.Release()
+	c.Check(err.Info, gc.NotNil)
+	err = New(0, "ergo", EMyErrorArgs, "name", "x")
+	c.Check(err.Info, gc.DeepEquals, ErrInfo{"name": "x"})
+}
+
+func (t *TestSuite) BenchmarkNewSerialize(c *gc.C) {
+	for i := 0; i < c.N; i++ {
+		err := NewError(EMyErrorArgs, "name", "x")
+		json.Marshal(err)
+	}
+}
+
+func (t *TestSuite) BenchmarkNewSerializeRelease(c *gc.C) {
+	for i := 0; i < c.N; i++ {
+		err := NewError(EMyErrorArgs, "name", "x")
+		json.Marshal(err)
+		err.Release()
+	}
+}
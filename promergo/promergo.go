@@ -0,0 +1,87 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package promergo exposes Prometheus metrics about ergo errors.
+package promergo
+
+import (
+	"github.com/flaub/ergo"
+	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"sync"
+)
+
+// Collector is a prometheus.Collector exposing the counter
+// ergo_errors_total{domain,code}.
+// Errors are only counted once Observe is installed as a hook,
+// see Enable.
+type Collector struct {
+	desc   *prometheus.Desc
+	mu     sync.Mutex
+	counts map[ergo.Target]uint64
+}
+
+// NewCollector creates a collector with no errors counted.
+func NewCollector() *Collector {
+	return &Collector{
+		desc: prometheus.NewDesc("ergo_errors_total",
+			"Number of errors created, by domain and code.",
+			[]string{"domain", "code"}, nil),
+		counts: make(map[ergo.Target]uint64),
+	}
+}
+
+// Enable creates a collector, registers it with "reg"
+// and installs it as a hook, so that every error created
+// by ergo.New or ergo.Wrap is counted.
+func Enable(reg prometheus.Registerer) (*Collector, error) {
+	c := NewCollector()
+	if err := reg.Register(c); err != nil {
+		return nil, err
+	}
+	ergo.AddHook(c.Observe)
+	return c, nil
+}
+
+// Observe counts an error.
+func (c *Collector) Observe(err *ergo.Error) {
+	key := ergo.Code(err.Domain, err.Code)
+	c.mu.Lock()
+	c.counts[key]++
+	c.mu.Unlock()
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, count := range c.counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue,
+			float64(count), key.Domain, strconv.Itoa(int(key.Code)))
+	}
+}
@@ -0,0 +1,84 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package promergo
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+	EBroken
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("promergo", ergo.DomainMap{
+		ENotFound: "Not found",
+		EBroken:   "Broken",
+	})
+}
+
+func (t *TestSuite) TestCollector(c *gc.C) {
+	reg := prometheus.NewRegistry()
+	collector, err := Enable(reg)
+	c.Assert(err, gc.IsNil)
+
+	ergo.New(0, "promergo", ENotFound)
+	ergo.New(0, "promergo", ENotFound)
+	ergo.New(0, "promergo", EBroken)
+	ergo.Wrap(io.EOF)
+
+	expected := `
+# HELP ergo_errors_total Number of errors created, by domain and code.
+# TYPE ergo_errors_total counter
+ergo_errors_total{code="0",domain="go"} 1
+ergo_errors_total{code="0",domain="promergo"} 2
+ergo_errors_total{code="1",domain="promergo"} 1
+`
+	c.Check(testutil.GatherAndCompare(reg, strings.NewReader(expected), "ergo_errors_total"), gc.IsNil)
+
+	_, err = Enable(reg)
+	c.Check(err, gc.NotNil)
+
+	collector.Observe(&ergo.Error{Domain: "promergo", Code: EBroken})
+	c.Check(testutil.CollectAndCount(collector), gc.Equals, 3)
+	c.Check(testutil.GatherAndCompare(reg, strings.NewReader(strings.Replace(expected,
+		`{code="1",domain="promergo"} 1`, `{code="1",domain="promergo"} 2`, 1)),
+		"ergo_errors_total"), gc.IsNil)
+}
@@ -0,0 +1,134 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"regexp"
+	"sync/atomic"
+)
+
+var (
+	// CreditCardPattern matches payment card numbers of 13 to 19
+	// digits, optionally grouped with spaces or dashes.
+	CreditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+	// EmailPattern matches email addresses.
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+	redactionPolicy atomic.Value
+)
+
+// RedactionPolicy selects Info values to redact wherever errors are
+// rendered: messages, Error(), serializers and logging integrations.
+// Errors themselves are left unchanged.
+type RedactionPolicy struct {
+	// Keys whose values are replaced by RedactedText,
+	// such as regexp.MustCompile("(?i)password|token").
+	Keys []*regexp.Regexp
+
+	// Patterns replaced by RedactedText within string values and
+	// messages, such as CreditCardPattern and EmailPattern.
+	Values []*regexp.Regexp
+}
+
+// SetRedactionPolicy sets the policy applied to every error, so that
+// scrubbing is enforced centrally rather than at each call site.
+// A nil policy only redacts values wrapped with Secret.
+func SetRedactionPolicy(p *RedactionPolicy) {
+	redactionPolicy.Store(p)
+}
+
+func currentPolicy() *RedactionPolicy {
+	p, _ := redactionPolicy.Load().(*RedactionPolicy)
+	return p
+}
+
+// Redact returns an Info value as it should be rendered: RedactedText
+// for secrets and keys selected by the policy, and strings with the
+// value patterns of the policy replaced. Other values are returned as is.
+// Integrations rendering Info themselves should call Redact.
+func Redact(key string, value interface{}) interface{} {
+	value, _ = redact(key, value)
+	return value
+}
+
+// redact implements Redact, reporting whether the value was changed.
+func redact(key string, value interface{}) (interface{}, bool) {
+	if _, ok := value.(Redacted); ok {
+		return RedactedText, true
+	}
+	p := currentPolicy()
+	if p == nil {
+		return value, false
+	}
+	for _, re := range p.Keys {
+		if re.MatchString(key) {
+			return RedactedText, true
+		}
+	}
+	if s, ok := value.(string); ok {
+		if r := p.redactText(s); r != s {
+			return r, true
+		}
+	}
+	return value, false
+}
+
+// RedactInfo returns Info with Redact applied to every value.
+// Info is returned itself if nothing is redacted.
+func RedactInfo(info ErrInfo) ErrInfo {
+	var redacted ErrInfo
+	for key, value := range info {
+		r, changed := redact(key, value)
+		if !changed {
+			continue
+		}
+		if redacted == nil {
+			redacted = make(ErrInfo, len(info))
+			for k, v := range info {
+				redacted[k] = v
+			}
+		}
+		redacted[key] = r
+	}
+	if redacted == nil {
+		return info
+	}
+	return redacted
+}
+
+// redactText replaces the value patterns of the policy within "s".
+func redactText(s string) string {
+	if p := currentPolicy(); p != nil {
+		return p.redactText(s)
+	}
+	return s
+}
+
+func (p *RedactionPolicy) redactText(s string) string {
+	for _, re := range p.Values {
+		s = re.ReplaceAllLiteralString(s, RedactedText)
+	}
+	return s
+}
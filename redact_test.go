@@ -0,0 +1,66 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"encoding/json"
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"regexp"
+	"strings"
+)
+
+func (t *TestSuite) TestRedactionPolicy(c *gc.C) {
+	defer SetRedactionPolicy(nil)
+	SetRedactionPolicy(&RedactionPolicy{
+		Keys:   []*regexp.Regexp{regexp.MustCompile("(?i)password")},
+		Values: []*regexp.Regexp{CreditCardPattern, EmailPattern},
+	})
+
+	err := NewError(EMyErrorArgs, "name", "charging 4111 1111 1111 1111 for ann@example.com",
+		"Password", "hunter2", "ids", []int{1, 2})
+	c.Check(err.Message(), gc.Equals, "The charging [REDACTED] for [REDACTED] failed")
+	for _, leak := range []string{"4111", "ann@", "hunter2"} {
+		c.Check(strings.Contains(err.Error(), leak), gc.Equals, false, gc.Commentf(leak))
+		c.Check(strings.Contains(err.Info.String(), leak), gc.Equals, false, gc.Commentf(leak))
+		c.Check(strings.Contains(fmt.Sprintf("%#v", err), leak), gc.Equals, false, gc.Commentf(leak))
+		data, jerr := json.Marshal(err)
+		c.Assert(jerr, gc.IsNil)
+		c.Check(strings.Contains(string(data), leak), gc.Equals, false, gc.Commentf(leak))
+	}
+	c.Check(err.Info["Password"], gc.Equals, "hunter2")
+
+	wrapped := Wrap(fmt.Errorf("no account for bob@example.com"))
+	c.Check(wrapped.Message(), gc.Equals, "Error: no account for [REDACTED]")
+
+	c.Check(Redact("password", 42), gc.Equals, RedactedText)
+	c.Check(Redact("ids", []int{1}), gc.DeepEquals, []int{1})
+	info := ErrInfo{"ids": []int{1}, "n": 2}
+	c.Check(RedactInfo(info), gc.DeepEquals, info)
+
+	SetRedactionPolicy(nil)
+	c.Check(Redact("password", "hunter2"), gc.Equals, "hunter2")
+	c.Check(Redact("password", Secret("hunter2")), gc.Equals, RedactedText)
+	c.Check(err.Message(), gc.Equals, "The charging 4111 1111 1111 1111 for ann@example.com failed")
+}
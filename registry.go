@@ -0,0 +1,169 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+var (
+	identicalDomains int32
+
+	// The FormatFunc of SetMissingDomainFunc.
+	missingDomainFunc atomic.Value
+)
+
+// SetMissingDomainFunc sets the function formatting the message of
+// errors whose domain is not defined, for instance to render them in
+// the style of the application, or to panic during development.
+// Use nil to restore the default "Domain missing: [domain:code] info".
+func SetMissingDomainFunc(fn FormatFunc) {
+	if fn == nil {
+		fn = domainMissing
+	}
+	missingDomainFunc.Store(fn)
+}
+
+func missingDomain() FormatFunc {
+	if fn, ok := missingDomainFunc.Load().(FormatFunc); ok {
+		return fn
+	}
+	return domainMissing
+}
+
+func domainMissing(err *Error) string {
+	return fmt.Sprintf("Domain missing: [%v:%d] %v",
+		err.Domain, err.Code, err.Info)
+}
+
+// SetIdenticalDomains controls whether defining a domain again with
+// Domain is allowed if the message formats are identical, in which case
+// it does nothing. This lets test suites of several packages register
+// the same domains. Otherwise, every redefinition panics.
+func SetIdenticalDomains(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&identicalDomains, v)
+}
+
+func identicalDomainsAllowed() bool {
+	return atomic.LoadInt32(&identicalDomains) != 0
+}
+
+// sameDomain reports whether two domains have the same message formats.
+func sameDomain(a, b DomainMap) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for code, text := range a {
+		if other, ok := b[code]; !ok || other != text {
+			return false
+		}
+	}
+	return true
+}
+
+// ReplaceDomain defines a domain, replacing its previous definition
+// if any, see Domain. Translations of the domain are kept.
+// Unlike UpdateDomain, it may change the options of the domain,
+// but errors formatted meanwhile may find the domain missing.
+// It is intended for tests.
+func ReplaceDomain(name string, domain DomainMap, opts ...DomainOption) {
+	undefine(name)
+	Domain(name, domain, opts...)
+}
+
+// RemoveDomain removes a domain, along with its translations,
+// overrides, catalog files and code names. It is intended for tests.
+func RemoveDomain(name string) {
+	undefine(name)
+	translations.Range(func(key, _ interface{}) bool {
+		if key.(localeKey).domain == name {
+			translations.Delete(key)
+		}
+		return true
+	})
+	localizersMu.Lock()
+	localizers.Delete(name)
+	localizersMu.Unlock()
+	overrides.Range(func(key, _ interface{}) bool {
+		if key.(Target).Domain == name {
+			overrides.Delete(key)
+		}
+		return true
+	})
+	sourcesMu.Lock()
+	delete(sources, name)
+	sourcesMu.Unlock()
+	unnameCodes(name)
+}
+
+// ResetDomains removes every domain, see RemoveDomain,
+// leaving only the domain of wrapped go errors.
+// It is intended for tests.
+func ResetDomains() {
+	domains.Range(func(name, _ interface{}) bool {
+		RemoveDomain(name.(string))
+		return true
+	})
+	defineGo()
+}
+
+// undefine removes the definition of a domain, but not its translations.
+func undefine(name string) {
+	domains.Delete(name)
+	defined.Delete(name)
+	domainFuncs.Delete(name)
+	domainKeys.Delete(name)
+	domainRanges.Delete(name)
+}
+
+// ListDomains returns the names of the defined domains, sorted.
+func ListDomains() []string {
+	var names []string
+	domains.Range(func(name, _ interface{}) bool {
+		names = append(names, name.(string))
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+// ListCodes returns a copy of the message formats of a domain,
+// reporting false unless it was defined with Domain.
+func ListCodes(name string) (DomainMap, bool) {
+	def, ok := defined.Load(name)
+	if !ok {
+		return nil, false
+	}
+	codes := make(DomainMap, len(def.(*definition).catalog))
+	for code, text := range def.(*definition).catalog {
+		codes[code] = text
+	}
+	return codes, true
+}
@@ -0,0 +1,162 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+func (t *TestSuite) TestIdenticalDomains(c *gc.C) {
+	Domain("identical", DomainMap{0: "Same"})
+	c.Check(func() { Domain("identical", DomainMap{0: "Same"}) }, gc.PanicMatches, "Domain conflict: identical")
+
+	SetIdenticalDomains(true)
+	defer SetIdenticalDomains(false)
+	Domain("identical", DomainMap{0: "Same"})
+	_, err := LoadDomainReader(strings.NewReader(
+		`{"domain": "identical", "codes": [{"code": 0, "message": "Same"}]}`), "json")
+	c.Check(err, gc.IsNil)
+	c.Check(func() { Domain("identical", DomainMap{0: "Other"}) }, gc.PanicMatches, "Domain conflict: identical")
+	c.Check(func() { Domain("identical", DomainMap{0: "Same", 1: "Other"}) }, gc.PanicMatches, "Domain conflict: identical")
+	c.Check(New(0, "identical", 0).Message(), gc.Equals, "Same")
+}
+
+func (t *TestSuite) TestRemoveDomain(c *gc.C) {
+	Domain("removed", DomainMap{0: "Removed"})
+	DomainLocale("removed", "de", DomainMap{0: "Entfernt"})
+	c.Assert(Override("removed", 0, "Overridden"), gc.IsNil)
+
+	ReplaceDomain("removed", DomainMap{0: "Replaced"})
+	ClearOverride("removed", 0)
+	err := New(0, "removed", 0)
+	c.Check(err.Message(), gc.Equals, "Replaced")
+	c.Check(err.MessageIn("de"), gc.Equals, "Entfernt")
+
+	c.Assert(Override("removed", 0, "Overridden"), gc.IsNil)
+	RemoveDomain("removed")
+	c.Check(err.Message(), gc.Equals, "Domain missing: [removed:0] map[]")
+	c.Check(err.MessageIn("de"), gc.Equals, "Domain missing: [removed:0] map[]")
+	c.Check(Overrides(), gc.HasLen, 0)
+	Domain("removed", DomainMap{0: "Again"})
+	c.Check(err.Message(), gc.Equals, "Again")
+	RemoveDomain("removed")
+}
+
+func (t *TestSuite) TestResetDomains(c *gc.C) {
+	// The suite relies on its domains, so they are restored afterwards.
+	var saved, defs sync.Map
+	domains.Range(func(name, fn interface{}) bool {
+		saved.Store(name, fn)
+		return true
+	})
+	defined.Range(func(name, def interface{}) bool {
+		defs.Store(name, def)
+		return true
+	})
+	defer func() {
+		saved.Range(func(name, fn interface{}) bool {
+			domains.Store(name, fn)
+			return true
+		})
+		defs.Range(func(name, def interface{}) bool {
+			defined.Store(name, def)
+			return true
+		})
+	}()
+	ResetDomains()
+	count := 0
+	domains.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	c.Check(count, gc.Equals, 1)
+	c.Check(Wrap(io.EOF).Message(), gc.Equals, "Error: EOF")
+	c.Check(NewError(EMyError0).Message(), gc.Equals, "Domain missing: [ergo:0] map[]")
+}
+
+func (t *TestSuite) TestConcurrentDomains(c *gc.C) {
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("concurrent%d", i)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			Domain(name, DomainMap{0: "Concurrent"})
+		}()
+		go func() {
+			defer wg.Done()
+			New(0, name, 0).Message()
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("concurrent%d", i)
+		c.Check(New(0, name, 0).Message(), gc.Equals, "Concurrent")
+		RemoveDomain(name)
+	}
+}
+
+func (t *TestSuite) TestList(c *gc.C) {
+	Domain("listed", DomainMap{0: "Zero {{.x}}", 1: "One"})
+	DomainFunc("listedfunc", func(err *Error) string { return "Func" })
+	defer RemoveDomain("listed")
+	defer RemoveDomain("listedfunc")
+
+	names := ListDomains()
+	c.Check(sort.StringsAreSorted(names), gc.Equals, true)
+	c.Check(names, gc.Not(gc.HasLen), 0)
+	found := 0
+	for _, name := range names {
+		if name == "go" || name == "listed" || name == "listedfunc" {
+			found++
+		}
+	}
+	c.Check(found, gc.Equals, 3)
+
+	codes, ok := ListCodes("listed")
+	c.Check(ok, gc.Equals, true)
+	c.Check(codes, gc.DeepEquals, DomainMap{0: "Zero {{.x}}", 1: "One"})
+	codes[0] = "Changed"
+	codes, _ = ListCodes("listed")
+	c.Check(codes[0], gc.Equals, "Zero {{.x}}")
+	_, ok = ListCodes("listedfunc")
+	c.Check(ok, gc.Equals, false)
+	_, ok = ListCodes("unlisted")
+	c.Check(ok, gc.Equals, false)
+}
+
+func (t *TestSuite) TestMissingDomainFunc(c *gc.C) {
+	SetMissingDomainFunc(func(err *Error) string {
+		return fmt.Sprintf("Something went wrong (%v-%d)", err.Domain, err.Code)
+	})
+	c.Check(New(0, "nowhere", 3).Message(), gc.Equals, "Something went wrong (nowhere-3)")
+	c.Check(NewError(EMyError0).Message(), gc.Not(gc.Matches), "Something.*")
+	SetMissingDomainFunc(nil)
+	c.Check(New(0, "nowhere", 3).Message(), gc.Equals, "Domain missing: [nowhere:3] map[]")
+}
@@ -0,0 +1,112 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	// The catalog files of each domain, see ReloadDomain.
+	sourcesMu sync.Mutex
+	sources   = make(map[string][]catalogSource)
+)
+
+func addSource(domain string, src catalogSource) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	for _, s := range sources[domain] {
+		if s.fsys == nil && src.fsys == nil && s.name == src.name {
+			return
+		}
+	}
+	sources[domain] = append(sources[domain], src)
+}
+
+// UpdateDomain replaces the message formats of a domain defined with
+// Domain, for instance with a catalog fetched from a remote service.
+// The formats are swapped atomically, so that concurrent calls to
+// Message() see either the old or the new ones.
+func UpdateDomain(name string, domain DomainMap) error {
+	if _, ok := defined.Load(name); !ok {
+		return fmt.Errorf("ergo: domain %v is not defined with Domain", name)
+	}
+	if err := checkRange(name, domain, reservedRange(name)); err != nil {
+		return err
+	}
+	tmpls, err := parseTemplates(name, funcsFor(name, "en"), domain)
+	if err != nil {
+		return fmt.Errorf("ergo: %v", err)
+	}
+	if err := checkKeys(name, tmpls, declaredKeys(name), true); err != nil {
+		return err
+	}
+	defined.Store(name, define(tmpls, domain))
+	return nil
+}
+
+// ReloadDomain reloads the catalogs of a domain, including its
+// translations, from the files they were loaded from by LoadDomainFile
+// and LoadDomainFS, so that long-running services can pick up edits
+// without restarting. Every file is read and checked before any is
+// applied: if one is invalid, the domain is left unchanged.
+func ReloadDomain(name string) error {
+	sourcesMu.Lock()
+	srcs := append([]catalogSource(nil), sources[name]...)
+	sourcesMu.Unlock()
+	if len(srcs) == 0 {
+		return fmt.Errorf("ergo: domain %v was not loaded from catalog files", name)
+	}
+	type compiled struct {
+		cat    *Catalog
+		domain DomainMap
+		tmpls  templates
+	}
+	var all []compiled
+	for _, src := range srcs {
+		cat, err := src.decode()
+		if err != nil {
+			return err
+		}
+		if cat.Domain != name {
+			return fmt.Errorf("%v: ergo: catalog of domain %v now defines %v", src.name, name, cat.Domain)
+		}
+		domain, tmpls, err := cat.compile()
+		if err != nil {
+			return fmt.Errorf("%v: %v", src.name, err)
+		}
+		all = append(all, compiled{cat, domain, tmpls})
+	}
+	for _, c := range all {
+		if c.cat.Locale != "" {
+			key := localeKey{domain: name, locale: normalizeLocale(c.cat.Locale)}
+			translations.Store(key, c.tmpls)
+		} else {
+			defined.Store(name, define(c.tmpls, c.domain))
+			c.cat.nameCodes()
+		}
+	}
+	return nil
+}
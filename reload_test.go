@@ -0,0 +1,92 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	gc "github.com/motain/gocheck"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing/fstest"
+)
+
+func (t *TestSuite) TestUpdateDomain(c *gc.C) {
+	Domain("update", DomainMap{0: "Before"})
+	err := New(0, "update", 0)
+	c.Check(err.Message(), gc.Equals, "Before")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			err.Message()
+		}
+	}()
+	c.Assert(UpdateDomain("update", DomainMap{0: "After", 1: "Added"}), gc.IsNil)
+	wg.Wait()
+	c.Check(err.Message(), gc.Equals, "After")
+	c.Check(New(0, "update", 1).Message(), gc.Equals, "Added")
+	_, werr := (&Wire{Domain: "update", Code: 1}).ToError()
+	c.Check(werr, gc.IsNil)
+
+	c.Check(UpdateDomain("update", DomainMap{0: "{{"}), gc.ErrorMatches, "ergo: template: .*")
+	c.Check(err.Message(), gc.Equals, "After")
+	c.Check(UpdateDomain("go", DomainMap{}), gc.ErrorMatches, "ergo: domain go is not defined with Domain")
+}
+
+func (t *TestSuite) TestReloadDomain(c *gc.C) {
+	dir := c.MkDir()
+	base := filepath.Join(dir, "reload.json")
+	write := func(path, doc string) {
+		c.Assert(os.WriteFile(path, []byte(doc), 0644), gc.IsNil)
+	}
+	write(base, `{"domain": "reload", "codes": [{"code": 0, "message": "Old"}]}`)
+	_, err := LoadDomainFile(base)
+	c.Assert(err, gc.IsNil)
+	fsys := fstest.MapFS{"reload.de.json": {Data: []byte(
+		`{"domain": "reload", "locale": "de", "codes": [{"code": 0, "message": "Alt"}]}`)}}
+	_, err = LoadDomainFS(fsys, "*.json")
+	c.Assert(err, gc.IsNil)
+	e := New(0, "reload", 0)
+	c.Check(e.Message(), gc.Equals, "Old")
+	c.Check(e.MessageIn("de"), gc.Equals, "Alt")
+
+	write(base, `{"domain": "reload", "codes": [{"code": 0, "message": "New"}]}`)
+	fsys["reload.de.json"].Data = []byte(
+		`{"domain": "reload", "locale": "de", "codes": [{"code": 0, "message": "Neu"}]}`)
+	c.Assert(ReloadDomain("reload"), gc.IsNil)
+	c.Check(e.Message(), gc.Equals, "New")
+	c.Check(e.MessageIn("de"), gc.Equals, "Neu")
+
+	// Nothing is applied unless every file is valid.
+	write(base, `{"domain": "reload", "codes": [{"code": 0, "message": "Newer"}]}`)
+	fsys["reload.de.json"].Data = []byte(`{"domain": "reload", "locale": "de", "codes": [{"code": 0, "message": "{{"}]}`)
+	c.Check(ReloadDomain("reload"), gc.ErrorMatches, "reload.de.json: ergo: template: .*")
+	c.Check(e.Message(), gc.Equals, "New")
+
+	write(base, `{"domain": "other", "codes": []}`)
+	c.Check(ReloadDomain("reload"), gc.ErrorMatches, ".*ergo: catalog of domain reload now defines other")
+	c.Check(ReloadDomain("ergo"), gc.ErrorMatches, "ergo: domain ergo was not loaded from catalog files")
+}
@@ -0,0 +1,116 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package render
+
+import (
+	"fmt"
+	"github.com/flaub/ergo"
+	"io"
+	"strings"
+)
+
+// Markdown writes an error chain as Markdown, outermost first,
+// for pasting into issue trackers and chat tools.
+// Each link has a heading, a table of its Info and a fenced code block
+// with its stack or context. Additional causes follow their link,
+// one heading level deeper.
+func Markdown(w io.Writer, err *ergo.Error) error {
+	var b strings.Builder
+	writeMarkdown(&b, err, 2)
+	_, werr := io.WriteString(w, b.String())
+	return werr
+}
+
+func writeMarkdown(b *strings.Builder, err *ergo.Error, level int) {
+	for link := err; link != nil; link = link.Inner {
+		if link != err {
+			b.WriteString("\n")
+		}
+		b.WriteString(strings.Repeat("#", level))
+		b.WriteString(" [" + link.Domain + ":" + ergo.CodeString(link.Domain, link.Code) + "] ")
+		b.WriteString(markdownLine(link.Message()))
+		b.WriteString("\n")
+		if keys := infoKeys(link.Info); len(keys) != 0 {
+			b.WriteString("\n| Key | Value |\n| --- | --- |\n")
+			for _, key := range keys {
+				value := fmt.Sprintf("%v", ergo.Redact(key, link.Info[key]))
+				b.WriteString("| " + markdownCell(key) + " | " + markdownCell(value) + " |\n")
+			}
+		}
+		context := link.Context
+		if link.Stack != nil {
+			context = link.Stack.String()
+		}
+		if context != "" {
+			writeFence(b, context)
+		}
+		if len(link.Causes) != 0 {
+			sub := level + 1
+			if sub > 6 {
+				sub = 6
+			}
+			for _, cause := range link.Causes {
+				b.WriteString("\n")
+				writeMarkdown(b, cause, sub)
+			}
+		}
+	}
+}
+
+// infoKeys returns the sorted keys of "info", except the
+// reserved keys which are already part of the message.
+func infoKeys(info ergo.ErrInfo) []string {
+	var keys []string
+	for _, key := range info.Keys() {
+		if !ergo.IsReserved(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// writeFence writes "text" as a fenced code block, with a fence longer
+// than any run of backticks within it.
+func writeFence(b *strings.Builder, text string) {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	b.WriteString("\n" + fence + "\n")
+	b.WriteString(text)
+	if !strings.HasSuffix(text, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString(fence + "\n")
+}
+
+func markdownLine(text string) string {
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+var cellReplacer = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+func markdownCell(text string) string {
+	return cellReplacer.Replace(text)
+}
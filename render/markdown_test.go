@@ -0,0 +1,76 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package render
+
+import (
+	"bytes"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+)
+
+func (t *TestSuite) TestMarkdown(c *gc.C) {
+	var buf bytes.Buffer
+	c.Assert(Markdown(&buf, newChain()), gc.IsNil)
+	c.Check(buf.String(), gc.Equals, `## [render:0] Outer x
+
+| Key | Value |
+| --- | --- |
+| name | x |
+
+`+"```"+`
+main.go:7
+	main.main
+`+"```"+`
+
+## [render:1] Inner
+
+`+"```"+`
+main.cpp:42
+`+"```"+`
+`)
+}
+
+func (t *TestSuite) TestMarkdownEscaping(c *gc.C) {
+	err := ergo.Join(
+		&ergo.Error{Domain: "render", Code: 1, Info: ergo.ErrInfo{"a|b": "line 1\nline 2"}},
+		&ergo.Error{Domain: "render", Code: 1, Context: "has ``` fence"},
+	)
+	err.Stack = nil
+	var buf bytes.Buffer
+	c.Assert(Markdown(&buf, err), gc.IsNil)
+	c.Check(buf.String(), gc.Equals, `## [go:0] Error: Inner; Inner
+
+### [render:1] Inner
+
+| Key | Value |
+| --- | --- |
+| a\|b | line 1<br>line 2 |
+
+### [render:1] Inner
+
+`+"````"+`
+has `+"```"+` fence
+`+"````"+`
+`)
+}
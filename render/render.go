@@ -0,0 +1,136 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package render prints ergo errors for humans,
+// using ANSI colors when writing to a terminal.
+package render
+
+import (
+	"github.com/flaub/ergo"
+	"io"
+	"os"
+)
+
+// ANSI escape sequences used for each part of an error.
+const (
+	colorCode    = "\x1b[1;31m"
+	colorMessage = "\x1b[1m"
+	colorContext = "\x1b[2m"
+	colorReset   = "\x1b[0m"
+)
+
+// Renderer prints error chains to a writer.
+type Renderer struct {
+	w io.Writer
+
+	// Color enables ANSI escape sequences.
+	Color bool
+}
+
+// New creates a renderer for "w".
+// Colors are enabled if "w" is a terminal,
+// unless the NO_COLOR environment variable is set or TERM is "dumb".
+func New(w io.Writer) *Renderer {
+	return &Renderer{w: w, Color: IsTerminal(w)}
+}
+
+// IsTerminal reports whether "w" is a terminal which supports colors.
+func IsTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Fprint renders "err" to "w", see Renderer.Render.
+func Fprint(w io.Writer, err *ergo.Error) error {
+	return New(w).Render(err)
+}
+
+// Print renders "err" to standard error.
+func Print(err *ergo.Error) error {
+	return Fprint(os.Stderr, err)
+}
+
+// Render prints the entire chain, innermost first,
+// in the same layout as Error().
+func (r *Renderer) Render(err *ergo.Error) error {
+	p := printer{Renderer: r}
+	p.chain(err)
+	return p.err
+}
+
+// printer remembers the first write error.
+type printer struct {
+	*Renderer
+	err error
+}
+
+func (p *printer) write(color, text string) {
+	if p.err != nil || text == "" {
+		return
+	}
+	if p.Color && color != "" {
+		text = color + text + colorReset
+	}
+	_, p.err = io.WriteString(p.w, text)
+}
+
+func (p *printer) chain(err *ergo.Error) {
+	if err.Inner != nil {
+		p.chain(err.Inner)
+		p.write("", "\n")
+	}
+	for _, cause := range err.Causes {
+		p.chain(cause)
+		p.write("", "\n")
+	}
+	p.entry(err)
+}
+
+func (p *printer) entry(err *ergo.Error) {
+	p.write(colorCode, "["+err.Domain+":"+ergo.CodeString(err.Domain, err.Code)+"]")
+	p.write("", " ")
+	p.write(colorMessage, err.Message())
+	p.write("", "\n")
+	if err.Stack != nil {
+		for _, frame := range err.Stack.Frames() {
+			p.write(colorContext, frame.String())
+		}
+		return
+	}
+	if err.Context != "" {
+		p.write(colorContext, err.Context)
+		if err.Context[len(err.Context)-1] != '\n' {
+			p.write("", "\n")
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package render
+
+import (
+	"bytes"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("render", ergo.DomainMap{
+		0: "Outer {{.name}}",
+		1: "Inner",
+	})
+}
+
+func newChain() *ergo.Error {
+	inner := &ergo.Error{Domain: "render", Code: 1, Context: "main.cpp:42"}
+	outer := &ergo.Error{
+		Domain: "render",
+		Code:   0,
+		Info:   ergo.ErrInfo{"name": "x"},
+		Stack:  ergo.NewStack([]ergo.Frame{{Function: "main.main", File: "main.go", Line: 7}}),
+	}
+	return ergo.Chain(inner, outer).(*ergo.Error)
+}
+
+func (t *TestSuite) TestPlain(c *gc.C) {
+	var buf bytes.Buffer
+	err := newChain()
+	c.Check(Fprint(&buf, err), gc.IsNil)
+	c.Check(buf.String(), gc.Equals, err.Error())
+}
+
+func (t *TestSuite) TestColor(c *gc.C) {
+	var buf bytes.Buffer
+	r := New(&buf)
+	c.Check(r.Color, gc.Equals, false)
+	r.Color = true
+	c.Check(r.Render(newChain()), gc.IsNil)
+	c.Check(buf.String(), gc.Equals,
+		"\x1b[1;31m[render:1]\x1b[0m \x1b[1mInner\x1b[0m\n"+
+			"\x1b[2mmain.cpp:42\x1b[0m\n"+
+			"\n"+
+			"\x1b[1;31m[render:0]\x1b[0m \x1b[1mOuter x\x1b[0m\n"+
+			"\x1b[2mmain.go:7\n\tmain.main\n\x1b[0m")
+}
@@ -0,0 +1,123 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"github.com/flaub/ergo"
+	"sync"
+	"time"
+)
+
+// Info keys of the summaries passed on by an Aggregator.
+const (
+	CountKey = "count"
+	FirstKey = "first_seen"
+	LastKey  = "last_seen"
+)
+
+// Aggregator is a Reporter which passes on summaries instead of errors.
+// Errors are grouped by fingerprint, see ergo.Fingerprint, and every
+// interval a summary of each group is passed on: a copy of the first
+// error of the group, keeping its stack as a sample, whose Info holds
+// the number of errors in CountKey and the times of the first and last
+// ones in FirstKey and LastKey.
+type Aggregator struct {
+	next   ergo.Reporter
+	mu     sync.Mutex
+	groups map[string]*Entry
+	order  []*Entry
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewAggregator starts an aggregator passing summaries on to "next"
+// every "interval". Close must be called to stop it.
+func NewAggregator(next ergo.Reporter, interval time.Duration) *Aggregator {
+	a := &Aggregator{
+		next:   next,
+		groups: make(map[string]*Entry),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go a.run(interval)
+	return a
+}
+
+// Report implements ergo.Reporter.
+func (a *Aggregator) Report(err *ergo.Error) {
+	now := time.Now()
+	key := fingerprint(err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	entry, ok := a.groups[key]
+	if !ok {
+		entry = &Entry{Error: snapshot(err), First: now, key: key}
+		a.groups[key] = entry
+		a.order = append(a.order, entry)
+	}
+	entry.Count++
+	entry.Last = now
+}
+
+// Flush passes on the summaries of the errors reported since the
+// last flush, in the order their groups first occurred.
+func (a *Aggregator) Flush() {
+	a.mu.Lock()
+	order := a.order
+	a.groups = make(map[string]*Entry)
+	a.order = nil
+	a.mu.Unlock()
+	for _, entry := range order {
+		summary := entry.Error
+		summary.Info[CountKey] = entry.Count
+		summary.Info[FirstKey] = entry.First
+		summary.Info[LastKey] = entry.Last
+		a.next.Report(summary)
+	}
+}
+
+// Close stops the aggregator and flushes the pending summaries.
+func (a *Aggregator) Close() {
+	select {
+	case <-a.stop:
+	default:
+		close(a.stop)
+	}
+	<-a.done
+	a.Flush()
+}
+
+func (a *Aggregator) run(interval time.Duration) {
+	defer close(a.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.Flush()
+		case <-a.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,72 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"time"
+)
+
+func (t *TestSuite) TestAggregator(c *gc.C) {
+	next := &collector{}
+	a := NewAggregator(next, time.Hour)
+	for i := 0; i < 3; i++ {
+		a.Report(ergo.New(0, "report", EBroken))
+	}
+	a.Report(ergo.New(0, "report", ENotFound, "name", "x"))
+	c.Check(next.received(), gc.HasLen, 0)
+
+	a.Flush()
+	errs := next.received()
+	c.Assert(errs, gc.HasLen, 2)
+	c.Check(errs[0].Code, gc.Equals, EBroken)
+	c.Check(errs[0].Info[CountKey], gc.Equals, 3)
+	c.Check(errs[0].Stack, gc.NotNil)
+	first := errs[0].Info[FirstKey].(time.Time)
+	last := errs[0].Info[LastKey].(time.Time)
+	c.Check(last.Before(first), gc.Equals, false)
+	c.Check(errs[1].Message(), gc.Equals, "The x was not found")
+	c.Check(errs[1].Info[CountKey], gc.Equals, 1)
+
+	a.Report(ergo.New(0, "report", EBroken))
+	a.Close()
+	errs = next.received()
+	c.Assert(errs, gc.HasLen, 3)
+	c.Check(errs[2].Info[CountKey], gc.Equals, 1)
+	a.Close()
+}
+
+func (t *TestSuite) TestAggregatorInterval(c *gc.C) {
+	next := &collector{}
+	a := NewAggregator(next, 10*time.Millisecond)
+	defer a.Close()
+	a.Report(ergo.New(0, "report", EBroken))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(next.received()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	c.Check(next.received(), gc.HasLen, 1)
+}
@@ -0,0 +1,116 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"fmt"
+	"github.com/flaub/ergo"
+	"sync"
+	"time"
+)
+
+// RepeatedKey is the Info key of summaries holding the number of
+// suppressed repeats.
+const RepeatedKey = "repeated"
+
+// Dedup is a Reporter which suppresses repeated errors.
+// The first error of each fingerprint, see ergo.Fingerprint,
+// is passed on at once; repeats within the following window are
+// only counted. When the window ends, a summary is passed on:
+// a copy of the last repeat, whose message is followed by
+// "(seen N more times)" and whose Info holds N in RepeatedKey.
+// Repeats are copied when reported, so that callers may keep modifying
+// or release them.
+type Dedup struct {
+	next   ergo.Reporter
+	window time.Duration
+	mu     sync.Mutex
+	seen   map[string]*repeats
+}
+
+type repeats struct {
+	last  *ergo.Error
+	count int
+	timer *time.Timer
+}
+
+// NewDedup creates a reporter suppressing repeats within "window"
+// before passing errors on to "next".
+func NewDedup(next ergo.Reporter, window time.Duration) *Dedup {
+	return &Dedup{next: next, window: window, seen: make(map[string]*repeats)}
+}
+
+// Report implements ergo.Reporter.
+func (d *Dedup) Report(err *ergo.Error) {
+	key := fingerprint(err)
+	d.mu.Lock()
+	if w, ok := d.seen[key]; ok {
+		w.last = snapshot(err)
+		w.count++
+		d.mu.Unlock()
+		return
+	}
+	w := &repeats{}
+	w.timer = time.AfterFunc(d.window, func() { d.expire(key, w) })
+	d.seen[key] = w
+	d.mu.Unlock()
+	d.next.Report(err)
+}
+
+// Flush ends every window at once, passing on their summaries.
+func (d *Dedup) Flush() {
+	d.mu.Lock()
+	seen := d.seen
+	d.seen = make(map[string]*repeats)
+	d.mu.Unlock()
+	for _, w := range seen {
+		w.timer.Stop()
+		d.summarize(w)
+	}
+}
+
+func (d *Dedup) expire(key string, w *repeats) {
+	d.mu.Lock()
+	if d.seen[key] != w {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.seen, key)
+	d.mu.Unlock()
+	d.summarize(w)
+}
+
+func (d *Dedup) summarize(w *repeats) {
+	if w.count == 0 {
+		return
+	}
+	summary := w.last
+	times := "times"
+	if w.count == 1 {
+		times = "time"
+	}
+	summary.Info["_msg"] = fmt.Sprintf("%v (seen %d more %v)", summary.Message(), w.count, times)
+	summary.Info[RepeatedKey] = w.count
+	d.next.Report(summary)
+}
@@ -0,0 +1,96 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"sync"
+	"time"
+)
+
+// collector is a reporter remembering the errors it received.
+type collector struct {
+	mu   sync.Mutex
+	errs []*ergo.Error
+}
+
+func (r *collector) Report(err *ergo.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+}
+
+func (r *collector) received() []*ergo.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*ergo.Error(nil), r.errs...)
+}
+
+func (t *TestSuite) TestDedup(c *gc.C) {
+	next := &collector{}
+	d := NewDedup(next, time.Hour)
+	for i := 0; i < 3; i++ {
+		err := ergo.New(0, "report", ENotFound, "name", "db")
+		d.Report(err)
+		// Repeats are copied, so callers may reuse them.
+		err.With("name", "cache")
+		if i > 0 {
+			err.Release()
+		}
+	}
+	d.Report(ergo.New(0, "report", EBroken))
+	c.Assert(next.received(), gc.HasLen, 2)
+
+	d.Flush()
+	errs := next.received()
+	c.Assert(errs, gc.HasLen, 3)
+	summary := errs[2]
+	c.Check(summary.Code, gc.Equals, ENotFound)
+	c.Check(summary.Message(), gc.Equals, "The db was not found (seen 2 more times)")
+	c.Check(summary.Info[RepeatedKey], gc.Equals, 2)
+
+	// A new window starts once the previous one has ended.
+	d.Report(ergo.New(0, "report", ENotFound, "name", "db"))
+	c.Check(next.received(), gc.HasLen, 4)
+}
+
+func (t *TestSuite) TestDedupWindow(c *gc.C) {
+	next := &collector{}
+	d := NewDedup(next, 20*time.Millisecond)
+	d.Report(ergo.New(0, "report", EBroken))
+	d.Report(ergo.New(0, "report", EBroken))
+	c.Check(next.received(), gc.HasLen, 1)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(next.received()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	errs := next.received()
+	c.Assert(errs, gc.HasLen, 2)
+	c.Check(errs[1].Message(), gc.Equals, "Broken (seen 1 more time)")
+
+	d.Report(ergo.New(0, "report", EBroken))
+	c.Check(next.received(), gc.HasLen, 3)
+}
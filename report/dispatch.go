@@ -0,0 +1,172 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"github.com/flaub/ergo"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DropPolicy selects the error discarded when the queue is full.
+type DropPolicy int
+
+const (
+	// DropNewest discards the error being reported.
+	DropNewest DropPolicy = iota
+
+	// DropOldest discards the oldest queued error to make room.
+	DropOldest
+)
+
+// DefaultQueueSize is the queue size of dispatchers without one.
+const DefaultQueueSize = 1024
+
+// Options customizes a Dispatcher.
+type Options struct {
+	// QueueSize bounds the number of errors waiting to be sent.
+	// If 0, DefaultQueueSize is used.
+	QueueSize int
+
+	// Drop selects the error discarded when the queue is full.
+	Drop DropPolicy
+
+	// Retries is the number of additional attempts made
+	// when sending an error fails.
+	Retries int
+
+	// Backoff is the delay before the first retry,
+	// doubled for every subsequent one up to MaxBackoff.
+	Backoff time.Duration
+
+	// MaxBackoff bounds the delay between retries.
+	// If 0, DefaultMaxBackoff is used.
+	MaxBackoff time.Duration
+
+	// OnDrop, if set, is called with every error which is discarded,
+	// either because the queue is full or because every attempt failed.
+	OnDrop func(err *ergo.Error)
+}
+
+// Dispatcher is a Reporter which never blocks the caller.
+// Errors are queued and sent to a Sink by a background goroutine,
+// one at a time and in order.
+type Dispatcher struct {
+	sink    Sink
+	opts    Options
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan *ergo.Error
+	done    chan struct{}
+	dropped uint64
+}
+
+// NewDispatcher starts a dispatcher sending errors to "sink".
+// Close must be called to stop it.
+func NewDispatcher(sink Sink, opts Options) *Dispatcher {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	d := &Dispatcher{
+		sink:  sink,
+		opts:  opts,
+		queue: make(chan *ergo.Error, opts.QueueSize),
+		done:  make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Report implements ergo.Reporter.
+// Errors are copied before they are queued, so that callers may keep
+// modifying or release them. Errors reported after Close are dropped.
+func (d *Dispatcher) Report(err *ergo.Error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		d.drop(err)
+		return
+	}
+	err = snapshot(err)
+	for {
+		select {
+		case d.queue <- err:
+			return
+		default:
+		}
+		if d.opts.Drop == DropNewest {
+			d.drop(err)
+			return
+		}
+		select {
+		case oldest := <-d.queue:
+			d.drop(oldest)
+		default:
+		}
+	}
+}
+
+// Dropped returns the number of errors discarded so far.
+func (d *Dispatcher) Dropped() uint64 {
+	return atomic.LoadUint64(&d.dropped)
+}
+
+// Close sends the errors still queued and stops the dispatcher.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+	<-d.done
+}
+
+func (d *Dispatcher) drop(err *ergo.Error) {
+	atomic.AddUint64(&d.dropped, 1)
+	if d.opts.OnDrop != nil {
+		d.opts.OnDrop(err)
+	}
+}
+
+func (d *Dispatcher) run() {
+	defer close(d.done)
+	for err := range d.queue {
+		d.send(err)
+	}
+}
+
+func (d *Dispatcher) send(err *ergo.Error) {
+	for attempt := 0; ; attempt++ {
+		if d.sink.Send(err) == nil {
+			return
+		}
+		if attempt >= d.opts.Retries {
+			d.drop(err)
+			return
+		}
+		time.Sleep(retryDelay(attempt, d.opts.Backoff, d.opts.MaxBackoff))
+	}
+}
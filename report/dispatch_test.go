@@ -0,0 +1,138 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"errors"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"sync"
+	"time"
+)
+
+// recorder is a sink remembering the codes it received.
+type recorder struct {
+	mu    sync.Mutex
+	codes []ergo.ErrCode
+	fails int
+	block chan struct{}
+}
+
+func (r *recorder) Send(err *ergo.Error) error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fails > 0 {
+		r.fails--
+		return errors.New("unavailable")
+	}
+	r.codes = append(r.codes, err.Code)
+	return nil
+}
+
+func newError(code int) *ergo.Error {
+	return &ergo.Error{Domain: "report", Code: ergo.ErrCode(code)}
+}
+
+func (t *TestSuite) TestDispatcher(c *gc.C) {
+	sink := &recorder{}
+	d := NewDispatcher(sink, Options{})
+	for i := 0; i < 10; i++ {
+		d.Report(newError(i))
+	}
+	d.Close()
+	c.Check(sink.codes, gc.HasLen, 10)
+	c.Check(sink.codes[9], gc.Equals, ergo.ErrCode(9))
+	c.Check(d.Dropped(), gc.Equals, uint64(0))
+
+	d.Report(newError(10))
+	c.Check(d.Dropped(), gc.Equals, uint64(1))
+	d.Close()
+}
+
+func (t *TestSuite) TestDropPolicies(c *gc.C) {
+	for _, policy := range []DropPolicy{DropNewest, DropOldest} {
+		sink := &recorder{block: make(chan struct{})}
+		var dropped []ergo.ErrCode
+		d := NewDispatcher(sink, Options{
+			QueueSize: 2,
+			Drop:      policy,
+			OnDrop:    func(err *ergo.Error) { dropped = append(dropped, err.Code) },
+		})
+		// The first error is held by the blocked sink.
+		d.Report(newError(0))
+		for len(d.queue) != 0 {
+			time.Sleep(time.Millisecond)
+		}
+		for i := 1; i <= 4; i++ {
+			d.Report(newError(i))
+		}
+		close(sink.block)
+		d.Close()
+		c.Check(d.Dropped(), gc.Equals, uint64(2))
+		if policy == DropNewest {
+			c.Check(sink.codes, gc.DeepEquals, []ergo.ErrCode{0, 1, 2})
+			c.Check(dropped, gc.DeepEquals, []ergo.ErrCode{3, 4})
+		} else {
+			c.Check(sink.codes, gc.DeepEquals, []ergo.ErrCode{0, 3, 4})
+			c.Check(dropped, gc.DeepEquals, []ergo.ErrCode{1, 2})
+		}
+	}
+}
+
+func (t *TestSuite) TestRetries(c *gc.C) {
+	sink := &recorder{fails: 2}
+	d := NewDispatcher(sink, Options{Retries: 2, Backoff: time.Millisecond})
+	d.Report(newError(1))
+	d.Close()
+	c.Check(sink.codes, gc.DeepEquals, []ergo.ErrCode{1})
+	c.Check(d.Dropped(), gc.Equals, uint64(0))
+
+	sink = &recorder{fails: 3}
+	d = NewDispatcher(sink, Options{Retries: 1})
+	d.Report(newError(1))
+	d.Report(newError(2))
+	d.Close()
+	c.Check(sink.codes, gc.DeepEquals, []ergo.ErrCode{2})
+	c.Check(d.Dropped(), gc.Equals, uint64(1))
+}
+
+func (t *TestSuite) TestDispatcherCopies(c *gc.C) {
+	release := make(chan struct{})
+	var names []interface{}
+	d := NewDispatcher(SinkFunc(func(err *ergo.Error) error {
+		<-release
+		names = append(names, err.Info["name"])
+		return nil
+	}), Options{})
+	err := ergo.New(0, "report", ENotFound, "name", "db")
+	d.Report(err)
+	err.With("name", "cache")
+	err.Release()
+	close(release)
+	d.Close()
+	c.Check(names, gc.DeepEquals, []interface{}{"db"})
+}
@@ -0,0 +1,150 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"encoding/json"
+	"github.com/flaub/ergo"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DebugPath is the conventional path of the Recorder handler.
+const DebugPath = "/debug/ergo"
+
+// Entry is an error kept by a Recorder.
+type Entry struct {
+	Error *ergo.Error
+
+	// The number of times an error with the same fingerprint
+	// was recorded while this entry was kept, see ergo.Fingerprint.
+	Count int
+
+	// The times of the first and last occurrences.
+	First time.Time
+	Last  time.Time
+
+	key string
+}
+
+// Recorder is a flight recorder keeping the most recent errors,
+// for live inspection during incidents.
+// Repeated errors with the same fingerprint share a single entry.
+// It is a Reporter, and may also be installed as a hook:
+//
+//	rec := report.NewRecorder(100)
+//	ergo.AddHook(rec.Report)
+//	http.Handle(report.DebugPath, rec)
+type Recorder struct {
+	mu      sync.Mutex
+	size    int
+	entries []*Entry
+	total   uint64
+}
+
+// NewRecorder creates a recorder keeping at most "size" entries.
+func NewRecorder(size int) *Recorder {
+	return &Recorder{size: size}
+}
+
+// Report implements ergo.Reporter.
+// A copy of the error is kept, so that releasing it is safe.
+func (r *Recorder) Report(err *ergo.Error) {
+	now := time.Now()
+	key := fingerprint(err)
+	sample := snapshot(err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.total++
+	entry := &Entry{First: now}
+	for i, e := range r.entries {
+		if e.key == key {
+			entry = e
+			r.entries = append(r.entries[:i], r.entries[i+1:]...)
+			break
+		}
+	}
+	entry.Error = sample
+	entry.Count++
+	entry.Last = now
+	entry.key = key
+	if len(r.entries) >= r.size && r.size > 0 {
+		r.entries = r.entries[1:]
+	}
+	if r.size > 0 {
+		r.entries = append(r.entries, entry)
+	}
+}
+
+// Entries returns the kept entries, most recent first.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := make([]Entry, len(r.entries))
+	for i, e := range r.entries {
+		entries[len(entries)-1-i] = *e
+	}
+	return entries
+}
+
+// Total returns the number of errors recorded so far,
+// including the ones no longer kept.
+func (r *Recorder) Total() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.total
+}
+
+// Reset discards every entry.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+	r.total = 0
+}
+
+type jsonEntry struct {
+	Count int
+	First time.Time
+	Last  time.Time
+	Error *ergo.Wire
+}
+
+// ServeHTTP writes the entries as JSON, most recent first.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	entries := r.Entries()
+	doc := struct {
+		Total   uint64
+		Entries []jsonEntry
+	}{Total: r.Total(), Entries: make([]jsonEntry, len(entries))}
+	for i, e := range entries {
+		doc.Entries[i] = jsonEntry{e.Count, e.First, e.Last, e.Error.ToWire()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(doc)
+}
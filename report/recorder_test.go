@@ -0,0 +1,80 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"encoding/json"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"net/http/httptest"
+)
+
+func (t *TestSuite) TestRecorder(c *gc.C) {
+	rec := NewRecorder(2)
+	first := ergo.New(0, "report", ENotFound, "name", "a")
+	rec.Report(first)
+	rec.Report(ergo.New(0, "report", EBroken))
+	rec.Report(ergo.New(0, "report", ENotFound, "name", "b"))
+	first.Release()
+
+	entries := rec.Entries()
+	c.Assert(entries, gc.HasLen, 2)
+	c.Check(entries[0].Error.Info["name"], gc.Equals, "b")
+	c.Check(entries[0].Count, gc.Equals, 2)
+	c.Check(entries[0].First.After(entries[0].Last), gc.Equals, false)
+	c.Check(entries[1].Error.Code, gc.Equals, EBroken)
+	c.Check(entries[1].Count, gc.Equals, 1)
+
+	rec.Report(&ergo.Error{Domain: "report", Code: 7})
+	entries = rec.Entries()
+	c.Assert(entries, gc.HasLen, 2)
+	c.Check(entries[0].Error.Code, gc.Equals, ergo.ErrCode(7))
+	c.Check(entries[1].Error.Code, gc.Equals, ENotFound)
+	c.Check(rec.Total(), gc.Equals, uint64(4))
+
+	rec.Reset()
+	c.Check(rec.Entries(), gc.HasLen, 0)
+	c.Check(rec.Total(), gc.Equals, uint64(0))
+}
+
+func (t *TestSuite) TestRecorderHandler(c *gc.C) {
+	rec := NewRecorder(10)
+	rec.Report(ergo.New(0, "report", ENotFound, "name", "a", ergo.WithStackDepth(0)))
+
+	w := httptest.NewRecorder()
+	rec.ServeHTTP(w, httptest.NewRequest("GET", DebugPath, nil))
+	c.Check(w.Header().Get("Content-Type"), gc.Equals, "application/json")
+	var doc struct {
+		Total   int
+		Entries []struct {
+			Count int
+			Error *ergo.Wire
+		}
+	}
+	c.Assert(json.Unmarshal(w.Body.Bytes(), &doc), gc.IsNil)
+	c.Check(doc.Total, gc.Equals, 1)
+	c.Assert(doc.Entries, gc.HasLen, 1)
+	c.Check(doc.Entries[0].Count, gc.Equals, 1)
+	c.Check(doc.Entries[0].Error.Info["name"], gc.Equals, "a")
+}
@@ -0,0 +1,123 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package report provides implementations of ergo.Reporter.
+//
+// Reporters which may fail, such as the ones sending errors over the
+// network, implement Sink instead, and are driven by a Dispatcher
+// which queues errors, retries failures and drops errors when full.
+package report
+
+import (
+	"encoding/json"
+	"github.com/flaub/ergo"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBackoff is the longest delay between retries
+// of reporters without a MaxBackoff.
+const DefaultMaxBackoff = 30 * time.Second
+
+// Sink is a destination for errors which may fail.
+type Sink interface {
+	Send(err *ergo.Error) error
+}
+
+// SinkFunc adapts a function to Sink.
+type SinkFunc func(err *ergo.Error) error
+
+// Send implements Sink.
+func (fn SinkFunc) Send(err *ergo.Error) error {
+	return fn(err)
+}
+
+// Nop is a reporter which discards every error.
+var Nop ergo.Reporter = nop{}
+
+type nop struct{}
+
+func (nop) Report(*ergo.Error) {}
+
+// JSON reports errors as lines of JSON, see ergo.Wire.
+// It is both a Reporter, which ignores write errors, and a Sink.
+type JSON struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSON creates a reporter writing to "w".
+func NewJSON(w io.Writer) *JSON {
+	return &JSON{enc: json.NewEncoder(w)}
+}
+
+// Stdout creates a reporter writing JSON lines to standard output.
+func Stdout() *JSON {
+	return NewJSON(os.Stdout)
+}
+
+// Send implements Sink.
+func (r *JSON) Send(err *ergo.Error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(err.ToWire())
+}
+
+// Report implements ergo.Reporter.
+func (r *JSON) Report(err *ergo.Error) {
+	r.Send(err)
+}
+
+// fingerprint returns a map key of the fingerprint of an error.
+func fingerprint(err *ergo.Error) string {
+	return strings.Join(ergo.Fingerprint(err), "\x00")
+}
+
+// retryDelay returns the delay before a retry: "backoff" doubled for
+// every previous retry, up to "max", or DefaultMaxBackoff if 0.
+func retryDelay(retry int, backoff, max time.Duration) time.Duration {
+	if max <= 0 {
+		max = DefaultMaxBackoff
+	}
+	for i := 0; i < retry && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		return max
+	}
+	return backoff
+}
+
+// snapshot copies an error along with its Info,
+// so that it can be kept after the error is released or modified.
+func snapshot(err *ergo.Error) *ergo.Error {
+	copy := *err
+	copy.Info = make(ergo.ErrInfo, len(err.Info))
+	for key, value := range err.Info {
+		copy.Info[key] = value
+	}
+	return &copy
+}
@@ -0,0 +1,80 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"bytes"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+	EBroken
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("report", ergo.DomainMap{
+		ENotFound: "The {{.name}} was not found",
+		EBroken:   "Broken",
+	})
+}
+
+func (t *TestSuite) TestJSON(c *gc.C) {
+	var buf bytes.Buffer
+	reporter := NewJSON(&buf)
+	reporter.Report(ergo.New(0, "report", ENotFound, "name", "x", ergo.WithStackDepth(0)))
+	c.Check(reporter.Send(&ergo.Error{Domain: "report", Code: EBroken}), gc.IsNil)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	c.Assert(lines, gc.HasLen, 2)
+	c.Check(lines[0], gc.Equals, `{"Version":1,"Domain":"report","Code":0,"Info":{"name":"x"}}`)
+
+	restored, err := ergo.FromJSON([]byte(lines[1]))
+	c.Assert(err, gc.IsNil)
+	c.Check(restored.Message(), gc.Equals, "Broken")
+
+	Nop.Report(restored)
+}
+
+func (t *TestSuite) TestRetryDelay(c *gc.C) {
+	c.Check(retryDelay(0, time.Second, time.Minute), gc.Equals, time.Second)
+	c.Check(retryDelay(3, time.Second, time.Minute), gc.Equals, 8*time.Second)
+	c.Check(retryDelay(10, time.Second, time.Minute), gc.Equals, time.Minute)
+	c.Check(retryDelay(1000, time.Second, time.Minute), gc.Equals, time.Minute)
+	c.Check(retryDelay(0, time.Hour, time.Minute), gc.Equals, time.Minute)
+	c.Check(retryDelay(1000, time.Second, 0), gc.Equals, DefaultMaxBackoff)
+	c.Check(retryDelay(5, 0, 0), gc.Equals, time.Duration(0))
+}
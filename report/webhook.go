@@ -0,0 +1,290 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/flaub/ergo"
+	"github.com/flaub/ergo/httperr"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Format selects the document posted for each error.
+type Format int
+
+const (
+	// FormatWire posts errors as ergo JSON, see ergo.Wire.
+	FormatWire Format = iota
+
+	// FormatProblem posts errors as problem details, see httperr.Problem.
+	FormatProblem
+)
+
+const (
+	// DefaultBatchSize is the batch size of webhooks without one.
+	DefaultBatchSize = 16
+
+	// DefaultFlushInterval is the flush interval of webhooks without one.
+	DefaultFlushInterval = 5 * time.Second
+
+	// DefaultBatchQueue is the number of batches waiting to be posted
+	// by webhooks without a queue size.
+	DefaultBatchQueue = 16
+)
+
+// WebhookOptions customizes a Webhook.
+type WebhookOptions struct {
+	// Format selects the document posted for each error.
+	Format Format
+
+	// Match selects the errors which are posted.
+	// If nil, every error is posted.
+	Match func(err *ergo.Error) bool
+
+	// BatchSize is the maximum number of errors posted at once.
+	// If 0, DefaultBatchSize is used.
+	BatchSize int
+
+	// FlushInterval is the longest time an error waits for its
+	// batch to fill. If 0, DefaultFlushInterval is used.
+	FlushInterval time.Duration
+
+	// QueueSize bounds the number of full batches waiting to be posted.
+	// If 0, DefaultBatchQueue is used.
+	QueueSize int
+
+	// Retries is the number of additional attempts made when posting
+	// fails with a network error, 429 or a 5xx status.
+	Retries int
+
+	// Backoff is the delay before the first retry,
+	// doubled for every subsequent one up to MaxBackoff.
+	Backoff time.Duration
+
+	// MaxBackoff bounds the delay between retries.
+	// If 0, DefaultMaxBackoff is used.
+	MaxBackoff time.Duration
+
+	// Client posts the batches. If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// Header is added to every request, for instance to authenticate.
+	Header http.Header
+
+	// OnDrop, if set, is called with every error which is discarded,
+	// either because the queue is full or because posting failed.
+	OnDrop func(err *ergo.Error)
+}
+
+// Codes returns a predicate for WebhookOptions.Match selecting errors
+// whose chain holds one of "codes" of "domain", see ergo.Code.
+func Codes(domain string, codes ...ergo.ErrCode) func(*ergo.Error) bool {
+	return func(err *ergo.Error) bool {
+		for _, code := range codes {
+			if errors.Is(err, ergo.Code(domain, code)) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Webhook is a Reporter which posts errors to a URL.
+// Errors are collected into batches, which are posted as a JSON array
+// of documents once full or when the flush interval elapses.
+// Reporting never blocks the caller.
+type Webhook struct {
+	url     string
+	opts    WebhookOptions
+	mu      sync.Mutex
+	closed  bool
+	batch   []*ergo.Error
+	queue   chan []*ergo.Error
+	done    chan struct{}
+	dropped uint64
+}
+
+// NewWebhook starts a webhook posting errors to "url".
+// Close must be called to stop it.
+func NewWebhook(url string, opts WebhookOptions) *Webhook {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultBatchQueue
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	w := &Webhook{
+		url:   url,
+		opts:  opts,
+		queue: make(chan []*ergo.Error, opts.QueueSize),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Report implements ergo.Reporter.
+// Errors are copied before they are batched, so that callers may keep
+// modifying or release them. Errors reported after Close are dropped.
+func (w *Webhook) Report(err *ergo.Error) {
+	if w.opts.Match != nil && !w.opts.Match(err) {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		w.drop(err)
+		return
+	}
+	w.batch = append(w.batch, snapshot(err))
+	if len(w.batch) < w.opts.BatchSize {
+		return
+	}
+	select {
+	case w.queue <- w.batch:
+	default:
+		for _, err := range w.batch {
+			w.drop(err)
+		}
+	}
+	w.batch = nil
+}
+
+// Dropped returns the number of errors discarded so far.
+func (w *Webhook) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Close posts the errors still pending and stops the webhook.
+func (w *Webhook) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		<-w.done
+		return
+	}
+	w.closed = true
+	batch := w.batch
+	w.batch = nil
+	w.mu.Unlock()
+	if len(batch) != 0 {
+		w.queue <- batch
+	}
+	close(w.queue)
+	<-w.done
+}
+
+func (w *Webhook) drop(err *ergo.Error) {
+	atomic.AddUint64(&w.dropped, 1)
+	if w.opts.OnDrop != nil {
+		w.opts.OnDrop(err)
+	}
+}
+
+func (w *Webhook) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case batch, ok := <-w.queue:
+			if !ok {
+				return
+			}
+			w.send(batch)
+		case <-ticker.C:
+			w.mu.Lock()
+			batch := w.batch
+			w.batch = nil
+			w.mu.Unlock()
+			if len(batch) != 0 {
+				w.send(batch)
+			}
+		}
+	}
+}
+
+func (w *Webhook) send(batch []*ergo.Error) {
+	if body, err := w.encode(batch); err == nil {
+		for attempt := 0; ; attempt++ {
+			retry, err := w.post(body)
+			if err == nil {
+				return
+			}
+			if !retry || attempt >= w.opts.Retries {
+				break
+			}
+			time.Sleep(retryDelay(attempt, w.opts.Backoff, w.opts.MaxBackoff))
+		}
+	}
+	for _, err := range batch {
+		w.drop(err)
+	}
+}
+
+func (w *Webhook) encode(batch []*ergo.Error) ([]byte, error) {
+	docs := make([]interface{}, len(batch))
+	for i, err := range batch {
+		if w.opts.Format == FormatProblem {
+			docs[i] = httperr.Problem(err)
+		} else {
+			docs[i] = err.ToWire()
+		}
+	}
+	return json.Marshal(docs)
+}
+
+// post posts a batch, reporting whether a failure is worth retrying.
+func (w *Webhook) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for key, values := range w.opts.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("report: webhook responded %v", resp.Status)
+}
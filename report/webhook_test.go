@@ -0,0 +1,131 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"encoding/json"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// hook is a webhook endpoint remembering the batches it received.
+type hook struct {
+	mu      sync.Mutex
+	batches [][]map[string]interface{}
+	fails   int
+}
+
+func (h *hook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.fails > 0 {
+		h.fails--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var batch []map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&batch)
+	h.batches = append(h.batches, batch)
+}
+
+func (t *TestSuite) TestWebhook(c *gc.C) {
+	h := &hook{}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	w := NewWebhook(server.URL, WebhookOptions{
+		Match:     Codes("report", EBroken),
+		BatchSize: 2,
+	})
+	for i := 0; i < 3; i++ {
+		w.Report(ergo.New(0, "report", EBroken))
+	}
+	w.Report(ergo.New(0, "report", ENotFound, "name", "x"))
+	w.Close()
+
+	c.Assert(h.batches, gc.HasLen, 2)
+	c.Check(h.batches[0], gc.HasLen, 2)
+	c.Check(h.batches[1], gc.HasLen, 1)
+	c.Check(h.batches[0][0]["Domain"], gc.Equals, "report")
+	c.Check(h.batches[0][0]["Code"], gc.Equals, float64(EBroken))
+	c.Check(w.Dropped(), gc.Equals, uint64(0))
+}
+
+func (t *TestSuite) TestWebhookFlush(c *gc.C) {
+	h := &hook{}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	w := NewWebhook(server.URL, WebhookOptions{
+		Format:        FormatProblem,
+		FlushInterval: 10 * time.Millisecond,
+	})
+	defer w.Close()
+	w.Report(ergo.New(0, "report", ENotFound, "name", "x"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		h.mu.Lock()
+		n := len(h.batches)
+		h.mu.Unlock()
+		if n != 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	c.Assert(h.batches, gc.HasLen, 1)
+	c.Check(h.batches[0][0]["type"], gc.Equals, "urn:ergo:report:0")
+	c.Check(h.batches[0][0]["detail"], gc.Equals, "The x was not found")
+}
+
+func (t *TestSuite) TestWebhookRetries(c *gc.C) {
+	h := &hook{fails: 1}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	w := NewWebhook(server.URL, WebhookOptions{Retries: 1, Backoff: time.Millisecond})
+	w.Report(newError(1))
+	w.Close()
+	c.Check(h.batches, gc.HasLen, 1)
+	c.Check(w.Dropped(), gc.Equals, uint64(0))
+
+	h.fails = 2
+	var dropped []ergo.ErrCode
+	w = NewWebhook(server.URL, WebhookOptions{
+		Retries: 1,
+		OnDrop:  func(err *ergo.Error) { dropped = append(dropped, err.Code) },
+	})
+	w.Report(newError(2))
+	w.Close()
+	c.Check(h.batches, gc.HasLen, 1)
+	c.Check(dropped, gc.DeepEquals, []ergo.ErrCode{2})
+	w.Report(newError(3))
+	c.Check(w.Dropped(), gc.Equals, uint64(2))
+}
@@ -0,0 +1,160 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+)
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing errors
+// serialized with encoding/json, see Wire. Consumers in other languages
+// can use it to validate payloads or to generate bindings.
+func JSONSchema() []byte {
+	return []byte(fmt.Sprintf(jsonSchema, SchemaVersion, SchemaVersion))
+}
+
+const jsonSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "https://github.com/flaub/ergo/schema/v%d/error.json",
+  "title": "ergo error",
+  "description": "An error chain serialized by github.com/flaub/ergo.",
+  "$ref": "#/$defs/error",
+  "properties": {
+    "Version": {
+      "description": "The schema version, only set on the outermost error. A missing version means 1.",
+      "type": "integer",
+      "minimum": 1,
+      "maximum": %d
+    }
+  },
+  "$defs": {
+    "error": {
+      "type": "object",
+      "properties": {
+        "Version": {
+          "type": "integer"
+        },
+        "Domain": {
+          "description": "The domain of this error.",
+          "type": "string",
+          "minLength": 1
+        },
+        "Code": {
+          "description": "The error code of this error, within its domain.",
+          "type": "integer"
+        },
+        "Name": {
+          "description": "The symbolic name of the error code, if it has one.",
+          "type": "string"
+        },
+        "Info": {
+          "$ref": "#/$defs/info"
+        },
+        "Severity": {
+          "description": "The seriousness of this error: 1 debug, 2 info, 3 warn, 4 error, 5 fatal. A missing severity means error.",
+          "type": "integer",
+          "minimum": 0,
+          "maximum": 5
+        },
+        "Context": {
+          "description": "Additional context to help developers determine the source of an error.",
+          "type": "string"
+        },
+        "Stack": {
+          "description": "The stack trace captured when this error was created, innermost frame first.",
+          "type": "array",
+          "items": {
+            "$ref": "#/$defs/frame"
+          }
+        },
+        "Inner": {
+          "description": "The inner error of the chain. The innermost error represents the original error.",
+          "$ref": "#/$defs/error"
+        },
+        "Causes": {
+          "description": "Additional causes of this error.",
+          "type": "array",
+          "items": {
+            "$ref": "#/$defs/error"
+          }
+        }
+      },
+      "required": ["Domain", "Code"],
+      "unevaluatedProperties": false,
+      "if": {
+        "properties": {
+          "Domain": {
+            "const": "go"
+          }
+        }
+      },
+      "then": {
+        "description": "Errors of the go domain wrap a go error and carry its message.",
+        "required": ["Info"],
+        "properties": {
+          "Info": {
+            "required": ["_err"]
+          }
+        }
+      }
+    },
+    "info": {
+      "description": "A collection of named values. Values may be any JSON value; values which cannot be represented are replaced by their string form. Keys starting with an underscore are reserved.",
+      "type": "object",
+      "properties": {
+        "_err": {
+          "description": "The message of a wrapped go error.",
+          "type": "string"
+        },
+        "_msg": {
+          "description": "A preformatted message, used instead of the domain message format.",
+          "type": "string"
+        },
+        "_note": {
+          "description": "A message attached to a wrapped go error, preceding it.",
+          "type": "string"
+        }
+      }
+    },
+    "frame": {
+      "type": "object",
+      "properties": {
+        "Function": {
+          "description": "The fully qualified name of the function.",
+          "type": "string"
+        },
+        "File": {
+          "description": "The source file.",
+          "type": "string"
+        },
+        "Line": {
+          "description": "The line number in the source file.",
+          "type": "integer"
+        }
+      },
+      "additionalProperties": false
+    }
+  }
+}
+`
@@ -0,0 +1,66 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"encoding/json"
+	gc "github.com/motain/gocheck"
+	"reflect"
+)
+
+func (t *TestSuite) TestSchemaFields(c *gc.C) {
+	var schema map[string]interface{}
+	c.Assert(json.Unmarshal(JSONSchema(), &schema), gc.IsNil)
+	c.Check(schema["$id"], gc.Equals, "https://github.com/flaub/ergo/schema/v1/error.json")
+
+	defs := schema["$defs"].(map[string]interface{})
+	properties := func(def string) map[string]interface{} {
+		return defs[def].(map[string]interface{})["properties"].(map[string]interface{})
+	}
+
+	// Every serialized field must be described.
+	fields := func(typ reflect.Type, def string) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.Tag.Get("json") == "-" {
+				continue
+			}
+			_, ok := properties(def)[field.Name]
+			c.Check(ok, gc.Equals, true, gc.Commentf("%v.%v", def, field.Name))
+		}
+		c.Check(properties(def), gc.HasLen, typ.NumField()-countIgnored(typ))
+	}
+	fields(reflect.TypeOf(Wire{}), "error")
+	fields(reflect.TypeOf(Frame{}), "frame")
+}
+
+func countIgnored(typ reflect.Type) int {
+	n := 0
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Tag.Get("json") == "-" {
+			n++
+		}
+	}
+	return n
+}
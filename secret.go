@@ -0,0 +1,72 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"io"
+)
+
+// RedactedText replaces secret Info values wherever they are rendered.
+const RedactedText = "[REDACTED]"
+
+// Redacted is an Info value rendered as RedactedText, see Secret.
+type Redacted struct {
+	value interface{}
+}
+
+// Secret wraps an Info value, such as a token or a password, so that
+// it is rendered as RedactedText by Error(), Message() and every
+// serializer, while remaining available to the program through Value
+// and Info:
+//
+//	err := ergo.NewE("auth", EDenied, ergo.WithInfo("token", ergo.Secret(token)))
+func Secret(v interface{}) Redacted {
+	return Redacted{value: v}
+}
+
+// Value returns the wrapped value.
+func (r Redacted) Value() interface{} {
+	return r.value
+}
+
+// String implements fmt.Stringer.
+func (r Redacted) String() string {
+	return RedactedText
+}
+
+// Format implements fmt.Formatter, so that no verb reveals the value.
+func (r Redacted) Format(f fmt.State, verb rune) {
+	io.WriteString(f, RedactedText)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r Redacted) MarshalText() ([]byte, error) {
+	return []byte(RedactedText), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r Redacted) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + RedactedText + `"`), nil
+}
@@ -0,0 +1,64 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"strings"
+)
+
+func (t *TestSuite) TestSecret(c *gc.C) {
+	err := NewError(EMyErrorArgs, "name", Secret("hunter2"), "token", Secret([]byte("t0k3n")))
+	c.Check(err.Message(), gc.Equals, "The [REDACTED] failed")
+	c.Check(strings.Contains(err.Error(), "hunter2"), gc.Equals, false)
+	c.Check(err.Info.String(), gc.Equals, "map[name:[REDACTED] token:[REDACTED]]")
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x"} {
+		c.Check(fmt.Sprintf(verb, err.Info["name"]), gc.Equals, RedactedText, gc.Commentf(verb))
+	}
+
+	data, jerr := json.Marshal(err)
+	c.Assert(jerr, gc.IsNil)
+	c.Check(bytes.Contains(data, []byte("hunter2")), gc.Equals, false)
+	decoded, derr := FromJSON(data)
+	c.Assert(derr, gc.IsNil)
+	c.Check(decoded.Info["name"], gc.Equals, RedactedText)
+
+	var buf bytes.Buffer
+	c.Assert(EncodeGob(&buf, err), gc.IsNil)
+	c.Check(bytes.Contains(buf.Bytes(), []byte("hunter2")), gc.Equals, false)
+	decoded, derr = DecodeGob(&buf)
+	c.Assert(derr, gc.IsNil)
+	c.Check(decoded.Info["token"], gc.Equals, RedactedText)
+
+	c.Check(err.Info["name"].(Redacted).Value(), gc.Equals, "hunter2")
+	name, ok := InfoString(err, "name")
+	c.Check(ok, gc.Equals, true)
+	c.Check(name, gc.Equals, "hunter2")
+	secret, ok := Info[Redacted](err, "name")
+	c.Check(ok, gc.Equals, true)
+	c.Check(secret.Value(), gc.Equals, "hunter2")
+}
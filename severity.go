@@ -0,0 +1,88 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Severity ranks the seriousness of an error,
+// for consumers such as loggers and error trackers.
+type Severity int
+
+const (
+	// SeverityUnset is the severity of errors created without one.
+	// It is treated as SeverityError, see Level.
+	SeverityUnset Severity = iota
+	SeverityDebug
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+	SeverityFatal
+)
+
+var severityNames = [...]string{"", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// String returns the upper case name of the severity.
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return "Severity(" + strconv.Itoa(int(s)) + ")"
+	}
+	if s == SeverityUnset {
+		return "UNSET"
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity returns the severity of a name returned by String,
+// ignoring case. An empty name is SeverityUnset.
+func ParseSeverity(name string) (Severity, error) {
+	if name == "" || strings.EqualFold(name, "UNSET") {
+		return SeverityUnset, nil
+	}
+	for s, known := range severityNames {
+		if s != 0 && strings.EqualFold(name, known) {
+			return Severity(s), nil
+		}
+	}
+	return SeverityUnset, fmt.Errorf("unknown severity %q", name)
+}
+
+// WithSeverity sets the severity of a single error.
+func WithSeverity(s Severity) Option {
+	return func(opts *options) {
+		opts.severity = s
+	}
+}
+
+// Level returns the severity of this error,
+// defaulting to SeverityError if it is unset.
+func (err *Error) Level() Severity {
+	if err.Severity == SeverityUnset {
+		return SeverityError
+	}
+	return err.Severity
+}
@@ -0,0 +1,79 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	gc "github.com/motain/gocheck"
+)
+
+func (t *TestSuite) TestSeverity(c *gc.C) {
+	err := New(0, "ergo", EMyError0)
+	c.Check(err.Severity, gc.Equals, SeverityUnset)
+	c.Check(err.Level(), gc.Equals, SeverityError)
+
+	err = New(0, "ergo", EMyErrorArgs, "name", "x", WithSeverity(SeverityWarn))
+	c.Check(err.Severity, gc.Equals, SeverityWarn)
+	c.Check(err.Level(), gc.Equals, SeverityWarn)
+	c.Check(err.Info, gc.DeepEquals, ErrInfo{"name": "x"})
+
+	err = Wrap(errorString("x"), WithSeverity(SeverityDebug))
+	c.Check(err.Severity, gc.Equals, SeverityDebug)
+
+	c.Check(SeverityFatal.String(), gc.Equals, "FATAL")
+	c.Check(SeverityUnset.String(), gc.Equals, "UNSET")
+	c.Check(Severity(42).String(), gc.Equals, "Severity(42)")
+}
+
+func (t *TestSuite) TestSeverityWire(c *gc.C) {
+	err := New(0, "ergo", EMyError0, WithSeverity(SeverityInfo))
+	data, jerr := err.MarshalJSON()
+	c.Assert(jerr, gc.IsNil)
+	c.Check(string(data), gc.Matches, `.*"Severity":2.*`)
+	restored, jerr := FromJSON(data)
+	c.Assert(jerr, gc.IsNil)
+	c.Check(restored.Severity, gc.Equals, SeverityInfo)
+
+	data, _ = New(0, "ergo", EMyError0).MarshalJSON()
+	c.Check(string(data), gc.Not(gc.Matches), `.*"Severity".*`)
+}
+
+type errorString string
+
+func (e errorString) Error() string { return string(e) }
+
+func (t *TestSuite) TestParseSeverity(c *gc.C) {
+	for s := SeverityUnset; s <= SeverityFatal; s++ {
+		parsed, err := ParseSeverity(s.String())
+		c.Check(err, gc.IsNil)
+		c.Check(parsed, gc.Equals, s)
+	}
+	parsed, err := ParseSeverity("warn")
+	c.Check(err, gc.IsNil)
+	c.Check(parsed, gc.Equals, SeverityWarn)
+	parsed, err = ParseSeverity("")
+	c.Check(err, gc.IsNil)
+	c.Check(parsed, gc.Equals, SeverityUnset)
+	_, err = ParseSeverity("severe")
+	c.Check(err, gc.ErrorMatches, `unknown severity "severe"`)
+}
@@ -0,0 +1,141 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package slogergo expands ergo errors logged with log/slog
+// into structured attributes.
+package slogergo
+
+import (
+	"context"
+	"github.com/flaub/ergo"
+	"log/slog"
+)
+
+// HandlerOptions customizes a Handler.
+type HandlerOptions struct {
+	// StackLevel is the minimum level of records whose errors
+	// include a "stack" attribute. If nil, stacks are never included.
+	StackLevel slog.Leveler
+}
+
+// Handler is a slog.Handler which expands ergo errors found in
+// attributes before passing records on to another handler.
+// An error attribute "err" becomes a group with the attributes
+// "err.msg", "err.domain", "err.code", "err.info.*" and optionally
+// "err.stack", which holds the stack trace or context of the error.
+type Handler struct {
+	next slog.Handler
+	opts HandlerOptions
+}
+
+// NewHandler creates a handler which passes records on to "next".
+func NewHandler(next slog.Handler, opts *HandlerOptions) *Handler {
+	h := &Handler{next: next}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		out.AddAttrs(h.expand(attr, r.Level))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+// WithAttrs implements slog.Handler.
+// Since no record is available, stacks are included
+// if StackLevel is set at all.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		expanded[i] = h.expand(attr, slog.LevelError+1)
+	}
+	return &Handler{next: h.next.WithAttrs(expanded), opts: h.opts}
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), opts: h.opts}
+}
+
+func (h *Handler) expand(attr slog.Attr, level slog.Level) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		expanded := make([]any, len(group))
+		for i, member := range group {
+			expanded[i] = h.expand(member, level)
+		}
+		return slog.Group(attr.Key, expanded...)
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			if ergoErr, ok := ergo.AsError(err); ok {
+				stack := h.opts.StackLevel != nil && level >= h.opts.StackLevel.Level()
+				return slog.Group(attr.Key, Attrs(ergoErr, stack)...)
+			}
+		}
+	}
+	return attr
+}
+
+// Attrs returns the attributes describing an error:
+// "msg", "domain", "code", an "info" group and, if "stack" is true,
+// the "stack" trace or context of the error.
+// Reserved Info keys (see ergo.IsReserved) are left out,
+// since they are already part of the message.
+func Attrs(err *ergo.Error, stack bool) []any {
+	attrs := []any{
+		slog.String("msg", err.Message()),
+		slog.String("domain", err.Domain),
+		slog.Int("code", int(err.Code)),
+	}
+	var info []any
+	for _, key := range err.Info.Keys() {
+		if !ergo.IsReserved(key) {
+			info = append(info, slog.Any(key, ergo.Redact(key, err.Info[key])))
+		}
+	}
+	if info != nil {
+		attrs = append(attrs, slog.Group("info", info...))
+	}
+	if stack {
+		context := err.Context
+		if err.Stack != nil {
+			context = err.Stack.String()
+		}
+		if context != "" {
+			attrs = append(attrs, slog.String("stack", context))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,112 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package slogergo
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("slogergo", ergo.DomainMap{
+		ENotFound: "The {{.name}} was not found",
+	})
+}
+
+func newLogger(buf *bytes.Buffer, opts *HandlerOptions) *slog.Logger {
+	text := slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	return slog.New(NewHandler(text, opts))
+}
+
+func (t *TestSuite) TestHandler(c *gc.C) {
+	var buf bytes.Buffer
+	logger := newLogger(&buf, &HandlerOptions{StackLevel: slog.LevelError})
+	err := ergo.New(0, "slogergo", ENotFound, "name", "user", "id", 7)
+
+	logger.Warn("lookup", "err", err)
+	c.Check(buf.String(), gc.Equals, `level=WARN msg=lookup err.msg="The user was not found" `+
+		`err.domain=slogergo err.code=0 err.info.id=7 err.info.name=user`+"\n")
+
+	buf.Reset()
+	logger.Error("lookup", "err", fmt.Errorf("wrapped: %w", err))
+	c.Check(buf.String(), gc.Matches, `level=ERROR msg=lookup err.msg="The user was not found" `+
+		`err.domain=slogergo err.code=0 err.info.id=7 err.info.name=user err.stack=".*TestHandler.*"\n`)
+
+	buf.Reset()
+	logger.Info("plain", "err", fmt.Errorf("plain"), slog.Group("req", "err", err))
+	c.Check(buf.String(), gc.Equals, `level=INFO msg=plain err=plain req.err.msg="The user was not found" `+
+		`req.err.domain=slogergo req.err.code=0 req.err.info.id=7 req.err.info.name=user`+"\n")
+}
+
+func (t *TestSuite) TestWithAttrs(c *gc.C) {
+	var buf bytes.Buffer
+	logger := newLogger(&buf, nil).With("cause", ergo.Wrap(fmt.Errorf("disk full")))
+	logger.WithGroup("job").Info("failed")
+	c.Check(buf.String(), gc.Equals, `level=INFO msg=failed cause.msg="Error: disk full" `+
+		`cause.domain=go cause.code=0`+"\n")
+	c.Check(strings.Contains(buf.String(), "stack"), gc.Equals, false)
+}
+
+func (t *TestSuite) TestRedaction(c *gc.C) {
+	defer ergo.SetRedactionPolicy(nil)
+	ergo.SetRedactionPolicy(&ergo.RedactionPolicy{
+		Keys:   []*regexp.Regexp{regexp.MustCompile("^token$")},
+		Values: []*regexp.Regexp{ergo.EmailPattern},
+	})
+	var buf bytes.Buffer
+	logger := newLogger(&buf, nil)
+	err := ergo.New(0, "slogergo", ENotFound, "name", "ann@example.com", "token", "t0k3n",
+		"key", ergo.Secret("k3y"))
+
+	logger.Warn("lookup", "err", err)
+	c.Check(buf.String(), gc.Equals, `level=WARN msg=lookup err.msg="The [REDACTED] was not found" `+
+		`err.domain=slogergo err.code=0 err.info.key=[REDACTED] err.info.name=[REDACTED] `+
+		`err.info.token=[REDACTED]`+"\n")
+}
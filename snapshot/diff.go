@@ -0,0 +1,189 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"github.com/flaub/ergo"
+	"sort"
+)
+
+// Kind is the kind of a Change.
+type Kind int
+
+const (
+	// Added codes are new.
+	Added Kind = iota
+	// Removed codes no longer exist.
+	Removed
+	// Renumbered codes kept their name but not their number.
+	Renumbered
+	// Renamed codes kept their number but not their name,
+	// so the number now means something else.
+	// Naming a code which had no name is not a change.
+	Renamed
+	// Reworded codes kept their number and name, but not their message.
+	Reworded
+)
+
+var kindNames = [...]string{"added", "removed", "renumbered", "renamed", "reworded"}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Change is a difference between two snapshots.
+// Old is missing for added codes, New for removed codes.
+type Change struct {
+	Domain string
+	Kind   Kind
+	Old    *Entry
+	New    *Entry
+}
+
+// Breaking reports whether the change breaks clients depending on codes:
+// only added and reworded codes are compatible.
+func (c Change) Breaking() bool {
+	return c.Kind != Added && c.Kind != Reworded
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("[%v:%d] %v was added", c.Domain, c.New.Code, c.New.label())
+	case Removed:
+		return fmt.Sprintf("[%v:%d] %v was removed", c.Domain, c.Old.Code, c.Old.label())
+	case Renumbered:
+		return fmt.Sprintf("[%v:%d] %v was renumbered to %d", c.Domain, c.Old.Code, c.Old.Name, c.New.Code)
+	case Renamed:
+		return fmt.Sprintf("[%v:%d] %v was renamed to %v", c.Domain, c.Old.Code, c.Old.label(), c.New.label())
+	}
+	return fmt.Sprintf("[%v:%d] %v was reworded from %q to %q",
+		c.Domain, c.Old.Code, c.Old.label(), c.Old.Message, c.New.Message)
+}
+
+func (e *Entry) label() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%q", e.Message)
+	}
+	return e.Name
+}
+
+// Diff returns the changes from one snapshot to another,
+// ordered by domain and code. A name found under another number is
+// renumbered; the old number is then not reported as removed.
+func Diff(old, new *Snapshot) []Change {
+	var changes []Change
+	for _, domain := range domainNames(old, new) {
+		changes = append(changes, diffDomain(domain, old.Domains[domain], new.Domains[domain])...)
+	}
+	return changes
+}
+
+func domainNames(snaps ...*Snapshot) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, snap := range snaps {
+		for name := range snap.Domains {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func diffDomain(domain string, old, new []Entry) []Change {
+	oldCodes := index(old)
+	newCodes := index(new)
+	newNames := make(map[string]*Entry)
+	for i := range new {
+		if new[i].Name != "" {
+			newNames[new[i].Name] = &new[i]
+		}
+	}
+	var changes []Change
+	renumbered := make(map[ergo.ErrCode]bool)
+	for i := range old {
+		o := &old[i]
+		if n, ok := newNames[o.Name]; ok && o.Name != "" && n.Code != o.Code {
+			changes = append(changes, Change{Domain: domain, Kind: Renumbered, Old: o, New: n})
+			renumbered[o.Code] = true
+		}
+	}
+	for i := range old {
+		o := &old[i]
+		n, ok := newCodes[o.Code]
+		switch {
+		case !ok && !renumbered[o.Code]:
+			changes = append(changes, Change{Domain: domain, Kind: Removed, Old: o})
+		case !ok:
+		case n.Name != o.Name && o.Name != "":
+			changes = append(changes, Change{Domain: domain, Kind: Renamed, Old: o, New: n})
+		case n.Message != o.Message:
+			changes = append(changes, Change{Domain: domain, Kind: Reworded, Old: o, New: n})
+		}
+	}
+	for i := range new {
+		n := &new[i]
+		if _, ok := oldCodes[n.Code]; ok {
+			continue
+		}
+		if o, ok := findName(old, n.Name); ok && o.Code != n.Code {
+			continue
+		}
+		changes = append(changes, Change{Domain: domain, Kind: Added, New: n})
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].code() < changes[j].code() })
+	return changes
+}
+
+func (c Change) code() ergo.ErrCode {
+	if c.Old != nil {
+		return c.Old.Code
+	}
+	return c.New.Code
+}
+
+func index(entries []Entry) map[ergo.ErrCode]*Entry {
+	codes := make(map[ergo.ErrCode]*Entry, len(entries))
+	for i := range entries {
+		codes[entries[i].Code] = &entries[i]
+	}
+	return codes
+}
+
+func findName(entries []Entry, name string) (*Entry, bool) {
+	if name == "" {
+		return nil, false
+	}
+	for i := range entries {
+		if entries[i].Name == name {
+			return &entries[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,69 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package snapshot
+
+import (
+	gc "github.com/motain/gocheck"
+)
+
+func (t *TestSuite) TestDiff(c *gc.C) {
+	old := &Snapshot{Domains: map[string][]Entry{
+		"shop": {
+			{Code: 1, Name: "EOne", Message: "One"},
+			{Code: 2, Name: "ETwo", Message: "Two"},
+			{Code: 3, Message: "Three"},
+			{Code: 4, Name: "EFour", Message: "Four"},
+			{Code: 5, Message: "Five"},
+			{Code: 6, Message: "Six"},
+		},
+		"bank": {{Code: 1, Message: "Closed"}},
+	}}
+	new := &Snapshot{Domains: map[string][]Entry{
+		"shop": {
+			{Code: 1, Name: "EOne", Message: "The one"},
+			{Code: 3, Message: "Three"},
+			{Code: 4, Name: "EQuatre", Message: "Four"},
+			{Code: 5, Name: "EFive", Message: "Five"},
+			{Code: 7, Message: "Seven"},
+			{Code: 8, Name: "ETwo", Message: "Two"},
+		},
+	}}
+	var changes []string
+	var breaking []bool
+	for _, change := range Diff(old, new) {
+		changes = append(changes, change.String())
+		breaking = append(breaking, change.Breaking())
+	}
+	c.Check(changes, gc.DeepEquals, []string{
+		`[bank:1] "Closed" was removed`,
+		`[shop:1] EOne was reworded from "One" to "The one"`,
+		`[shop:2] ETwo was renumbered to 8`,
+		`[shop:4] EFour was renamed to EQuatre`,
+		`[shop:6] "Six" was removed`,
+		`[shop:7] "Seven" was added`,
+	})
+	c.Check(breaking, gc.DeepEquals, []bool{true, false, true, true, true, false})
+	c.Check(Diff(old, old), gc.HasLen, 0)
+	c.Check(Renumbered.String(), gc.Equals, "renumbered")
+}
@@ -0,0 +1,168 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package snapshot guards error codes against accidental changes.
+// A snapshot records the name and message of every code of some domains;
+// comparing it with a previous snapshot, typically committed next to the
+// code, reveals codes that were removed, renumbered or given another
+// meaning, which would break clients depending on them:
+//
+//	func TestErrorCodes(t *testing.T) {
+//		if err := snapshot.Check("testdata/errors.json", snapshot.FromRegistry(), false, *update); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+//
+// See also the ergosnap command, which works with catalog files.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/flaub/ergo"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Snapshot records the codes of domains.
+type Snapshot struct {
+	Domains map[string][]Entry `json:"domains"`
+}
+
+// Entry records a code of a domain.
+type Entry struct {
+	Code    ergo.ErrCode `json:"code"`
+	Name    string       `json:"name,omitempty"`
+	Message string       `json:"message"`
+}
+
+// FromRegistry records the domains defined with ergo.Domain in this
+// process, except those given, such as domains of other libraries.
+func FromRegistry(except ...string) *Snapshot {
+	snap := &Snapshot{Domains: make(map[string][]Entry)}
+outer:
+	for _, name := range ergo.ListDomains() {
+		for _, skip := range except {
+			if name == skip {
+				continue outer
+			}
+		}
+		formats, ok := ergo.ListCodes(name)
+		if !ok {
+			continue
+		}
+		for code, message := range formats {
+			entry := Entry{Code: code, Message: message}
+			entry.Name, _ = ergo.CodeName(name, code)
+			snap.Domains[name] = append(snap.Domains[name], entry)
+		}
+	}
+	snap.sort()
+	return snap
+}
+
+// FromCatalogs records the domains of catalogs, ignoring translations.
+func FromCatalogs(cats []*ergo.Catalog) *Snapshot {
+	snap := &Snapshot{Domains: make(map[string][]Entry)}
+	for _, cat := range cats {
+		if cat.Locale != "" {
+			continue
+		}
+		for _, entry := range cat.Codes {
+			snap.Domains[cat.Domain] = append(snap.Domains[cat.Domain],
+				Entry{Code: entry.Code, Name: entry.Name, Message: entry.Message})
+		}
+	}
+	snap.sort()
+	return snap
+}
+
+func (snap *Snapshot) sort() {
+	for _, entries := range snap.Domains {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	}
+}
+
+// Read decodes a snapshot written by Write.
+func Read(r io.Reader) (*Snapshot, error) {
+	snap := new(Snapshot)
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(snap); err != nil {
+		return nil, fmt.Errorf("snapshot: %v", err)
+	}
+	if snap.Domains == nil {
+		snap.Domains = make(map[string][]Entry)
+	}
+	snap.sort()
+	return snap, nil
+}
+
+// Write encodes the snapshot as indented JSON, suited to version control.
+func (snap *Snapshot) Write(w io.Writer) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Check compares a snapshot with the one in a file, reporting breaking
+// changes, see Diff. Rewording a message is only reported if "strict".
+// The file is written if it does not exist yet, or if "update" is set
+// to acknowledge the changes.
+func Check(path string, current *Snapshot, strict, update bool) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) || update {
+		if f != nil {
+			f.Close()
+		}
+		var b strings.Builder
+		if err := current.Write(&b); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(path, []byte(b.String()), 0644)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	previous, err := Read(f)
+	if err != nil {
+		return fmt.Errorf("%v: %v", path, err)
+	}
+	var breaking []string
+	for _, change := range Diff(previous, current) {
+		if change.Breaking() || (strict && change.Kind == Reworded) {
+			breaking = append(breaking, change.String())
+		}
+	}
+	if len(breaking) != 0 {
+		return fmt.Errorf("snapshot: error codes changed since %v:\n\t%v", path, strings.Join(breaking, "\n\t"))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package snapshot
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("snapshot", ergo.DomainMap{
+		1: "Not found",
+		0: "Failed",
+	}, ergo.WithNames(map[ergo.ErrCode]string{1: "ENotFound"}))
+}
+
+func (t *TestSuite) TestFromRegistry(c *gc.C) {
+	snap := FromRegistry("go")
+	c.Check(snap.Domains["go"], gc.IsNil)
+	c.Check(snap.Domains["snapshot"], gc.DeepEquals, []Entry{
+		{Code: 0, Message: "Failed"},
+		{Code: 1, Name: "ENotFound", Message: "Not found"},
+	})
+	c.Check(FromRegistry("snapshot").Domains["snapshot"], gc.IsNil)
+}
+
+func (t *TestSuite) TestReadWrite(c *gc.C) {
+	snap := FromCatalogs([]*ergo.Catalog{
+		{Domain: "shop", Codes: []ergo.CatalogEntry{{Code: 2, Name: "EOut", Message: "Out"}, {Code: 1, Message: "One"}}},
+		{Domain: "shop", Locale: "de", Codes: []ergo.CatalogEntry{{Code: 3, Message: "Drei"}}},
+	})
+	var b strings.Builder
+	c.Assert(snap.Write(&b), gc.IsNil)
+	c.Check(b.String(), gc.Equals, `{
+  "domains": {
+    "shop": [
+      {
+        "code": 1,
+        "message": "One"
+      },
+      {
+        "code": 2,
+        "name": "EOut",
+        "message": "Out"
+      }
+    ]
+  }
+}
+`)
+	read, err := Read(strings.NewReader(b.String()))
+	c.Assert(err, gc.IsNil)
+	c.Check(read, gc.DeepEquals, snap)
+	_, err = Read(strings.NewReader(`{"domian": {}}`))
+	c.Check(err, gc.ErrorMatches, `snapshot: json: unknown field "domian"`)
+}
+
+func (t *TestSuite) TestCheck(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "errors.json")
+	snap := func(entries ...Entry) *Snapshot {
+		return &Snapshot{Domains: map[string][]Entry{"shop": entries}}
+	}
+	c.Assert(Check(path, snap(Entry{Code: 1, Name: "EOne", Message: "One"}), false, false), gc.IsNil)
+	_, err := ioutil.ReadFile(path)
+	c.Assert(err, gc.IsNil)
+
+	c.Check(Check(path, snap(
+		Entry{Code: 1, Name: "EOne", Message: "The one"},
+		Entry{Code: 2, Name: "ETwo", Message: "Two"},
+	), false, false), gc.IsNil)
+	c.Check(Check(path, snap(Entry{Code: 1, Name: "EOne", Message: "The one"}), true, false), gc.ErrorMatches,
+		`(?s)snapshot: error codes changed since .*errors.json:\n\t\[shop:1\] EOne was reworded from "One" to "The one"`)
+	c.Check(Check(path, snap(Entry{Code: 2, Name: "EOne", Message: "One"}), false, false), gc.ErrorMatches,
+		`(?s)snapshot: error codes changed since .*:\n\t\[shop:1\] EOne was renumbered to 2`)
+
+	c.Assert(Check(path, snap(Entry{Code: 2, Name: "EOne", Message: "One"}), false, true), gc.IsNil)
+	c.Check(Check(path, snap(Entry{Code: 2, Name: "EOne", Message: "One"}), true, false), gc.IsNil)
+
+	c.Assert(ioutil.WriteFile(path, []byte("{"), 0644), gc.IsNil)
+	c.Check(Check(path, snap(), false, false), gc.ErrorMatches, ".*errors.json: snapshot: unexpected EOF")
+}
@@ -0,0 +1,260 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Frame is a single frame of a stack trace.
+type Frame struct {
+	// The program counter of this frame.
+	// It is only meaningful within the process that captured it.
+	PC uintptr `json:"-"`
+
+	// The fully qualified name of the function.
+	Function string `json:",omitempty"`
+
+	// The source file and line number.
+	File string `json:",omitempty"`
+	Line int    `json:",omitempty"`
+}
+
+// Stack is a stack trace made of structured frames.
+// The outermost caller is last.
+//
+// A stack captured by New only records program counters.
+// Symbolic information is resolved the first time it is needed,
+// so errors which are handled and discarded stay cheap.
+type Stack struct {
+	pcs    []uintptr
+	once   sync.Once
+	frames []Frame
+}
+
+// NewStack creates a stack from a list of frames,
+// for example to represent a trace received from another process.
+func NewStack(frames []Frame) *Stack {
+	return &Stack{frames: frames}
+}
+
+// callers captures at most "depth" frames of the current stack,
+// or all of them if "depth" is negative.
+func callers(skip, depth int) *Stack {
+	if depth == 0 {
+		return nil
+	}
+	var buf [64]uintptr
+	pcs := buf[:]
+	if depth > 0 && depth < len(buf) {
+		pcs = buf[:depth]
+	}
+	for {
+		n := runtime.Callers(skip+1, pcs)
+		if n < len(pcs) || len(pcs) == depth {
+			return &Stack{pcs: append([]uintptr(nil), pcs[:n]...)}
+		}
+		// The buffer is full, grow it and try again.
+		size := 2 * len(pcs)
+		if depth > 0 && size > depth {
+			size = depth
+		}
+		pcs = make([]uintptr, size)
+	}
+}
+
+// stackOf keeps at most "depth" of "pcs" as a stack,
+// or all of them if "depth" is negative.
+func stackOf(pcs []uintptr, depth int) *Stack {
+	if depth >= 0 && depth < len(pcs) {
+		pcs = pcs[:depth]
+	}
+	return &Stack{pcs: pcs}
+}
+
+// caller returns the immediate caller as "file:line function".
+func caller(skip int) string {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) == 0 {
+		return ""
+	}
+	return callerAt(pcs[:])
+}
+
+// callerAt returns the first frame of "pcs" as "file:line function".
+func callerAt(pcs []uintptr) string {
+	frame, _ := runtime.CallersFrames(pcs).Next()
+	f := Frame{Function: frame.Function, File: frame.File, Line: frame.Line}
+	return fmt.Sprintf("%v:%v %v", f.file(), f.Line, f.Function)
+}
+
+// panicCallers returns the program counters of a panicking goroutine,
+// starting at the frame which panicked. It must be called by a deferred
+// function while the panic is being recovered: the frames up to
+// runtime.gopanic, and the runtime frames raising the panic, such as
+// runtime.sigpanic for a nil dereference, are skipped.
+// Only program counters are inspected, so inlining cannot shift
+// the result.
+func panicCallers() []uintptr {
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(2, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+	start := 0
+	for i, pc := range pcs {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil {
+			continue
+		}
+		name := fn.Name()
+		if name == "runtime.gopanic" {
+			start = i + 1
+		} else if start == i && start > 0 && strings.HasPrefix(name, "runtime.") {
+			start = i + 1
+		} else if start > 0 {
+			break
+		}
+	}
+	return pcs[start:]
+}
+
+func (s *Stack) resolve() {
+	if s.pcs == nil {
+		return
+	}
+	// CallersFrames expands inlined calls into logical frames,
+	// so there may be more frames than program counters.
+	frames := runtime.CallersFrames(s.pcs)
+	for {
+		frame, more := frames.Next()
+		s.frames = append(s.frames, Frame{
+			PC:       frame.PC,
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+		if !more {
+			break
+		}
+	}
+}
+
+// Frames returns the frames of this stack, innermost first.
+func (s *Stack) Frames() []Frame {
+	s.once.Do(s.resolve)
+	return s.frames
+}
+
+// RelFile returns the source file relative to the import path of
+// the package containing the function, e.g. "mypkg/server.go".
+// If the function is unknown, File is returned unchanged.
+func (f Frame) RelFile() string {
+	if f.Function == "" || f.File == "" {
+		return f.File
+	}
+	return f.pkg() + "/" + path.Base(filepath.ToSlash(f.File))
+}
+
+// pkg returns the import path of the package containing the function.
+func (f Frame) pkg() string {
+	pkg := f.Function
+	slash := strings.LastIndex(pkg, "/")
+	if dot := strings.Index(pkg[slash+1:], "."); dot >= 0 {
+		pkg = pkg[:slash+1+dot]
+	}
+	return pkg
+}
+
+// file returns the source file as it should be rendered.
+func (f Frame) file() string {
+	if trimPathsEnabled() {
+		return f.RelFile()
+	}
+	return f.File
+}
+
+// String formats the frame as "file:line" followed by
+// the function name on an indented line.
+func (f Frame) String() string {
+	var b strings.Builder
+	f.writeTo(&b)
+	return b.String()
+}
+
+func (f Frame) writeTo(b *strings.Builder) {
+	b.WriteString(f.file())
+	b.WriteByte(':')
+	b.WriteString(strconv.Itoa(f.Line))
+	b.WriteString("\n\t")
+	b.WriteString(f.Function)
+	b.WriteByte('\n')
+}
+
+// String formats every frame in the stack.
+func (s *Stack) String() string {
+	var b strings.Builder
+	s.writeTo(&b)
+	return b.String()
+}
+
+func (s *Stack) writeTo(b *strings.Builder) {
+	for _, frame := range s.Frames() {
+		frame.writeTo(b)
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+// A stack is serialized as an array of frames.
+func (s *Stack) MarshalJSON() ([]byte, error) {
+	frames := s.Frames()
+	if frames == nil {
+		return []byte("[]"), nil
+	}
+	if trimPathsEnabled() {
+		trimmed := make([]Frame, len(frames))
+		for i, frame := range frames {
+			frame.File = frame.RelFile()
+			trimmed[i] = frame
+		}
+		frames = trimmed
+	}
+	return json.Marshal(frames)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Stack) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &s.frames)
+}
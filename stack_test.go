@@ -0,0 +1,138 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"encoding/json"
+	gc "github.com/motain/gocheck"
+	"strings"
+)
+
+func (t *TestSuite) TestStackFrames(c *gc.C) {
+	err := New(0, "ergo", EMyError0)
+	c.Assert(err.Stack, gc.NotNil)
+	c.Check(err.Context, gc.Equals, "")
+	frames := err.Stack.Frames()
+	c.Assert(len(frames) > 1, gc.Equals, true)
+	c.Check(frames[0].Function, gc.Matches, ".*TestStackFrames$")
+	c.Check(strings.HasSuffix(frames[0].File, "stack_test.go"), gc.Equals, true)
+	c.Check(frames[0].Line > 0, gc.Equals, true)
+	c.Check(frames[0].PC, gc.Not(gc.Equals), uintptr(0))
+
+	lines := strings.Split(err.Error(), "\n")
+	c.Check(lines[1], gc.Matches, ".*stack_test.go:[0-9]+$")
+	c.Check(lines[2], gc.Matches, "\t.*TestStackFrames$")
+}
+
+func (t *TestSuite) TestStackLazy(c *gc.C) {
+	err := New(0, "ergo", EMyError0)
+	c.Check(err.Stack.frames, gc.IsNil)
+	c.Check(len(err.Stack.pcs) > 0, gc.Equals, true)
+	frames := err.Stack.Frames()
+	c.Check(len(frames) >= len(err.Stack.pcs), gc.Equals, true)
+	c.Check(frames[0].Function, gc.Matches, ".*TestStackLazy$")
+}
+
+// inlinedError is small enough to be inlined into its callers.
+func inlinedError() *Error {
+	return New(0, "ergo", EMyError0)
+}
+
+func (t *TestSuite) TestStackInlined(c *gc.C) {
+	err := inlinedError()
+	frames := err.Stack.Frames()
+	c.Assert(len(frames) > 1, gc.Equals, true)
+	c.Check(frames[0].Function, gc.Matches, ".*inlinedError$")
+	c.Check(frames[1].Function, gc.Matches, ".*TestStackInlined$")
+	c.Check(frames[0].Line < frames[1].Line, gc.Equals, true)
+}
+
+func (t *TestSuite) BenchmarkNew(c *gc.C) {
+	for i := 0; i < c.N; i++ {
+		New(0, "ergo", EMyError0)
+	}
+}
+
+func (t *TestSuite) BenchmarkNewAndFormat(c *gc.C) {
+	for i := 0; i < c.N; i++ {
+		_ = New(0, "ergo", EMyError0).Error()
+	}
+}
+
+func (t *TestSuite) TestStackJSON(c *gc.C) {
+	stack := NewStack([]Frame{
+		{PC: 1, Function: "main.main", File: "main.go", Line: 7},
+	})
+	data, err := json.Marshal(stack)
+	c.Assert(err, gc.IsNil)
+	c.Check(string(data), gc.Equals, `[{"Function":"main.main","File":"main.go","Line":7}]`)
+
+	var decoded Stack
+	c.Assert(json.Unmarshal(data, &decoded), gc.IsNil)
+	c.Check(decoded.Frames(), gc.DeepEquals, []Frame{
+		{Function: "main.main", File: "main.go", Line: 7},
+	})
+	c.Check(decoded.String(), gc.Equals, "main.go:7\n\tmain.main\n")
+}
+
+func (t *TestSuite) TestContext(c *gc.C) {
+	err := &Error{Domain: "ergo", Code: EMyError1, Context: "main.cpp:42"}
+	lines := strings.Split(err.Error(), "\n")
+	c.Check(lines[0], gc.Equals, "[ergo:1] My error 1")
+	c.Check(lines[1], gc.Equals, "main.cpp:42")
+}
+
+func (t *TestSuite) TestRelFile(c *gc.C) {
+	frame := Frame{
+		Function: "github.com/flaub/ergo.(*TestSuite).TestRelFile",
+		File:     "/home/ci/build/ergo/stack_test.go",
+		Line:     42,
+	}
+	c.Check(frame.RelFile(), gc.Equals, "github.com/flaub/ergo/stack_test.go")
+
+	frame = Frame{Function: "main.main", File: "/src/server.go"}
+	c.Check(frame.RelFile(), gc.Equals, "main/server.go")
+
+	frame = Frame{Function: "net/http.(*conn).serve", File: "/usr/lib/go/src/net/http/server.go"}
+	c.Check(frame.RelFile(), gc.Equals, "net/http/server.go")
+
+	frame = Frame{File: "/src/unknown.go"}
+	c.Check(frame.RelFile(), gc.Equals, "/src/unknown.go")
+}
+
+func (t *TestSuite) TestTrimPaths(c *gc.C) {
+	SetTrimPaths(true)
+	defer SetTrimPaths(false)
+
+	err := New(0, "ergo", EMyError0)
+	lines := strings.Split(err.Error(), "\n")
+	c.Check(lines[1], gc.Matches, "github.com/flaub/ergo/stack_test.go:[0-9]+")
+
+	data, jerr := json.Marshal(err.Stack)
+	c.Assert(jerr, gc.IsNil)
+	var frames []Frame
+	c.Assert(json.Unmarshal(data, &frames), gc.IsNil)
+	c.Check(frames[0].File, gc.Equals, "github.com/flaub/ergo/stack_test.go")
+	c.Check(strings.HasPrefix(err.Stack.Frames()[0].File, "/"), gc.Equals, true)
+}
@@ -0,0 +1,142 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MarshalText implements encoding.TextMarshaler.
+// The text is the multi-line layout of Error(): for each link,
+// a "[domain:code] message" header followed by its context.
+func (err *Error) MarshalText() ([]byte, error) {
+	var b strings.Builder
+	b.Grow(err.sizeHint(0))
+	err.writeChain(&b, 0)
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, see ParseText.
+func (err *Error) UnmarshalText(text []byte) error {
+	parsed, perr := ParseText(string(text))
+	if perr != nil {
+		return perr
+	}
+	*err = *parsed
+	return nil
+}
+
+// ParseText reads back an error written by MarshalText or Error().
+// Messages cannot be rendered again without the original Info,
+// so each message is kept in Info["_msg"], except for errors in the
+// "go" domain. Stack traces are restored as frames when possible.
+// Additional Causes are read back as links of the chain.
+// Named codes are resolved with CodeByName.
+func ParseText(text string) (*Error, error) {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil, fmt.Errorf("ergo: empty error text")
+	}
+	blocks := strings.Split(text, "\n\n")
+	if len(blocks) > MaxDecodeDepth {
+		return nil, fmt.Errorf("ergo: chain exceeds %d links", MaxDecodeDepth)
+	}
+	var chain *Error
+	for _, block := range blocks {
+		err, perr := parseBlock(block)
+		if perr != nil {
+			return nil, perr
+		}
+		err.Inner = chain
+		chain = err
+	}
+	return chain, nil
+}
+
+func parseBlock(block string) (*Error, error) {
+	lines := strings.SplitN(block, "\n", 2)
+	header := lines[0]
+	end := strings.Index(header, "]")
+	sep := strings.LastIndex(header[:end+1], ":")
+	if !strings.HasPrefix(header, "[") || sep < 0 {
+		return nil, fmt.Errorf("ergo: invalid error header: %q", header)
+	}
+	code, cerr := strconv.Atoi(header[sep+1 : end])
+	if cerr != nil {
+		named, ok := CodeByName(header[1:sep], header[sep+1:end])
+		if !ok {
+			return nil, fmt.Errorf("ergo: invalid error code: %q", header)
+		}
+		code = int(named)
+	}
+	err := &Error{
+		Domain: header[1:sep],
+		Code:   ErrCode(code),
+		Info:   make(ErrInfo),
+	}
+	msg := strings.TrimPrefix(header[end+1:], " ")
+	if err.Domain == "go" && strings.HasPrefix(msg, "Error: ") {
+		err.Info["_err"] = strings.TrimPrefix(msg, "Error: ")
+	} else {
+		err.Info["_msg"] = msg
+	}
+	if len(lines) > 1 {
+		if frames, ok := parseFrames(lines[1]); ok {
+			err.Stack = NewStack(frames)
+		} else {
+			err.Context = lines[1]
+		}
+	}
+	if verr := validate(err.Domain, err.Code, err.Info); verr != nil {
+		return nil, verr
+	}
+	return err, nil
+}
+
+// parseFrames reads frames formatted as "file:line\n\tfunction".
+func parseFrames(text string) ([]Frame, bool) {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if len(lines)%2 != 0 {
+		return nil, false
+	}
+	frames := make([]Frame, 0, len(lines)/2)
+	for i := 0; i < len(lines); i += 2 {
+		sep := strings.LastIndex(lines[i], ":")
+		if sep < 0 || !strings.HasPrefix(lines[i+1], "\t") {
+			return nil, false
+		}
+		line, err := strconv.Atoi(lines[i][sep+1:])
+		if err != nil {
+			return nil, false
+		}
+		frames = append(frames, Frame{
+			Function: lines[i+1][1:],
+			File:     lines[i][:sep],
+			Line:     line,
+		})
+	}
+	return frames, true
+}
@@ -0,0 +1,69 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	gc "github.com/motain/gocheck"
+	"io"
+)
+
+func (t *TestSuite) TestTextRoundTrip(c *gc.C) {
+	inner := &Error{Domain: "legacy", Code: 3, Info: ErrInfo{"x": 1}, Context: "main.cpp:42"}
+	middle := Chain(inner, Wrap(io.EOF))
+	err := Chain(middle, NewError(EMyErrorArgs, "name", "x")).(*Error)
+
+	text, terr := err.MarshalText()
+	c.Assert(terr, gc.IsNil)
+	c.Check(string(text), gc.Equals, err.Error())
+
+	var parsed Error
+	c.Assert(parsed.UnmarshalText(text), gc.IsNil)
+	c.Check(parsed.Error(), gc.Equals, err.Error())
+	c.Check(parsed.Domain, gc.Equals, "ergo")
+	c.Check(parsed.Code, gc.Equals, EMyErrorArgs)
+	c.Check(parsed.Message(), gc.Equals, "The x failed")
+	c.Check(parsed.Stack.Frames(), gc.HasLen, len(err.Stack.Frames()))
+	c.Check(parsed.Stack.Frames()[0].Function, gc.Matches, ".*TestTextRoundTrip$")
+	c.Check(parsed.Inner.Info, gc.DeepEquals, ErrInfo{"_err": "EOF"})
+	c.Check(parsed.Inner.Message(), gc.Equals, "Error: EOF")
+	c.Check(parsed.Inner.Inner.Context, gc.Equals, "main.cpp:42")
+	c.Check(parsed.Inner.Inner.Message(), gc.Equals, inner.Message())
+}
+
+func (t *TestSuite) TestParseText(c *gc.C) {
+	err, perr := ParseText("[ergo:1] My error 1")
+	c.Assert(perr, gc.IsNil)
+	c.Check(err.Code, gc.Equals, EMyError1)
+	c.Check(err.Context, gc.Equals, "")
+	c.Check(err.Stack, gc.IsNil)
+
+	_, perr = ParseText("")
+	c.Check(perr, gc.ErrorMatches, "ergo: empty error text")
+	_, perr = ParseText("ergo:1 oops")
+	c.Check(perr, gc.ErrorMatches, "ergo: invalid error header: .*")
+	_, perr = ParseText("[ergo:x] oops")
+	c.Check(perr, gc.ErrorMatches, "ergo: invalid error code: .*")
+	_, perr = ParseText("[ergo:42] oops")
+	c.Check(perr, gc.ErrorMatches, "ergo: \\[ergo:42\\] is not defined")
+}
@@ -0,0 +1,54 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package tomlergo registers TOML as a catalog format,
+// see ergo.LoadDomainFile:
+//
+//	import _ "github.com/flaub/ergo/tomlergo"
+//
+// Catalogs are read from files with the .toml extension.
+package tomlergo
+
+import (
+	"errors"
+	"github.com/flaub/ergo"
+	"github.com/pelletier/go-toml/v2"
+	"io"
+)
+
+func init() {
+	ergo.RegisterCatalogFormat("toml", Decode)
+}
+
+// Decode decodes a TOML catalog, rejecting unknown fields.
+func Decode(r io.Reader, cat *ergo.Catalog) error {
+	dec := toml.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(cat)
+	var strict *toml.StrictMissingError
+	if errors.As(err, &strict) {
+		// The details name the unknown fields.
+		return errors.New(strict.String())
+	}
+	return err
+}
@@ -0,0 +1,214 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SchemaVersion is the version of the wire format described by Wire.
+// It is only incremented by incompatible changes.
+const SchemaVersion = 1
+
+// MaxDecodeDepth limits the length of chains accepted when decoding.
+const MaxDecodeDepth = 100
+
+// Wire is the stable wire representation of an Error,
+// shared by every serialization format.
+// Field names and the layout of the chain are guaranteed across releases,
+// so that consumers in other languages can depend on them.
+type Wire struct {
+	// The schema version, only set on the outermost error.
+	// A missing version is treated as version 1.
+	Version int `json:",omitempty"`
+
+	Domain string
+	Code   ErrCode
+
+	// The name of the code, if any, see NameCodes.
+	Name string `json:",omitempty"`
+
+	// Values which cannot be serialized faithfully
+	// are replaced by their string form. Values are redacted, see Redact.
+	Info ErrInfo `json:",omitempty"`
+
+	// Missing for errors without a severity.
+	Severity Severity `json:",omitempty"`
+
+	Context string  `json:",omitempty"`
+	Stack   []Frame `json:",omitempty"`
+	Inner   *Wire   `json:",omitempty"`
+	Causes  []*Wire `json:",omitempty"`
+}
+
+// ToWire converts this error into its wire representation.
+func (err *Error) ToWire() *Wire {
+	wire := err.toWire(0)
+	wire.Version = SchemaVersion
+	return wire
+}
+
+// toWire converts the links of this error at "depth" and below.
+// Links as deep as MaxDecodeDepth are left out, so that the result
+// can always be decoded, and cycles created by assigning Inner or
+// Causes cannot recurse forever.
+func (err *Error) toWire(depth int) *Wire {
+	wire := &Wire{
+		Domain:   err.Domain,
+		Code:     err.Code,
+		Severity: err.Severity,
+		Context:  err.Context,
+	}
+	wire.Name, _ = CodeName(err.Domain, err.Code)
+	if len(err.Info) != 0 {
+		wire.Info = make(ErrInfo, len(err.Info))
+		for key, value := range err.Info {
+			wire.Info[key] = wireValue(key, value)
+		}
+	}
+	if err.Stack != nil {
+		trim := trimPathsEnabled()
+		for _, frame := range err.Stack.Frames() {
+			if trim {
+				frame.File = frame.RelFile()
+			}
+			frame.PC = 0
+			wire.Stack = append(wire.Stack, frame)
+		}
+	}
+	if depth+1 >= MaxDecodeDepth {
+		return wire
+	}
+	if err.Inner != nil {
+		wire.Inner = err.Inner.toWire(depth + 1)
+	}
+	for _, cause := range err.Causes {
+		wire.Causes = append(wire.Causes, cause.toWire(depth+1))
+	}
+	return wire
+}
+
+// wireValue replaces values which serializers cannot represent,
+// and redacts values, see Redact.
+func wireValue(key string, value interface{}) interface{} {
+	value = Redact(key, value)
+	if err, ok := value.(error); ok {
+		return err.Error()
+	}
+	if value == nil {
+		return nil
+	}
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer,
+		reflect.Complex64, reflect.Complex128:
+		return fmt.Sprintf("%v", value)
+	}
+	return value
+}
+
+// ToError reconstructs an error from its wire representation,
+// for example one received from another service. The chain is validated:
+// every link must have a domain, errors in the "go" domain must carry
+// their message, and codes of domains defined with Domain must exist.
+// Of "opts", only WithWrapped applies, to the outermost error,
+// for example to keep the transport error it was decoded from.
+func (wire *Wire) ToError(opts ...Option) (*Error, error) {
+	if wire.Version > SchemaVersion {
+		return nil, fmt.Errorf("ergo: unsupported schema version %d", wire.Version)
+	}
+	err, berr := wire.build(0)
+	if berr != nil {
+		return nil, berr
+	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	err.wrapped = o.wrapped
+	return err, nil
+}
+
+func (wire *Wire) build(depth int) (*Error, error) {
+	if depth >= MaxDecodeDepth {
+		return nil, fmt.Errorf("ergo: chain exceeds %d links", MaxDecodeDepth)
+	}
+	if err := validate(wire.Domain, wire.Code, wire.Info); err != nil {
+		return nil, err
+	}
+	if code, ok := CodeByName(wire.Domain, wire.Name); ok && code != wire.Code {
+		return nil, fmt.Errorf("ergo: [%v:%d] is named %v, but %v is %d",
+			wire.Domain, wire.Code, wire.Name, wire.Name, code)
+	}
+	err := &Error{
+		Domain:   wire.Domain,
+		Code:     wire.Code,
+		Info:     wire.Info,
+		Severity: wire.Severity,
+		Context:  wire.Context,
+	}
+	if err.Info == nil {
+		err.Info = make(ErrInfo)
+	}
+	if wire.Stack != nil {
+		err.Stack = NewStack(wire.Stack)
+	}
+	if wire.Inner != nil {
+		inner, ierr := wire.Inner.build(depth + 1)
+		if ierr != nil {
+			return nil, ierr
+		}
+		err.Inner = inner
+	}
+	for _, cause := range wire.Causes {
+		if cause == nil {
+			return nil, fmt.Errorf("ergo: [%v:%d] has a null cause", err.Domain, err.Code)
+		}
+		built, cerr := cause.build(depth + 1)
+		if cerr != nil {
+			return nil, cerr
+		}
+		err.Causes = append(err.Causes, built)
+	}
+	return err, nil
+}
+
+// validate checks that an error received from elsewhere is consistent
+// with the domains defined in this process.
+func validate(domain string, code ErrCode, info ErrInfo) error {
+	if domain == "" {
+		return fmt.Errorf("ergo: error has no domain")
+	}
+	if domain == "go" {
+		if _, ok := info["_err"].(string); !ok {
+			return fmt.Errorf("ergo: [go:%d] has no message", code)
+		}
+	}
+	if def, ok := defined.Load(domain); ok {
+		if _, ok := def.(*definition).catalog[code]; !ok {
+			return fmt.Errorf("ergo: [%v:%d] is not defined", domain, code)
+		}
+	}
+	return nil
+}
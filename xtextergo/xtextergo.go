@@ -0,0 +1,74 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package xtextergo translates ergo messages through
+// golang.org/x/text/message catalogs, so that translations maintained
+// there can be reused for ergo codes.
+package xtextergo
+
+import (
+	"github.com/flaub/ergo"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Key returns the catalog key of the message of a domain and code,
+// such as "[db:3]".
+func Key(domain string, code ergo.ErrCode) string {
+	return ergo.Code(domain, code).Error()
+}
+
+// Register translates the messages of a domain through a catalog,
+// see LocaleFunc.
+func Register(domain string, cat catalog.Catalog, args map[ergo.ErrCode][]string) {
+	ergo.DomainLocaleFunc(domain, LocaleFunc(cat, args))
+}
+
+// LocaleFunc returns a backend for ergo.DomainLocaleFunc formatting
+// messages with a catalog. The message of each code is looked up by Key,
+// and is passed the Info values named by args[code] as arguments,
+// in order, so that "%[1]s" refers to the first one.
+func LocaleFunc(cat catalog.Catalog, args map[ergo.ErrCode][]string) ergo.LocaleFunc {
+	return func(err *ergo.Error, locale string) (string, bool) {
+		tag, perr := language.Parse(locale)
+		if perr != nil {
+			return "", false
+		}
+		key := Key(err.Domain, err.Code)
+		if cat.Context(tag, discard{}).Execute(key) == catalog.ErrNotFound {
+			return "", false
+		}
+		values := make([]interface{}, len(args[err.Code]))
+		for i, name := range args[err.Code] {
+			values[i] = ergo.Redact(name, err.Info[name])
+		}
+		return message.NewPrinter(tag, message.Catalog(cat)).Sprintf(key, values...), true
+	}
+}
+
+// discard is a renderer used to look up messages without formatting them.
+type discard struct{}
+
+func (discard) Render(string)       {}
+func (discard) Arg(int) interface{} { return nil }
@@ -0,0 +1,79 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package xtextergo
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+	EFiles
+	EBroken
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("xtextergo", ergo.DomainMap{
+		ENotFound: "The {{.name}} was not found",
+		EFiles:    "{{.count}} files failed",
+		EBroken:   "Broken",
+	})
+	cat := catalog.NewBuilder()
+	cat.SetString(language.German, Key("xtextergo", ENotFound), "%[1]s wurde nicht gefunden")
+	cat.Set(language.German, Key("xtextergo", EFiles), plural.Selectf(1, "%d",
+		"one", "Eine Datei ist fehlgeschlagen",
+		"other", "%[1]d Dateien sind fehlgeschlagen",
+	))
+	Register("xtextergo", cat, map[ergo.ErrCode][]string{
+		ENotFound: {"name"},
+		EFiles:    {"count"},
+	})
+}
+
+func (t *TestSuite) TestMessageIn(c *gc.C) {
+	err := ergo.New(0, "xtextergo", ENotFound, "name", "config")
+	c.Check(err.MessageIn("de"), gc.Equals, "config wurde nicht gefunden")
+	c.Check(err.MessageIn("de-AT"), gc.Equals, "config wurde nicht gefunden")
+	c.Check(err.MessageIn("fr"), gc.Equals, "The config was not found")
+
+	c.Check(ergo.New(0, "xtextergo", EFiles, "count", 1).MessageIn("de"),
+		gc.Equals, "Eine Datei ist fehlgeschlagen")
+	c.Check(ergo.New(0, "xtextergo", EFiles, "count", 3).MessageIn("de"),
+		gc.Equals, "3 Dateien sind fehlgeschlagen")
+	c.Check(ergo.New(0, "xtextergo", EBroken).MessageIn("de"), gc.Equals, "Broken")
+}
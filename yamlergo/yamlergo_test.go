@@ -0,0 +1,65 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package yamlergo
+
+import (
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+func (t *TestSuite) TestLoadDomainFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "yamlergo.yml")
+	c.Assert(os.WriteFile(path, []byte(`
+domain: yamlergo
+codes:
+  - code: 1
+    name: ENotFound
+    message: The {{.name}} was not found
+    metadata:
+      status: 404
+  - code: 2
+    message: Broken
+`), 0644), gc.IsNil)
+	cat, err := ergo.LoadDomainFile(path)
+	c.Assert(err, gc.IsNil)
+	c.Check(cat.Codes[0].Metadata["status"], gc.Equals, 404)
+	c.Check(ergo.New(0, "yamlergo", 1, "name", "x").Message(), gc.Equals, "The x was not found")
+	c.Check(ergo.New(0, "yamlergo", 2).Message(), gc.Equals, "Broken")
+
+	var bad ergo.Catalog
+	c.Check(Decode(strings.NewReader("domian: x\n"), &bad), gc.ErrorMatches, "(?s).*field domian not found.*")
+}
@@ -0,0 +1,168 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// Package zapergo logs ergo errors as structured Zap fields.
+package zapergo
+
+import (
+	"github.com/flaub/ergo"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// Error returns a field named "error" which logs an error as a nested object,
+// see Object. A nil error is skipped.
+func Error(err *ergo.Error) zap.Field {
+	return NamedError("error", err)
+}
+
+// NamedError is like Error, with a custom key.
+func NamedError(key string, err *ergo.Error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(key, Object(err))
+}
+
+// Object adapts an error to zapcore.ObjectMarshaler.
+// The object holds the "msg", "domain", "code", "info" and "stack"
+// of the error, with the "causes" and the rest of the "chain"
+// as arrays of the same objects.
+func Object(err *ergo.Error) zapcore.ObjectMarshaler {
+	return (*object)(err)
+}
+
+type object ergo.Error
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (o *object) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if err := o.marshalLink(enc); err != nil {
+		return err
+	}
+	if o.Inner != nil {
+		return enc.AddArray("chain", chain{o.Inner})
+	}
+	return nil
+}
+
+// marshalLink encodes a single link of the chain.
+func (o *object) marshalLink(enc zapcore.ObjectEncoder) error {
+	err := (*ergo.Error)(o)
+	enc.AddString("msg", err.Message())
+	enc.AddString("domain", err.Domain)
+	enc.AddInt("code", int(err.Code))
+	if hasInfo(err.Info) {
+		if ierr := enc.AddObject("info", info(err.Info)); ierr != nil {
+			return ierr
+		}
+	}
+	if err.Stack != nil {
+		enc.AddString("stack", err.Stack.String())
+	} else if err.Context != "" {
+		enc.AddString("stack", err.Context)
+	}
+	if len(err.Causes) != 0 {
+		return enc.AddArray("causes", causes(err.Causes))
+	}
+	return nil
+}
+
+// chain encodes the links following the outermost error.
+type chain struct {
+	err *ergo.Error
+}
+
+func (c chain) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for link := c.err; link != nil; link = link.Inner {
+		if err := enc.AppendObject(linkObject{(*object)(link)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type linkObject struct {
+	*object
+}
+
+func (l linkObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return l.marshalLink(enc)
+}
+
+type causes []*ergo.Error
+
+func (c causes) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, cause := range c {
+		if err := enc.AppendObject((*object)(cause)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// info encodes Info without the reserved keys,
+// which are already part of the message.
+type info ergo.ErrInfo
+
+func hasInfo(i ergo.ErrInfo) bool {
+	for key := range i {
+		if !ergo.IsReserved(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i info) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, key := range ergo.ErrInfo(i).Keys() {
+		if ergo.IsReserved(key) {
+			continue
+		}
+		switch value := ergo.Redact(key, i[key]).(type) {
+		case string:
+			enc.AddString(key, value)
+		case bool:
+			enc.AddBool(key, value)
+		case int:
+			enc.AddInt(key, value)
+		case int64:
+			enc.AddInt64(key, value)
+		case uint64:
+			enc.AddUint64(key, value)
+		case float64:
+			enc.AddFloat64(key, value)
+		case time.Time:
+			enc.AddTime(key, value)
+		case time.Duration:
+			enc.AddDuration(key, value)
+		case error:
+			enc.AddString(key, value.Error())
+		default:
+			if err := enc.AddReflected(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
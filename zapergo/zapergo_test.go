@@ -0,0 +1,108 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package zapergo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/flaub/ergo"
+	gc "github.com/motain/gocheck"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type TestSuite struct {
+}
+
+var (
+	_ = gc.Suite(new(TestSuite))
+)
+
+const (
+	ENotFound = ergo.ErrCode(iota)
+	EBroken
+)
+
+func (t *TestSuite) SetUpSuite(c *gc.C) {
+	ergo.Domain("zapergo", ergo.DomainMap{
+		ENotFound: "The {{.name}} was not found",
+		EBroken:   "Broken",
+	})
+}
+
+func logJSON(c *gc.C, fields ...zap.Field) map[string]interface{} {
+	var buf bytes.Buffer
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.TimeKey = ""
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(cfg), zapcore.AddSync(&buf), zap.DebugLevel)
+	zap.New(core).Info("failed", fields...)
+	var entry map[string]interface{}
+	c.Assert(json.Unmarshal(buf.Bytes(), &entry), gc.IsNil)
+	return entry
+}
+
+func (t *TestSuite) TestError(c *gc.C) {
+	inner := ergo.New(0, "zapergo", ENotFound, "name", "user", "id", 7, ergo.WithStackDepth(0))
+	inner.Causes = []*ergo.Error{{Domain: "zapergo", Code: EBroken, Context: "main.cpp:42"}}
+	outer := ergo.New(0, "zapergo", EBroken, "_secret", fmt.Errorf("x"))
+	err := ergo.Chain(inner, outer).(*ergo.Error)
+
+	entry := logJSON(c, Error(err))
+	object := entry["error"].(map[string]interface{})
+	c.Check(object["msg"], gc.Equals, "Broken")
+	c.Check(object["domain"], gc.Equals, "zapergo")
+	c.Check(object["code"], gc.Equals, float64(EBroken))
+	c.Check(object["info"], gc.DeepEquals, map[string]interface{}{"_secret": "x"})
+	c.Check(object["stack"], gc.Matches, "(?s).*TestError.*")
+	c.Check(object["chain"], gc.DeepEquals, []interface{}{
+		map[string]interface{}{
+			"msg":    "The user was not found",
+			"domain": "zapergo",
+			"code":   float64(ENotFound),
+			"info":   map[string]interface{}{"id": float64(7), "name": "user"},
+			"causes": []interface{}{
+				map[string]interface{}{
+					"msg":    "Broken",
+					"domain": "zapergo",
+					"code":   float64(EBroken),
+					"stack":  "main.cpp:42",
+				},
+			},
+		},
+	})
+
+	wrapped := ergo.Wrap(fmt.Errorf("disk full"), ergo.WithStackDepth(0))
+	entry = logJSON(c, NamedError("cause", wrapped), Error(nil))
+	c.Check(entry["cause"], gc.DeepEquals, map[string]interface{}{
+		"msg":    "Error: disk full",
+		"domain": "go",
+		"code":   float64(0),
+	})
+	_, ok := entry["error"]
+	c.Check(ok, gc.Equals, false)
+}
@@ -0,0 +1,146 @@
+//go:build zerolog
+
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// This file is only built with the "zerolog" build tag,
+// so that other programs do not depend on zerolog.
+
+// MarshalZerologObject implements zerolog.LogObjectMarshaler,
+// so that log.Err(err) emits the structured chain:
+// the "msg", "domain", "code", "info" and "stack" of the error,
+// with the "causes" and the rest of the "chain" as arrays of
+// the same objects.
+func (err *Error) MarshalZerologObject(e *zerolog.Event) {
+	err.marshalZerolog(e, 0)
+}
+
+// marshalZerolog writes this error, "depth" links deep, and its chain.
+func (err *Error) marshalZerolog(e *zerolog.Event, depth int) {
+	err.marshalZerologLink(e, depth)
+	if err.Inner != nil {
+		e.Array("chain", zerologChain{err.Inner, depth + 1})
+	}
+}
+
+func (err *Error) marshalZerologLink(e *zerolog.Event, depth int) {
+	e.Str("msg", err.Message())
+	e.Str("domain", err.Domain)
+	e.Int("code", int(err.Code))
+	for key := range err.Info {
+		if !IsReserved(key) {
+			e.Object("info", zerologInfo(err.Info))
+			break
+		}
+	}
+	if err.Stack != nil {
+		e.Str("stack", err.Stack.String())
+	} else if err.Context != "" {
+		e.Str("stack", err.Context)
+	}
+	if max := maxDepth(); len(err.Causes) != 0 && (max < 0 || depth+1 < max) {
+		e.Array("causes", zerologCauses{err.Causes, depth + 1})
+	}
+}
+
+type zerologChain struct {
+	err   *Error
+	depth int
+}
+
+func (c zerologChain) MarshalZerologArray(a *zerolog.Array) {
+	links, _ := c.err.appendLinks(nil, c.depth)
+	for i, link := range links {
+		a.Object(zerologLink{link, c.depth + i})
+	}
+}
+
+type zerologLink struct {
+	err   *Error
+	depth int
+}
+
+func (l zerologLink) MarshalZerologObject(e *zerolog.Event) {
+	l.err.marshalZerologLink(e, l.depth)
+}
+
+type zerologCauses struct {
+	causes []*Error
+	depth  int
+}
+
+func (c zerologCauses) MarshalZerologArray(a *zerolog.Array) {
+	for _, cause := range c.causes {
+		a.Object(zerologCause{cause, c.depth})
+	}
+}
+
+type zerologCause struct {
+	err   *Error
+	depth int
+}
+
+func (c zerologCause) MarshalZerologObject(e *zerolog.Event) {
+	c.err.marshalZerolog(e, c.depth)
+}
+
+// zerologInfo omits the reserved keys,
+// which are already part of the message.
+type zerologInfo ErrInfo
+
+func (i zerologInfo) MarshalZerologObject(e *zerolog.Event) {
+	for _, key := range ErrInfo(i).Keys() {
+		if IsReserved(key) {
+			continue
+		}
+		switch value := Redact(key, i[key]).(type) {
+		case string:
+			e.Str(key, value)
+		case bool:
+			e.Bool(key, value)
+		case int:
+			e.Int(key, value)
+		case int64:
+			e.Int64(key, value)
+		case uint64:
+			e.Uint64(key, value)
+		case float64:
+			e.Float64(key, value)
+		case time.Time:
+			e.Time(key, value)
+		case time.Duration:
+			e.Dur(key, value)
+		case error:
+			e.Str(key, value.Error())
+		default:
+			e.Interface(key, value)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+//go:build zerolog
+
+/*
+The MIT License (MIT)
+
+Copyright (c) 2013 Frank Laub
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+package ergo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	gc "github.com/motain/gocheck"
+	"github.com/rs/zerolog"
+)
+
+func (t *TestSuite) TestZerolog(c *gc.C) {
+	inner := New(0, "ergo", EMyErrorArgs, "name", "job", "n", 7, WithStackDepth(0))
+	inner.Causes = []*Error{{Domain: "ergo", Code: EMyError1, Context: "main.cpp:42"}}
+	err := Chain(inner, New(0, "ergo", EMyError0))
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	logger.Error().Err(err).Msg("failed")
+	var entry map[string]interface{}
+	c.Assert(json.Unmarshal(buf.Bytes(), &entry), gc.IsNil)
+	c.Check(entry["message"], gc.Equals, "failed")
+
+	object := entry["error"].(map[string]interface{})
+	c.Check(object["msg"], gc.Equals, "My error 0")
+	c.Check(object["domain"], gc.Equals, "ergo")
+	c.Check(object["code"], gc.Equals, float64(EMyError0))
+	c.Check(object["stack"], gc.Matches, "(?s).*TestZerolog.*")
+	c.Check(object["chain"], gc.DeepEquals, []interface{}{
+		map[string]interface{}{
+			"msg":    "The job failed",
+			"domain": "ergo",
+			"code":   float64(EMyErrorArgs),
+			"info":   map[string]interface{}{"n": float64(7), "name": "job"},
+			"causes": []interface{}{
+				map[string]interface{}{
+					"msg":    "My error 1",
+					"domain": "ergo",
+					"code":   float64(EMyError1),
+					"stack":  "main.cpp:42",
+				},
+			},
+		},
+	})
+
+	buf.Reset()
+	logger.Error().Err(Wrap(fmt.Errorf("disk full"), WithStackDepth(0))).Send()
+	c.Check(buf.String(), gc.Equals,
+		`{"level":"error","error":{"msg":"Error: disk full","domain":"go","code":0}}`+"\n")
+}
+
+func (t *TestSuite) TestZerologCycle(c *gc.C) {
+	defer SetMaxChainDepth(DefaultChainDepth)
+	SetMaxChainDepth(2)
+	err := &Error{Domain: "ergo", Code: EMyError0}
+	err.Causes = []*Error{err}
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	logger.Error().Err(err).Send()
+	c.Check(buf.String(), gc.Equals, `{"level":"error","error":{"msg":"My error 0","domain":"ergo","code":0,`+
+		`"causes":[{"msg":"My error 0","domain":"ergo","code":0}]}}`+"\n")
+}